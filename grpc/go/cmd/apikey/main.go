@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/status"
+
+	"case-studies/grpc/internal/apikey"
+	"case-studies/grpc/internal/config"
+	"case-studies/grpc/internal/validation"
+)
+
+// previousKeySuffix names the entry that keeps a rotated key working during its grace period
+const previousKeySuffix = "-previous"
+
+const usage = `Usage: apikey <command> [flags]
+
+Commands:
+  generate  Create a key, store its hash and print it once
+  list      Show configured keys without their secrets
+  revoke    Disable a key
+  rotate    Replace a key's secret and print the new key once
+  verify    Check a key read from X_API_KEY or stdin
+
+Run "apikey <command> -h" for the flags of each command.
+API_KEY_PEPPER must match the server's. The server picks up changes on SIGHUP.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := map[string]func(args []string) error{
+		"generate": generate,
+		"list":     list,
+		"revoke":   revoke,
+		"rotate":   rotate,
+		"verify":   verify,
+	}
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := command(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// newFlagSet adds the flags every command shares
+func newFlagSet(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	assetsFilePath := config.DefaultAssetsFilePath
+	if env := os.Getenv("ASSETS_FILE_PATH"); env != "" {
+		assetsFilePath = env
+	}
+	configPath := fs.String("config", filepath.Join(assetsFilePath, "api-config.yaml"), "Path to api-config.yaml")
+	return fs, configPath
+}
+
+func generate(args []string) error {
+	fs, configPath := newFlagSet("generate")
+	name := fs.String("name", "", "Unique key name (required)")
+	scopes := fs.String("scopes", config.ScopeMoviesRead, "Comma-separated scopes")
+	notBefore := fs.String("not-before", "", "RFC 3339 time the key becomes valid")
+	expiresIn := fs.Duration("expires-in", 0, "How long the key stays valid (0 never expires)")
+	fs.Parse(args)
+	if *name == "" {
+		return errors.New("-name is required")
+	}
+
+	entry := config.APIKeyConfig{Name: *name, Scopes: splitScopes(*scopes)}
+	if err := validation.ValidateAPIKeyScopes(entry.Scopes); err != nil {
+		return fmt.Errorf("-scopes: %s", status.Convert(err).Message())
+	}
+	if *notBefore != "" {
+		t, err := time.Parse(time.RFC3339, *notBefore)
+		if err != nil {
+			return fmt.Errorf("-not-before: %w", err)
+		}
+		entry.NotBefore = t.UTC()
+	}
+	if *expiresIn > 0 {
+		entry.ExpiresAt = time.Now().Add(*expiresIn).UTC().Truncate(time.Second)
+	}
+
+	file, err := apikey.LoadConfigFile(*configPath)
+	if err != nil {
+		return err
+	}
+	key, hash, err := newKey()
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+	if err := file.Add(entry); err != nil {
+		return err
+	}
+	if err := file.Save(); err != nil {
+		return err
+	}
+
+	printKey(*name, key)
+	return nil
+}
+
+func list(args []string) error {
+	fs, configPath := newFlagSet("list")
+	fs.Parse(args)
+
+	file, err := apikey.LoadConfigFile(*configPath)
+	if err != nil {
+		return err
+	}
+	keys, err := file.Keys()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tSCOPES\tNOT BEFORE\tEXPIRES AT\tSTORED AS")
+	for _, k := range keys {
+		stored := "hash"
+		if k.Key != "" {
+			stored = "plaintext"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", k.Name, keyStatus(k, now), strings.Join(k.GrantedScopes(), ","), formatTime(k.NotBefore), formatTime(k.ExpiresAt), stored)
+	}
+	return w.Flush()
+}
+
+func revoke(args []string) error {
+	fs, configPath := newFlagSet("revoke")
+	name := fs.String("name", "", "Key name (required)")
+	fs.Parse(args)
+	if *name == "" {
+		return errors.New("-name is required")
+	}
+
+	file, err := apikey.LoadConfigFile(*configPath)
+	if err != nil {
+		return err
+	}
+	if err := file.Update(*name, func(k *config.APIKeyConfig) { k.Disabled = true }); err != nil {
+		return err
+	}
+	if err := file.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Revoked %s\n", *name)
+	return nil
+}
+
+func rotate(args []string) error {
+	fs, configPath := newFlagSet("rotate")
+	name := fs.String("name", "", "Key name (required)")
+	grace := fs.Duration("grace", 0, "Keep the old key working for this long as <name>-previous")
+	fs.Parse(args)
+	if *name == "" {
+		return errors.New("-name is required")
+	}
+
+	file, err := apikey.LoadConfigFile(*configPath)
+	if err != nil {
+		return err
+	}
+	keys, err := file.Keys()
+	if err != nil {
+		return err
+	}
+	var current *config.APIKeyConfig
+	for i := range keys {
+		if keys[i].Name == *name {
+			current = &keys[i]
+		}
+	}
+	if current == nil {
+		return fmt.Errorf("%w: %s", apikey.ErrKeyNotFound, *name)
+	}
+
+	if *grace > 0 {
+		previous := *current
+		previous.Name = *name + previousKeySuffix
+		if previous.Key != "" {
+			// Never write the old plaintext back out
+			if previous.Hash, err = apikey.Hash(previous.Key, os.Getenv("API_KEY_PEPPER")); err != nil {
+				return err
+			}
+			previous.Key = ""
+		}
+		if graceEnd := time.Now().Add(*grace).UTC().Truncate(time.Second); previous.ExpiresAt.IsZero() || graceEnd.Before(previous.ExpiresAt) {
+			previous.ExpiresAt = graceEnd
+		}
+		if err := file.Remove(previous.Name); err != nil && !errors.Is(err, apikey.ErrKeyNotFound) {
+			return err
+		}
+		if err := file.Add(previous); err != nil {
+			return err
+		}
+	}
+
+	key, hash, err := newKey()
+	if err != nil {
+		return err
+	}
+	if err := file.Update(*name, func(k *config.APIKeyConfig) {
+		k.Key = ""
+		k.Hash = hash
+	}); err != nil {
+		return err
+	}
+	if err := file.Save(); err != nil {
+		return err
+	}
+
+	printKey(*name, key)
+	return nil
+}
+
+func verify(args []string) error {
+	fs, configPath := newFlagSet("verify")
+	fs.Parse(args)
+
+	// The key is never taken as a flag so it stays out of shell history and the process list
+	key := os.Getenv("X_API_KEY")
+	if key == "" {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return errors.New("no key given: set X_API_KEY or pipe the key on stdin")
+		}
+		key = strings.TrimSpace(line)
+	}
+
+	file, err := apikey.LoadConfigFile(*configPath)
+	if err != nil {
+		return err
+	}
+	keys, err := file.Keys()
+	if err != nil {
+		return err
+	}
+	authenticator, err := apikey.NewAuthenticator(keys, os.Getenv("API_KEY_PEPPER"), time.Now)
+	if err != nil {
+		return err
+	}
+	principal, err := authenticator.Authenticate(key)
+	if err != nil {
+		if principal.Name != "" {
+			return fmt.Errorf("%s: %w", principal.Name, err)
+		}
+		return err
+	}
+	fmt.Printf("Valid key %s with scopes %s\n", principal.Name, strings.Join(principal.Scopes, ","))
+	return nil
+}
+
+func newKey() (key, hash string, err error) {
+	if key, err = apikey.Generate(); err != nil {
+		return "", "", err
+	}
+	if hash, err = apikey.Hash(key, os.Getenv("API_KEY_PEPPER")); err != nil {
+		return "", "", err
+	}
+	return key, hash, nil
+}
+
+func printKey(name, key string) {
+	fmt.Printf("API key for %s (shown once, only its hash is stored):\n%s\n", name, key)
+}
+
+func keyStatus(k config.APIKeyConfig, now time.Time) string {
+	switch apikey.CheckValidity(k, now) {
+	case nil:
+		return "active"
+	case apikey.ErrKeyDisabled:
+		return "revoked"
+	case apikey.ErrKeyNotYetValid:
+		return "pending"
+	default:
+		return "expired"
+	}
+}
+
+func splitScopes(scopes string) []string {
+	var result []string
+	for _, s := range strings.Split(scopes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpc_health_v1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"case-studies/grpc/internal/config"
+	"case-studies/grpc/internal/movie/client"
+)
+
+// Exit codes, so probes and scripts can tell why a check failed
+const (
+	exitServing     = 0
+	exitUsage       = 1
+	exitUnreachable = 2
+	exitNotServing  = 3
+	exitTLSFailure  = 4
+	exitAuthFailure = 5
+)
+
+const usage = `Usage: healthcheck [flags]
+
+Checks grpc.health.v1.Health on a server, over mutual TLS unless TLS_MODE or -plaintext
+says otherwise. SERVER_HOST, SERVER_PORT, ASSETS_FILE_PATH, the TLS_* variables and the
+client_tls section of api-config.yaml set the defaults. The movie server does not ask
+Health callers for an API key.
+
+Exit codes:
+  0  every service is SERVING, or -h printed this help
+  1  invalid flags
+  2  the server could not be reached in time
+  3  a service is not SERVING or is unknown
+  4  TLS failed: client certificates could not be loaded or the handshake was refused
+  5  the server refused the caller: UNAUTHENTICATED or PERMISSION_DENIED
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], config.LoadMovieClientConfig(), os.Stdout, os.Stderr))
+}
+
+// run parses args over the defaults in cfg and checks each service, returning the exit code
+func run(args []string, cfg *config.MovieClientConfig, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("healthcheck", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	host := fs.String("host", cfg.Host, "The server host to check")
+	port := fs.Int("port", cfg.Port, "The server port to check")
+	assetsFilePath := fs.String("assets-file-path", cfg.AssetsFilePath, "The file path holding tls/ client certificates")
+	services := fs.String("services", "", "Comma-separated services to check, such as liveness,readiness; empty checks the whole server")
+	timeout := fs.Duration("timeout", 5*time.Second, "How long all the checks may take")
+	plaintext := fs.Bool("plaintext", false, "Connect without TLS, as the helloworld server expects; the same as TLS_MODE=insecure")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitServing
+		}
+		return exitUsage
+	}
+
+	tlsCfg := cfg.TLS
+	if *assetsFilePath != cfg.AssetsFilePath {
+		tlsCfg = config.LoadClientTLSConfig(*assetsFilePath)
+	}
+	if *plaintext {
+		tlsCfg.Mode = config.TLSModeInsecure
+	}
+
+	return check(net.JoinHostPort(*host, strconv.Itoa(*port)), *assetsFilePath, tlsCfg, splitServices(*services), *timeout, stdout, stderr)
+}
+
+func check(address, assetsFilePath string, tlsCfg config.TLSConfig, services []string, timeout time.Duration, stdout, stderr io.Writer) int {
+	creds, err := client.TransportCredentials(assetsFilePath, tlsCfg)
+	if err != nil {
+		fmt.Fprintln(stderr, "error: could not load client TLS material:", err)
+		return exitTLSFailure
+	}
+	recorder := newTLSFailureRecorder(creds)
+
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(recorder))
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return exitUsage
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	healthClient := grpc_health_v1.NewHealthClient(conn)
+	for _, service := range services {
+		resp, err := healthClient.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: service})
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %s: %v\n", displayName(service), err)
+			return exitCode(err, recorder.Failure())
+		}
+		fmt.Fprintf(stdout, "%s: %s\n", displayName(service), resp.GetStatus())
+		if resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+			return exitNotServing
+		}
+	}
+	return exitServing
+}
+
+// exitCode classifies a failed check. gRPC reports connection failures only as UNAVAILABLE, so
+// tlsFailure, the TLS error the connection ran into if any, tells TLS failures apart.
+func exitCode(err, tlsFailure error) int {
+	s := status.Convert(err)
+	switch {
+	case tlsFailure != nil:
+		return exitTLSFailure
+	case s.Code() == codes.Unavailable || s.Code() == codes.DeadlineExceeded:
+		return exitUnreachable
+	case s.Code() == codes.Unauthenticated || s.Code() == codes.PermissionDenied:
+		return exitAuthFailure
+	default:
+		return exitNotServing
+	}
+}
+
+func splitServices(services string) []string {
+	var result []string
+	for _, s := range strings.Split(services, ",") {
+		result = append(result, strings.TrimSpace(s))
+	}
+	return result
+}
+
+func displayName(service string) string {
+	if service == "" {
+		return "server"
+	}
+	return service
+}
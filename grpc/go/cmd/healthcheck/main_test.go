@@ -0,0 +1,274 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	grpc_health_v1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"case-studies/grpc/internal/config"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create CA certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("could not parse CA certificate: %v", err)
+	}
+	return testCA{cert: cert, key: key}
+}
+
+func (ca testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+func (ca testCA) certPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})
+}
+
+// issue signs a certificate for 127.0.0.1, returning it with its certificate and key PEM
+func (ca testCA) issue(t *testing.T, cn string) (tls.Certificate, []byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not encode key: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("could not load certificate: %v", err)
+	}
+	return cert, certPEM, keyPEM
+}
+
+// writeClientAssets writes the client certificate issued by issuer and the CA trusted to verify
+// the server into the tls directory of a new assets path
+func writeClientAssets(t *testing.T, issuer, trusted testCA) string {
+	t.Helper()
+	assetsFilePath := t.TempDir()
+	tlsDir := filepath.Join(assetsFilePath, "tls")
+	if err := os.MkdirAll(tlsDir, 0o755); err != nil {
+		t.Fatalf("could not create tls directory: %v", err)
+	}
+	_, certPEM, keyPEM := issuer.issue(t, "healthcheck")
+	files := map[string][]byte{
+		"client-public.key":  certPEM,
+		"client-private.key": keyPEM,
+		"ca-public.key":      trusted.certPEM(),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tlsDir, name), content, 0o600); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+	}
+	return assetsFilePath
+}
+
+// startHealthServer serves grpc.health.v1.Health with a SERVING liveness service and a NOT_SERVING
+// readiness service, returning its port
+func startHealthServer(t *testing.T, creds credentials.TransportCredentials) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("liveness", grpc_health_v1.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("readiness", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	server := grpc.NewServer(grpc.Creds(creds))
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func closedPort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	return port
+}
+
+func TestRun(t *testing.T) {
+	serverCA := newTestCA(t)
+	otherCA := newTestCA(t)
+	serverCert, _, _ := serverCA.issue(t, "movie-server")
+
+	plaintextPort := startHealthServer(t, insecure.NewCredentials())
+	tlsPort := startHealthServer(t, credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{serverCert}}))
+	mtlsPort := startHealthServer(t, credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    serverCA.pool(),
+	}))
+
+	trustedAssets := writeClientAssets(t, serverCA, serverCA)
+	untrustedServerAssets := writeClientAssets(t, serverCA, otherCA)
+	refusedClientAssets := writeClientAssets(t, otherCA, serverCA)
+
+	tests := []struct {
+		name           string
+		args           []string
+		mode           string
+		assetsFilePath string
+		expectedCode   int
+	}{
+		{name: "help", args: []string{"-h"}, expectedCode: exitServing},
+		{name: "unknown flag", args: []string{"-verbose"}, expectedCode: exitUsage},
+		{name: "invalid timeout", args: []string{"-timeout", "soon"}, expectedCode: exitUsage},
+		{name: "serving server", args: []string{"-plaintext", "-port", strconv.Itoa(plaintextPort)}, expectedCode: exitServing},
+		{name: "serving services", args: []string{"-plaintext", "-port", strconv.Itoa(plaintextPort), "-services", "liveness, "}, expectedCode: exitServing},
+		{name: "service not serving", args: []string{"-plaintext", "-port", strconv.Itoa(plaintextPort), "-services", "liveness,readiness"}, expectedCode: exitNotServing},
+		{name: "unknown service", args: []string{"-plaintext", "-port", strconv.Itoa(plaintextPort), "-services", "catalogue"}, expectedCode: exitNotServing},
+		{name: "unreachable server", args: []string{"-plaintext", "-port", strconv.Itoa(closedPort(t))}, expectedCode: exitUnreachable},
+		{name: "serving over TLS", args: []string{"-port", strconv.Itoa(tlsPort)}, mode: config.TLSModeTLS, assetsFilePath: trustedAssets, expectedCode: exitServing},
+		{name: "serving over mutual TLS", args: []string{"-port", strconv.Itoa(mtlsPort)}, mode: config.TLSModeMTLS, assetsFilePath: trustedAssets, expectedCode: exitServing},
+		{name: "untrusted server certificate", args: []string{"-port", strconv.Itoa(tlsPort)}, mode: config.TLSModeTLS, assetsFilePath: untrustedServerAssets, expectedCode: exitTLSFailure},
+		{name: "refused client certificate", args: []string{"-port", strconv.Itoa(mtlsPort)}, mode: config.TLSModeMTLS, assetsFilePath: refusedClientAssets, expectedCode: exitTLSFailure},
+		{name: "TLS to a plaintext server", args: []string{"-port", strconv.Itoa(plaintextPort)}, mode: config.TLSModeTLS, assetsFilePath: trustedAssets, expectedCode: exitTLSFailure},
+		{name: "missing client certificates", args: []string{"-port", strconv.Itoa(mtlsPort)}, mode: config.TLSModeMTLS, assetsFilePath: t.TempDir(), expectedCode: exitTLSFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			cfg := &config.MovieClientConfig{
+				ClientConfig:   config.ClientConfig{Host: "127.0.0.1"},
+				AssetsFilePath: tt.assetsFilePath,
+				TLS:            config.TLSConfig{Mode: tt.mode, MinVersion: "1.2"},
+			}
+
+			// When
+			code := run(append(tt.args, "-timeout", "2s"), cfg, io.Discard, io.Discard)
+
+			// Then
+			if code != tt.expectedCode {
+				t.Errorf("Given %s, When running the health check, Then expected exit code %d, got %d", tt.name, tt.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	verificationErr := &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}
+
+	tests := []struct {
+		name         string
+		err          error
+		tlsFailure   error
+		expectedCode int
+	}{
+		{name: "unreachable", err: status.Error(codes.Unavailable, "connection refused"), expectedCode: exitUnreachable},
+		{name: "timed out", err: status.Error(codes.DeadlineExceeded, "context deadline exceeded"), expectedCode: exitUnreachable},
+		{name: "TLS failure", err: status.Error(codes.Unavailable, "authentication handshake failed"), tlsFailure: verificationErr, expectedCode: exitTLSFailure},
+		{name: "unauthenticated", err: status.Error(codes.Unauthenticated, "missing credentials"), expectedCode: exitAuthFailure},
+		{name: "permission denied", err: status.Error(codes.PermissionDenied, "not allowed"), expectedCode: exitAuthFailure},
+		{name: "unknown service", err: status.Error(codes.NotFound, "unknown service"), expectedCode: exitNotServing},
+		{name: "error text naming TLS", err: status.Error(codes.Unavailable, "tls: x509: made up"), expectedCode: exitUnreachable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			code := exitCode(tt.err, tt.tlsFailure)
+
+			// Then
+			if code != tt.expectedCode {
+				t.Errorf("Given %s, When classifying the error, Then expected exit code %d, got %d", tt.name, tt.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestIsTLSFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "certificate verification", err: fmt.Errorf("handshake: %w", &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}), expected: true},
+		{name: "alert from the server", err: &net.OpError{Op: "remote error", Err: errors.New("tls: bad certificate")}, expected: true},
+		{name: "peer not speaking TLS", err: tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, expected: true},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, expected: false},
+		{name: "connection closed", err: io.EOF, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			got := isTLSFailure(tt.err)
+
+			// Then
+			if got != tt.expected {
+				t.Errorf("Given %s, When checking for a TLS failure, Then expected %v, got %v", tt.name, tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync/atomic"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// tlsFailureRecorder keeps the last TLS error its connections ran into, since gRPC passes on only
+// the error's text in the UNAVAILABLE status of a failed call
+type tlsFailureRecorder struct {
+	credentials.TransportCredentials
+	failure *atomic.Pointer[error]
+}
+
+func newTLSFailureRecorder(creds credentials.TransportCredentials) *tlsFailureRecorder {
+	return &tlsFailureRecorder{TransportCredentials: creds, failure: new(atomic.Pointer[error])}
+}
+
+func (r *tlsFailureRecorder) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, authInfo, err := r.TransportCredentials.ClientHandshake(ctx, authority, rawConn)
+	if err != nil {
+		r.record(err)
+		return nil, nil, err
+	}
+	return &tlsFailureConn{Conn: conn, recorder: r}, authInfo, nil
+}
+
+func (r *tlsFailureRecorder) Clone() credentials.TransportCredentials {
+	return &tlsFailureRecorder{TransportCredentials: r.TransportCredentials.Clone(), failure: r.failure}
+}
+
+// Failure returns the last TLS error recorded, or nil
+func (r *tlsFailureRecorder) Failure() error {
+	if err := r.failure.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (r *tlsFailureRecorder) record(err error) {
+	if isTLSFailure(err) {
+		r.failure.Store(&err)
+	}
+}
+
+// tlsFailureConn records alerts arriving after the handshake. Under TLS 1.3 the client finishes
+// its handshake before the server checks the client certificate, so a refusal shows up on read.
+type tlsFailureConn struct {
+	net.Conn
+	recorder *tlsFailureRecorder
+}
+
+func (c *tlsFailureConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if err != nil {
+		c.recorder.record(err)
+	}
+	return n, err
+}
+
+// isTLSFailure reports whether err is the server's certificate failing verification, an alert
+// from the server, such as refusing the client certificate, or a peer not speaking TLS
+func isTLSFailure(err error) bool {
+	var verificationErr *tls.CertificateVerificationError
+	var recordHeaderErr tls.RecordHeaderError
+	// crypto/tls reports alerts it receives as a "remote error" operation
+	var opErr *net.OpError
+	return errors.As(err, &verificationErr) || errors.As(err, &recordHeaderErr) ||
+		(errors.As(err, &opErr) && opErr.Op == "remote error")
+}
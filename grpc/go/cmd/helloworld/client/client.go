@@ -25,7 +25,7 @@ func MakeGreeterRequest(ctx context.Context, client helloworld.GreeterClient, na
 	}
 
 	observability.LogSuccess("greeter-request", "MakeGreeterRequest", map[string]interface{}{
-		"message": response.GetMessage(),
+		"message_length": len(response.GetMessage()),
 	})
 
 	return nil
@@ -91,7 +91,7 @@ func CreateGRPCConnection(cfg *config.HelloWorldClientConfig) (*grpc.ClientConn,
 
 func main() {
 	cfg := loadConfig()
-	observability.SetupLogger(cfg.LogLevel)
+	observability.SetupLogger(cfg.LogLevel, cfg.LogRedactKeys...)
 
 	observability.LogStartup(AppType, AppName, map[string]interface{}{
 		"host":        cfg.Host,
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net"
@@ -15,6 +16,8 @@ import (
 	"case-studies/grpc/internal/config"
 	"case-studies/grpc/internal/middleware"
 	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/readiness"
+	"case-studies/grpc/internal/shutdown"
 	"case-studies/grpc/internal/validation"
 )
 
@@ -46,17 +49,28 @@ func loadConfig() *config.ServerConfig {
 		})
 		os.Exit(1)
 	}
+	if err := validation.ValidateShutdownPeriods(baseConfig.ShutdownDrainPeriod, baseConfig.ShutdownTimeout); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "shutdown",
+		})
+		os.Exit(1)
+	}
 
 	return baseConfig
 }
 
-func createGRPCServer(cfg *config.ServerConfig) *grpc.Server {
+func createGRPCServer(cfg *config.ServerConfig) (*grpc.Server, *health.Server) {
 	serverOpts := []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(
 			middleware.LoggingInterceptor(),
 			middleware.ErrorInterceptor(),
 			middleware.RecoveryInterceptor(),
 		),
+		grpc.ChainStreamInterceptor(
+			middleware.LoggingStreamInterceptor(),
+			middleware.ErrorStreamInterceptor(),
+			middleware.RecoveryStreamInterceptor(),
+		),
 	}
 
 	grpcServer := grpc.NewServer(serverOpts...)
@@ -67,7 +81,8 @@ func createGRPCServer(cfg *config.ServerConfig) *grpc.Server {
 	healthServer := health.NewServer()
 	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
 
-	healthServer.SetServingStatus("helloworld.Greeter", grpc_health_v1.HealthCheckResponse_SERVING)
+	// Greeter has no state to check, so it is ready as soon as it is registered
+	readiness.NewMonitor(healthServer, []string{helloworld.Greeter_ServiceDesc.ServiceName})
 
 	reflection.Register(grpcServer)
 
@@ -75,12 +90,12 @@ func createGRPCServer(cfg *config.ServerConfig) *grpc.Server {
 		"service": "helloworld.Greeter",
 	})
 
-	return grpcServer
+	return grpcServer, healthServer
 }
 
 func main() {
 	cfg := loadConfig()
-	observability.SetupLogger(cfg.LogLevel)
+	observability.SetupLogger(cfg.LogLevel, cfg.LogRedactKeys...)
 
 	observability.LogStartup(AppType, AppName, map[string]interface{}{
 		"port":        cfg.Port,
@@ -96,14 +111,32 @@ func main() {
 		os.Exit(1)
 	}
 
-	grpcServer := createGRPCServer(cfg)
+	grpcServer, healthServer := createGRPCServer(cfg)
 
 	observability.LogSuccess("server-listen", "main", map[string]interface{}{
 		"address": lis.Addr(),
 	})
 
-	if err := grpcServer.Serve(lis); err != nil {
+	ctx, stop := shutdown.NotifyContext(context.Background())
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(lis)
+	}()
+
+	select {
+	case err := <-serveErr:
 		observability.LogError("server-serve", "main", err, nil)
 		os.Exit(1)
+	case <-ctx.Done():
 	}
+
+	observability.LogShutdown(AppType, AppName)
+	graceful := shutdown.GRPC(grpcServer, healthServer, shutdown.Options{
+		DrainPeriod: cfg.ShutdownDrainPeriod,
+		Timeout:     cfg.ShutdownTimeout,
+	})
+	observability.LogSuccess("server-stop", "main", map[string]interface{}{
+		"graceful": graceful,
+	})
 }
@@ -16,13 +16,14 @@ type server struct {
 
 func (s *server) SayHello(ctx context.Context, in *helloworld.HelloRequest) (*helloworld.HelloReply, error) {
 	start := time.Now()
+	// Names are personal data, so only their length is logged
 	observability.LogSuccess("greeting-request-start", "SayHello", map[string]interface{}{
-		"name": in.GetName(),
+		"name_length": len(in.GetName()),
 	})
 
 	if err := validation.ValidateName(in.GetName()); err != nil {
 		observability.LogError("validation", "SayHello", err, map[string]interface{}{
-			"name": in.GetName(),
+			"name_length": len(in.GetName()),
 		})
 		return nil, err
 	}
@@ -34,9 +35,10 @@ func (s *server) SayHello(ctx context.Context, in *helloworld.HelloRequest) (*he
 
 	duration := time.Since(start)
 	observability.LogSuccess("greeting-request", "SayHello", map[string]interface{}{
-		"name":     sanitizedName,
-		"message":  message,
-		"duration": duration,
+		// The greeting repeats the name, so only its length is logged
+		"name_length":    len(sanitizedName),
+		"message_length": len(message),
+		"duration":       duration,
 	})
 
 	return response, nil
@@ -6,11 +6,13 @@ import (
 	"os"
 	"path/filepath"
 
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc/metadata"
 
 	"case-studies/grpc/cmd/movie"
 	"case-studies/grpc/internal/movie/client"
 	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/pagination"
 )
 
 const (
@@ -37,7 +39,7 @@ func WriteMoviesToFile(response *movie.GetMovieOutput, filePath string) error {
 
 func main() {
 	cfg := client.LoadConfig()
-	observability.SetupLogger(cfg.LogLevel)
+	observability.SetupLogger(cfg.LogLevel, cfg.LogRedactKeys...)
 
 	observability.LogStartup(AppType, AppName, map[string]interface{}{
 		"host":        cfg.Host,
@@ -46,6 +48,15 @@ func main() {
 	})
 	observability.LogConfig(cfg.LogLevel)
 
+	shutdownTracing, err := observability.SetupTracing(context.Background(), AppName+"-"+AppType, cfg.Tracing.TracesExporter, cfg.Tracing.OTLPEndpoint)
+	if err != nil {
+		observability.LogError("tracing-setup", "main", err, map[string]interface{}{
+			"exporter": cfg.Tracing.TracesExporter,
+		})
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
 	conn, err := client.CreateGRPCConnection(cfg)
 	if err != nil {
 		observability.LogError("grpc-connect", "main", err, nil)
@@ -88,20 +99,45 @@ func main() {
 }
 
 func makeGetterRequest(ctx context.Context, client movie.GetterClient, ratings float32) (*movie.GetMovieOutput, error) {
-	observability.LogSuccess("movie-request-start", "makeGetterRequest", map[string]interface{}{
+	// One parent span so every page request lands in the same trace
+	ctx, span := observability.StartSpan(ctx, "makeGetterRequest", attribute.Float64("movie.minimum_ratings_score", float64(ratings)))
+	defer span.End()
+	// Every page request is sent with the same x-request-id
+	ctx = observability.ContextWithRequestID(ctx, observability.NewRequestID())
+
+	observability.LogSuccessContext(ctx, "movie-request-start", "makeGetterRequest", map[string]interface{}{
 		"ratings": ratings,
 	})
 
 	sanitisedRatingsScore := ratings
-	request := &movie.GetMovieInput{MinimumRatingsScore: sanitisedRatingsScore}
+	response := &movie.GetMovieOutput{}
+	pages := 0
+
+	// Follow next_page_token until the server reports no further pages
+	pageToken := ""
+	for {
+		request := &movie.GetMovieInput{MinimumRatingsScore: sanitisedRatingsScore, PageSize: pagination.DefaultPageSize, PageToken: pageToken}
+
+		page, err := client.GetMoviesByRatings(ctx, request)
+		if err != nil {
+			observability.RecordSpanError(span, err)
+			return nil, fmt.Errorf("could not get movies: %w", err)
+		}
+		pages++
 
-	response, err := client.GetMoviesByRatings(ctx, request)
-	if err != nil {
-		return nil, fmt.Errorf("could not get movies: %w", err)
+		response.Movie = append(response.Movie, page.GetMovie()...)
+		response.TotalSize = page.GetTotalSize()
+
+		pageToken = page.GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
 	}
+	response.MovieCount = int32(len(response.GetMovie()))
 
-	observability.LogSuccess("movie-request", "makeGetterRequest", map[string]interface{}{
+	observability.LogSuccessContext(ctx, "movie-request", "makeGetterRequest", map[string]interface{}{
 		"total_movies": len(response.GetMovie()),
+		"pages":        pages,
 	})
 	return response, nil
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+
+	"case-studies/grpc/internal/config"
+	internalMovie "case-studies/grpc/internal/movie"
+	"case-studies/grpc/internal/observability"
+)
+
+const (
+	AppType = "importer"
+	AppName = "movie"
+)
+
+func main() {
+	assetsFilePath := flag.String("assets-file-path", config.DefaultAssetsFilePath, "The file path for assets")
+	databasePath := flag.String("database-path", "", "SQLite database path (defaults to movie-data.db under the assets file path)")
+	logLevel := flag.String("log-level", config.DefaultLogLevel, "Log level (debug, info, warn, error)")
+	flag.Parse()
+
+	observability.SetupLogger(*logLevel, config.LoadLogRedactKeys()...)
+	observability.LogStartup(AppType, AppName, nil)
+
+	sourcePath := filepath.Join(*assetsFilePath, internalMovie.DefaultMovieDataFileName)
+	if *databasePath == "" {
+		*databasePath = filepath.Join(*assetsFilePath, internalMovie.DefaultMovieDatabaseFileName)
+	}
+
+	f, err := os.Open(sourcePath)
+	if err != nil {
+		observability.LogError("movie-data-open", "main", err, map[string]interface{}{
+			"file_path": sourcePath,
+		})
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	movies, err := internalMovie.DecodeMovies(f)
+	if err != nil {
+		observability.LogError("movie-data-decode", "main", err, map[string]interface{}{
+			"file_path": sourcePath,
+		})
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	repository, err := internalMovie.OpenSQLiteRepository(ctx, *databasePath)
+	if err != nil {
+		os.Exit(1)
+	}
+	defer repository.Close()
+
+	if err := repository.Import(ctx, movies); err != nil {
+		observability.LogError("movie-data-import", "main", err, map[string]interface{}{
+			"database_path": *databasePath,
+		})
+		os.Exit(1)
+	}
+
+	observability.LogSuccess("movie-data-import", "main", map[string]interface{}{
+		"file_path":     sourcePath,
+		"database_path": *databasePath,
+		"total_movies":  len(movies),
+	})
+}
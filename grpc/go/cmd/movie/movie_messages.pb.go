@@ -9,6 +9,7 @@ package movie
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,28 +22,1081 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type GenreMatch int32
+
+const (
+	GenreMatch_GENRE_MATCH_UNSPECIFIED GenreMatch = 0
+	GenreMatch_GENRE_MATCH_ANY         GenreMatch = 1
+	GenreMatch_GENRE_MATCH_ALL         GenreMatch = 2
+)
+
+// Enum value maps for GenreMatch.
+var (
+	GenreMatch_name = map[int32]string{
+		0: "GENRE_MATCH_UNSPECIFIED",
+		1: "GENRE_MATCH_ANY",
+		2: "GENRE_MATCH_ALL",
+	}
+	GenreMatch_value = map[string]int32{
+		"GENRE_MATCH_UNSPECIFIED": 0,
+		"GENRE_MATCH_ANY":         1,
+		"GENRE_MATCH_ALL":         2,
+	}
+)
+
+func (x GenreMatch) Enum() *GenreMatch {
+	p := new(GenreMatch)
+	*p = x
+	return p
+}
+
+func (x GenreMatch) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GenreMatch) Descriptor() protoreflect.EnumDescriptor {
+	return file_movie_messages_proto_enumTypes[0].Descriptor()
+}
+
+func (GenreMatch) Type() protoreflect.EnumType {
+	return &file_movie_messages_proto_enumTypes[0]
+}
+
+func (x GenreMatch) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GenreMatch.Descriptor instead.
+func (GenreMatch) EnumDescriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{0}
+}
+
+type SortField int32
+
+const (
+	SortField_SORT_FIELD_UNSPECIFIED  SortField = 0
+	SortField_SORT_FIELD_RATING       SortField = 1
+	SortField_SORT_FIELD_RELEASE_DATE SortField = 2
+	SortField_SORT_FIELD_TITLE        SortField = 3
+)
+
+// Enum value maps for SortField.
+var (
+	SortField_name = map[int32]string{
+		0: "SORT_FIELD_UNSPECIFIED",
+		1: "SORT_FIELD_RATING",
+		2: "SORT_FIELD_RELEASE_DATE",
+		3: "SORT_FIELD_TITLE",
+	}
+	SortField_value = map[string]int32{
+		"SORT_FIELD_UNSPECIFIED":  0,
+		"SORT_FIELD_RATING":       1,
+		"SORT_FIELD_RELEASE_DATE": 2,
+		"SORT_FIELD_TITLE":        3,
+	}
+)
+
+func (x SortField) Enum() *SortField {
+	p := new(SortField)
+	*p = x
+	return p
+}
+
+func (x SortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_movie_messages_proto_enumTypes[1].Descriptor()
+}
+
+func (SortField) Type() protoreflect.EnumType {
+	return &file_movie_messages_proto_enumTypes[1]
+}
+
+func (x SortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortField.Descriptor instead.
+func (SortField) EnumDescriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{1}
+}
+
+type SortOrder int32
+
+const (
+	SortOrder_SORT_ORDER_UNSPECIFIED SortOrder = 0
+	SortOrder_SORT_ORDER_ASCENDING   SortOrder = 1
+	SortOrder_SORT_ORDER_DESCENDING  SortOrder = 2
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "SORT_ORDER_UNSPECIFIED",
+		1: "SORT_ORDER_ASCENDING",
+		2: "SORT_ORDER_DESCENDING",
+	}
+	SortOrder_value = map[string]int32{
+		"SORT_ORDER_UNSPECIFIED": 0,
+		"SORT_ORDER_ASCENDING":   1,
+		"SORT_ORDER_DESCENDING":  2,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_movie_messages_proto_enumTypes[2].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_movie_messages_proto_enumTypes[2]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{2}
+}
+
+type MovieEventType int32
+
+const (
+	MovieEventType_MOVIE_EVENT_TYPE_UNSPECIFIED MovieEventType = 0
+	MovieEventType_MOVIE_EVENT_TYPE_ADDED       MovieEventType = 1
+	MovieEventType_MOVIE_EVENT_TYPE_MODIFIED    MovieEventType = 2
+	MovieEventType_MOVIE_EVENT_TYPE_DELETED     MovieEventType = 3
+	// Ends the initial snapshot; its revision is the first one a watch can resume from.
+	MovieEventType_MOVIE_EVENT_TYPE_SNAPSHOT_COMPLETE MovieEventType = 4
+)
+
+// Enum value maps for MovieEventType.
+var (
+	MovieEventType_name = map[int32]string{
+		0: "MOVIE_EVENT_TYPE_UNSPECIFIED",
+		1: "MOVIE_EVENT_TYPE_ADDED",
+		2: "MOVIE_EVENT_TYPE_MODIFIED",
+		3: "MOVIE_EVENT_TYPE_DELETED",
+		4: "MOVIE_EVENT_TYPE_SNAPSHOT_COMPLETE",
+	}
+	MovieEventType_value = map[string]int32{
+		"MOVIE_EVENT_TYPE_UNSPECIFIED":       0,
+		"MOVIE_EVENT_TYPE_ADDED":             1,
+		"MOVIE_EVENT_TYPE_MODIFIED":          2,
+		"MOVIE_EVENT_TYPE_DELETED":           3,
+		"MOVIE_EVENT_TYPE_SNAPSHOT_COMPLETE": 4,
+	}
+)
+
+func (x MovieEventType) Enum() *MovieEventType {
+	p := new(MovieEventType)
+	*p = x
+	return p
+}
+
+func (x MovieEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MovieEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_movie_messages_proto_enumTypes[3].Descriptor()
+}
+
+func (MovieEventType) Type() protoreflect.EnumType {
+	return &file_movie_messages_proto_enumTypes[3]
+}
+
+func (x MovieEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MovieEventType.Descriptor instead.
+func (MovieEventType) EnumDescriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{3}
+}
+
 type GetMovieInput struct {
 	state               protoimpl.MessageState `protogen:"open.v1"`
 	MinimumRatingsScore float32                `protobuf:"fixed32,1,opt,name=minimum_ratings_score,json=minimumRatingsScore,proto3" json:"minimum_ratings_score,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// Pagination applies to GetMoviesByRatings only. 0 returns every match in one response, or
+	// uses the server default when page_token is set.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Opaque token from a previous GetMovieOutput.next_page_token.
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMovieInput) Reset() {
+	*x = GetMovieInput{}
+	mi := &file_movie_messages_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMovieInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMovieInput) ProtoMessage() {}
+
+func (x *GetMovieInput) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMovieInput.ProtoReflect.Descriptor instead.
+func (*GetMovieInput) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetMovieInput) GetMinimumRatingsScore() float32 {
+	if x != nil {
+		return x.MinimumRatingsScore
+	}
+	return 0
+}
+
+func (x *GetMovieInput) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetMovieInput) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type GetMovieOutput struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Movie []*Movie               `protobuf:"bytes,1,rep,name=movie,proto3" json:"movie,omitempty"`
+	// Number of movies matching the filter, not only those on this page.
+	MovieCount      int32 `protobuf:"varint,2,opt,name=movie_count,json=movieCount,proto3" json:"movie_count,omitempty"`
+	MovieCountSoFar int32 `protobuf:"varint,3,opt,name=movie_count_so_far,json=movieCountSoFar,proto3" json:"movie_count_so_far,omitempty"`
+	// Empty when there are no further pages.
+	NextPageToken string `protobuf:"bytes,4,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Number of movies matching the filter across all pages; the same as movie_count.
+	TotalSize     int32 `protobuf:"varint,5,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMovieOutput) Reset() {
+	*x = GetMovieOutput{}
+	mi := &file_movie_messages_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMovieOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMovieOutput) ProtoMessage() {}
+
+func (x *GetMovieOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMovieOutput.ProtoReflect.Descriptor instead.
+func (*GetMovieOutput) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetMovieOutput) GetMovie() []*Movie {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+func (x *GetMovieOutput) GetMovieCount() int32 {
+	if x != nil {
+		return x.MovieCount
+	}
+	return 0
+}
+
+func (x *GetMovieOutput) GetMovieCountSoFar() int32 {
+	if x != nil {
+		return x.MovieCountSoFar
+	}
+	return 0
+}
+
+func (x *GetMovieOutput) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetMovieOutput) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type GetMovieRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MovieId       string                 `protobuf:"bytes,1,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMovieRequest) Reset() {
+	*x = GetMovieRequest{}
+	mi := &file_movie_messages_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMovieRequest) ProtoMessage() {}
+
+func (x *GetMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMovieRequest.ProtoReflect.Descriptor instead.
+func (*GetMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetMovieRequest) GetMovieId() string {
+	if x != nil {
+		return x.MovieId
+	}
+	return ""
+}
+
+type BatchGetMoviesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MovieIds      []string               `protobuf:"bytes,1,rep,name=movie_ids,json=movieIds,proto3" json:"movie_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetMoviesRequest) Reset() {
+	*x = BatchGetMoviesRequest{}
+	mi := &file_movie_messages_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetMoviesRequest) ProtoMessage() {}
+
+func (x *BatchGetMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetMoviesRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchGetMoviesRequest) GetMovieIds() []string {
+	if x != nil {
+		return x.MovieIds
+	}
+	return nil
+}
+
+type BatchGetMoviesResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Movie           []*Movie               `protobuf:"bytes,1,rep,name=movie,proto3" json:"movie,omitempty"`
+	MissingMovieIds []string               `protobuf:"bytes,2,rep,name=missing_movie_ids,json=missingMovieIds,proto3" json:"missing_movie_ids,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BatchGetMoviesResponse) Reset() {
+	*x = BatchGetMoviesResponse{}
+	mi := &file_movie_messages_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetMoviesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetMoviesResponse) ProtoMessage() {}
+
+func (x *BatchGetMoviesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetMoviesResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetMoviesResponse) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchGetMoviesResponse) GetMovie() []*Movie {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+func (x *BatchGetMoviesResponse) GetMissingMovieIds() []string {
+	if x != nil {
+		return x.MissingMovieIds
+	}
+	return nil
+}
+
+type SearchMoviesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Genres are matched case-insensitively; genre_match defaults to ANY.
+	Genres     []string   `protobuf:"bytes,1,rep,name=genres,proto3" json:"genres,omitempty"`
+	GenreMatch GenreMatch `protobuf:"varint,2,opt,name=genre_match,json=genreMatch,proto3,enum=movie.GenreMatch" json:"genre_match,omitempty"`
+	// Inclusive release date bounds in YYYY-MM-DD format.
+	ReleasedAfter  string `protobuf:"bytes,3,opt,name=released_after,json=releasedAfter,proto3" json:"released_after,omitempty"`
+	ReleasedBefore string `protobuf:"bytes,4,opt,name=released_before,json=releasedBefore,proto3" json:"released_before,omitempty"`
+	// Name filters are case-insensitive substring matches.
+	DirectorName string `protobuf:"bytes,5,opt,name=director_name,json=directorName,proto3" json:"director_name,omitempty"`
+	ActorName    string `protobuf:"bytes,6,opt,name=actor_name,json=actorName,proto3" json:"actor_name,omitempty"`
+	// Crew role is a case-insensitive exact match, e.g. "Cinematographer".
+	CrewRole            string   `protobuf:"bytes,7,opt,name=crew_role,json=crewRole,proto3" json:"crew_role,omitempty"`
+	MinimumRatingsScore *float32 `protobuf:"fixed32,8,opt,name=minimum_ratings_score,json=minimumRatingsScore,proto3,oneof" json:"minimum_ratings_score,omitempty"`
+	MaximumRatingsScore *float32 `protobuf:"fixed32,9,opt,name=maximum_ratings_score,json=maximumRatingsScore,proto3,oneof" json:"maximum_ratings_score,omitempty"`
+	// Defaults to rating; order defaults to descending for rating and release date, ascending for title.
+	SortBy    SortField `protobuf:"varint,10,opt,name=sort_by,json=sortBy,proto3,enum=movie.SortField" json:"sort_by,omitempty"`
+	SortOrder SortOrder `protobuf:"varint,11,opt,name=sort_order,json=sortOrder,proto3,enum=movie.SortOrder" json:"sort_order,omitempty"`
+	// 0 uses the server default.
+	PageSize      int32 `protobuf:"varint,12,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMoviesRequest) Reset() {
+	*x = SearchMoviesRequest{}
+	mi := &file_movie_messages_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMoviesRequest) ProtoMessage() {}
+
+func (x *SearchMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMoviesRequest.ProtoReflect.Descriptor instead.
+func (*SearchMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SearchMoviesRequest) GetGenres() []string {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
+func (x *SearchMoviesRequest) GetGenreMatch() GenreMatch {
+	if x != nil {
+		return x.GenreMatch
+	}
+	return GenreMatch_GENRE_MATCH_UNSPECIFIED
+}
+
+func (x *SearchMoviesRequest) GetReleasedAfter() string {
+	if x != nil {
+		return x.ReleasedAfter
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetReleasedBefore() string {
+	if x != nil {
+		return x.ReleasedBefore
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetDirectorName() string {
+	if x != nil {
+		return x.DirectorName
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetActorName() string {
+	if x != nil {
+		return x.ActorName
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetCrewRole() string {
+	if x != nil {
+		return x.CrewRole
+	}
+	return ""
+}
+
+func (x *SearchMoviesRequest) GetMinimumRatingsScore() float32 {
+	if x != nil && x.MinimumRatingsScore != nil {
+		return *x.MinimumRatingsScore
+	}
+	return 0
+}
+
+func (x *SearchMoviesRequest) GetMaximumRatingsScore() float32 {
+	if x != nil && x.MaximumRatingsScore != nil {
+		return *x.MaximumRatingsScore
+	}
+	return 0
+}
+
+func (x *SearchMoviesRequest) GetSortBy() SortField {
+	if x != nil {
+		return x.SortBy
+	}
+	return SortField_SORT_FIELD_UNSPECIFIED
+}
+
+func (x *SearchMoviesRequest) GetSortOrder() SortOrder {
+	if x != nil {
+		return x.SortOrder
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+func (x *SearchMoviesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type SearchMoviesResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Movie      []*Movie               `protobuf:"bytes,1,rep,name=movie,proto3" json:"movie,omitempty"`
+	MovieCount int32                  `protobuf:"varint,2,opt,name=movie_count,json=movieCount,proto3" json:"movie_count,omitempty"`
+	// Number of movies matching the filters before page_size was applied.
+	TotalSize     int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMoviesResponse) Reset() {
+	*x = SearchMoviesResponse{}
+	mi := &file_movie_messages_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMoviesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMoviesResponse) ProtoMessage() {}
+
+func (x *SearchMoviesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMoviesResponse.ProtoReflect.Descriptor instead.
+func (*SearchMoviesResponse) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SearchMoviesResponse) GetMovie() []*Movie {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+func (x *SearchMoviesResponse) GetMovieCount() int32 {
+	if x != nil {
+		return x.MovieCount
+	}
+	return 0
+}
+
+func (x *SearchMoviesResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type FullTextSearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Words are matched case- and diacritic-insensitively; the last characters of a word may be omitted.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// 0 uses the server default.
+	PageSize      int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FullTextSearchRequest) Reset() {
+	*x = FullTextSearchRequest{}
+	mi := &file_movie_messages_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FullTextSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FullTextSearchRequest) ProtoMessage() {}
+
+func (x *FullTextSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FullTextSearchRequest.ProtoReflect.Descriptor instead.
+func (*FullTextSearchRequest) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FullTextSearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *FullTextSearchRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type FullTextSearchHighlight struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Field string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// HTML-escaped text with matched words wrapped in <em></em>.
+	Snippet       string `protobuf:"bytes,2,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FullTextSearchHighlight) Reset() {
+	*x = FullTextSearchHighlight{}
+	mi := &file_movie_messages_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FullTextSearchHighlight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FullTextSearchHighlight) ProtoMessage() {}
+
+func (x *FullTextSearchHighlight) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FullTextSearchHighlight.ProtoReflect.Descriptor instead.
+func (*FullTextSearchHighlight) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FullTextSearchHighlight) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FullTextSearchHighlight) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+type FullTextSearchResult struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Movie         *Movie                     `protobuf:"bytes,1,opt,name=movie,proto3" json:"movie,omitempty"`
+	Score         float32                    `protobuf:"fixed32,2,opt,name=score,proto3" json:"score,omitempty"`
+	Highlight     []*FullTextSearchHighlight `protobuf:"bytes,3,rep,name=highlight,proto3" json:"highlight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FullTextSearchResult) Reset() {
+	*x = FullTextSearchResult{}
+	mi := &file_movie_messages_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FullTextSearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FullTextSearchResult) ProtoMessage() {}
+
+func (x *FullTextSearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FullTextSearchResult.ProtoReflect.Descriptor instead.
+func (*FullTextSearchResult) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FullTextSearchResult) GetMovie() *Movie {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+func (x *FullTextSearchResult) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *FullTextSearchResult) GetHighlight() []*FullTextSearchHighlight {
+	if x != nil {
+		return x.Highlight
+	}
+	return nil
+}
+
+type FullTextSearchResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Result        []*FullTextSearchResult `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+	ResultCount   int32                   `protobuf:"varint,2,opt,name=result_count,json=resultCount,proto3" json:"result_count,omitempty"`
+	TotalSize     int32                   `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FullTextSearchResponse) Reset() {
+	*x = FullTextSearchResponse{}
+	mi := &file_movie_messages_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FullTextSearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FullTextSearchResponse) ProtoMessage() {}
+
+func (x *FullTextSearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FullTextSearchResponse.ProtoReflect.Descriptor instead.
+func (*FullTextSearchResponse) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FullTextSearchResponse) GetResult() []*FullTextSearchResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *FullTextSearchResponse) GetResultCount() int32 {
+	if x != nil {
+		return x.ResultCount
+	}
+	return 0
+}
+
+func (x *FullTextSearchResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type WatchMoviesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only movies rated at least this score are watched; movies crossing it are reported as ADDED or DELETED.
+	MinimumRatingsScore float32 `protobuf:"fixed32,1,opt,name=minimum_ratings_score,json=minimumRatingsScore,proto3" json:"minimum_ratings_score,omitempty"`
+	// Revision of the last event received, to resume after a reconnect.
+	// 0 starts with every matching movie as an ADDED event at revision 0, then a SNAPSHOT_COMPLETE
+	// event at the current revision, so a watch cut off during the snapshot starts over.
+	ResumeRevision int64 `protobuf:"varint,2,opt,name=resume_revision,json=resumeRevision,proto3" json:"resume_revision,omitempty"`
+	// Epoch of the last event received, required with resume_revision.
+	// Revisions from another epoch fail with OUT_OF_RANGE.
+	ResumeEpoch   string `protobuf:"bytes,3,opt,name=resume_epoch,json=resumeEpoch,proto3" json:"resume_epoch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchMoviesRequest) Reset() {
+	*x = WatchMoviesRequest{}
+	mi := &file_movie_messages_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchMoviesRequest) ProtoMessage() {}
+
+func (x *WatchMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchMoviesRequest.ProtoReflect.Descriptor instead.
+func (*WatchMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchMoviesRequest) GetMinimumRatingsScore() float32 {
+	if x != nil {
+		return x.MinimumRatingsScore
+	}
+	return 0
+}
+
+func (x *WatchMoviesRequest) GetResumeRevision() int64 {
+	if x != nil {
+		return x.ResumeRevision
+	}
+	return 0
+}
+
+func (x *WatchMoviesRequest) GetResumeEpoch() string {
+	if x != nil {
+		return x.ResumeEpoch
+	}
+	return ""
+}
+
+type MovieEvent struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Type     MovieEventType         `protobuf:"varint,1,opt,name=type,proto3,enum=movie.MovieEventType" json:"type,omitempty"`
+	Revision int64                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	// For DELETED events, the movie as it was before it was deleted or fell below the threshold.
+	// Unset for SNAPSHOT_COMPLETE.
+	Movie *Movie `protobuf:"bytes,3,opt,name=movie,proto3" json:"movie,omitempty"`
+	// Identifies the change history revision belongs to; it changes whenever the server restarts.
+	Epoch         string `protobuf:"bytes,4,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MovieEvent) Reset() {
+	*x = MovieEvent{}
+	mi := &file_movie_messages_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MovieEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MovieEvent) ProtoMessage() {}
+
+func (x *MovieEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (x *GetMovieInput) Reset() {
-	*x = GetMovieInput{}
-	mi := &file_movie_messages_proto_msgTypes[0]
+// Deprecated: Use MovieEvent.ProtoReflect.Descriptor instead.
+func (*MovieEvent) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MovieEvent) GetType() MovieEventType {
+	if x != nil {
+		return x.Type
+	}
+	return MovieEventType_MOVIE_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *MovieEvent) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *MovieEvent) GetMovie() *Movie {
+	if x != nil {
+		return x.Movie
+	}
+	return nil
+}
+
+func (x *MovieEvent) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+// A movie together with the etag identifying its current content.
+type VersionedMovie struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Movie         *Movie                 `protobuf:"bytes,1,opt,name=movie,proto3" json:"movie,omitempty"`
+	Etag          string                 `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VersionedMovie) Reset() {
+	*x = VersionedMovie{}
+	mi := &file_movie_messages_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMovieInput) String() string {
+func (x *VersionedMovie) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMovieInput) ProtoMessage() {}
+func (*VersionedMovie) ProtoMessage() {}
 
-func (x *GetMovieInput) ProtoReflect() protoreflect.Message {
-	mi := &file_movie_messages_proto_msgTypes[0]
+func (x *VersionedMovie) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -53,42 +1107,47 @@ func (x *GetMovieInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMovieInput.ProtoReflect.Descriptor instead.
-func (*GetMovieInput) Descriptor() ([]byte, []int) {
-	return file_movie_messages_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use VersionedMovie.ProtoReflect.Descriptor instead.
+func (*VersionedMovie) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *GetMovieInput) GetMinimumRatingsScore() float32 {
+func (x *VersionedMovie) GetMovie() *Movie {
 	if x != nil {
-		return x.MinimumRatingsScore
+		return x.Movie
 	}
-	return 0
+	return nil
 }
 
-type GetMovieOutput struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Movie           []*Movie               `protobuf:"bytes,1,rep,name=movie,proto3" json:"movie,omitempty"`
-	MovieCount      int32                  `protobuf:"varint,2,opt,name=movie_count,json=movieCount,proto3" json:"movie_count,omitempty"`
-	MovieCountSoFar int32                  `protobuf:"varint,3,opt,name=movie_count_so_far,json=movieCountSoFar,proto3" json:"movie_count_so_far,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *VersionedMovie) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
 }
 
-func (x *GetMovieOutput) Reset() {
-	*x = GetMovieOutput{}
-	mi := &file_movie_messages_proto_msgTypes[1]
+type CreateMovieRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Movie         *Movie                 `protobuf:"bytes,1,opt,name=movie,proto3" json:"movie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateMovieRequest) Reset() {
+	*x = CreateMovieRequest{}
+	mi := &file_movie_messages_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMovieOutput) String() string {
+func (x *CreateMovieRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMovieOutput) ProtoMessage() {}
+func (*CreateMovieRequest) ProtoMessage() {}
 
-func (x *GetMovieOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_movie_messages_proto_msgTypes[1]
+func (x *CreateMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -99,30 +1158,265 @@ func (x *GetMovieOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMovieOutput.ProtoReflect.Descriptor instead.
-func (*GetMovieOutput) Descriptor() ([]byte, []int) {
-	return file_movie_messages_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use CreateMovieRequest.ProtoReflect.Descriptor instead.
+func (*CreateMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *GetMovieOutput) GetMovie() []*Movie {
+func (x *CreateMovieRequest) GetMovie() *Movie {
 	if x != nil {
 		return x.Movie
 	}
 	return nil
 }
 
-func (x *GetMovieOutput) GetMovieCount() int32 {
+type UpdateMovieRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// movie.movie_id selects the movie to update.
+	Movie *Movie `protobuf:"bytes,1,opt,name=movie,proto3" json:"movie,omitempty"`
+	// Movie fields to replace, e.g. "title" or "director.name"; empty replaces every field.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Etag of the version being edited; the update is ABORTED if the movie has changed since.
+	Etag          string `protobuf:"bytes,3,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMovieRequest) Reset() {
+	*x = UpdateMovieRequest{}
+	mi := &file_movie_messages_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMovieRequest) ProtoMessage() {}
+
+func (x *UpdateMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[15]
 	if x != nil {
-		return x.MovieCount
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *GetMovieOutput) GetMovieCountSoFar() int32 {
+// Deprecated: Use UpdateMovieRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateMovieRequest) GetMovie() *Movie {
 	if x != nil {
-		return x.MovieCountSoFar
+		return x.Movie
 	}
-	return 0
+	return nil
+}
+
+func (x *UpdateMovieRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateMovieRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type DeleteMovieRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	MovieId string                 `protobuf:"bytes,1,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
+	// Etag of the version being deleted; the delete is ABORTED if the movie has changed since.
+	Etag          string `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMovieRequest) Reset() {
+	*x = DeleteMovieRequest{}
+	mi := &file_movie_messages_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMovieRequest) ProtoMessage() {}
+
+func (x *DeleteMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMovieRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteMovieRequest) GetMovieId() string {
+	if x != nil {
+		return x.MovieId
+	}
+	return ""
+}
+
+func (x *DeleteMovieRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type DeleteMovieResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMovieResponse) Reset() {
+	*x = DeleteMovieResponse{}
+	mi := &file_movie_messages_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMovieResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMovieResponse) ProtoMessage() {}
+
+func (x *DeleteMovieResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMovieResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMovieResponse) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{17}
+}
+
+type SetLogLevelRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// debug, info, warn or error
+	Level         string `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLogLevelRequest) Reset() {
+	*x = SetLogLevelRequest{}
+	mi := &file_movie_messages_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLogLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLogLevelRequest) ProtoMessage() {}
+
+func (x *SetLogLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLogLevelRequest.ProtoReflect.Descriptor instead.
+func (*SetLogLevelRequest) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SetLogLevelRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+type SetLogLevelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PreviousLevel string                 `protobuf:"bytes,1,opt,name=previous_level,json=previousLevel,proto3" json:"previous_level,omitempty"`
+	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLogLevelResponse) Reset() {
+	*x = SetLogLevelResponse{}
+	mi := &file_movie_messages_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLogLevelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLogLevelResponse) ProtoMessage() {}
+
+func (x *SetLogLevelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_messages_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLogLevelResponse.ProtoReflect.Descriptor instead.
+func (*SetLogLevelResponse) Descriptor() ([]byte, []int) {
+	return file_movie_messages_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetLogLevelResponse) GetPreviousLevel() string {
+	if x != nil {
+		return x.PreviousLevel
+	}
+	return ""
+}
+
+func (x *SetLogLevelResponse) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
 }
 
 type Movie struct {
@@ -143,7 +1437,7 @@ type Movie struct {
 
 func (x *Movie) Reset() {
 	*x = Movie{}
-	mi := &file_movie_messages_proto_msgTypes[2]
+	mi := &file_movie_messages_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -155,7 +1449,7 @@ func (x *Movie) String() string {
 func (*Movie) ProtoMessage() {}
 
 func (x *Movie) ProtoReflect() protoreflect.Message {
-	mi := &file_movie_messages_proto_msgTypes[2]
+	mi := &file_movie_messages_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -168,7 +1462,7 @@ func (x *Movie) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Movie.ProtoReflect.Descriptor instead.
 func (*Movie) Descriptor() ([]byte, []int) {
-	return file_movie_messages_proto_rawDescGZIP(), []int{2}
+	return file_movie_messages_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *Movie) GetMovieId() string {
@@ -250,7 +1544,7 @@ type Director struct {
 
 func (x *Director) Reset() {
 	*x = Director{}
-	mi := &file_movie_messages_proto_msgTypes[3]
+	mi := &file_movie_messages_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -262,7 +1556,7 @@ func (x *Director) String() string {
 func (*Director) ProtoMessage() {}
 
 func (x *Director) ProtoReflect() protoreflect.Message {
-	mi := &file_movie_messages_proto_msgTypes[3]
+	mi := &file_movie_messages_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -275,7 +1569,7 @@ func (x *Director) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Director.ProtoReflect.Descriptor instead.
 func (*Director) Descriptor() ([]byte, []int) {
-	return file_movie_messages_proto_rawDescGZIP(), []int{3}
+	return file_movie_messages_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *Director) GetName() string {
@@ -294,7 +1588,7 @@ type Producer struct {
 
 func (x *Producer) Reset() {
 	*x = Producer{}
-	mi := &file_movie_messages_proto_msgTypes[4]
+	mi := &file_movie_messages_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -306,7 +1600,7 @@ func (x *Producer) String() string {
 func (*Producer) ProtoMessage() {}
 
 func (x *Producer) ProtoReflect() protoreflect.Message {
-	mi := &file_movie_messages_proto_msgTypes[4]
+	mi := &file_movie_messages_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -319,7 +1613,7 @@ func (x *Producer) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Producer.ProtoReflect.Descriptor instead.
 func (*Producer) Descriptor() ([]byte, []int) {
-	return file_movie_messages_proto_rawDescGZIP(), []int{4}
+	return file_movie_messages_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *Producer) GetName() string {
@@ -341,7 +1635,7 @@ type CastMember struct {
 
 func (x *CastMember) Reset() {
 	*x = CastMember{}
-	mi := &file_movie_messages_proto_msgTypes[5]
+	mi := &file_movie_messages_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -353,7 +1647,7 @@ func (x *CastMember) String() string {
 func (*CastMember) ProtoMessage() {}
 
 func (x *CastMember) ProtoReflect() protoreflect.Message {
-	mi := &file_movie_messages_proto_msgTypes[5]
+	mi := &file_movie_messages_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -366,7 +1660,7 @@ func (x *CastMember) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CastMember.ProtoReflect.Descriptor instead.
 func (*CastMember) Descriptor() ([]byte, []int) {
-	return file_movie_messages_proto_rawDescGZIP(), []int{5}
+	return file_movie_messages_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *CastMember) GetActorName() string {
@@ -407,7 +1701,7 @@ type CrewMember struct {
 
 func (x *CrewMember) Reset() {
 	*x = CrewMember{}
-	mi := &file_movie_messages_proto_msgTypes[6]
+	mi := &file_movie_messages_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -419,7 +1713,7 @@ func (x *CrewMember) String() string {
 func (*CrewMember) ProtoMessage() {}
 
 func (x *CrewMember) ProtoReflect() protoreflect.Message {
-	mi := &file_movie_messages_proto_msgTypes[6]
+	mi := &file_movie_messages_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -432,7 +1726,7 @@ func (x *CrewMember) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CrewMember.ProtoReflect.Descriptor instead.
 func (*CrewMember) Descriptor() ([]byte, []int) {
-	return file_movie_messages_proto_rawDescGZIP(), []int{6}
+	return file_movie_messages_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *CrewMember) GetName() string {
@@ -453,14 +1747,96 @@ var File_movie_messages_proto protoreflect.FileDescriptor
 
 const file_movie_messages_proto_rawDesc = "" +
 	"\n" +
-	"\x14movie_messages.proto\x12\x05movie\"C\n" +
+	"\x14movie_messages.proto\x12\x05movie\x1a google/protobuf/field_mask.proto\"\x7f\n" +
 	"\rGetMovieInput\x122\n" +
-	"\x15minimum_ratings_score\x18\x01 \x01(\x02R\x13minimumRatingsScore\"\x82\x01\n" +
+	"\x15minimum_ratings_score\x18\x01 \x01(\x02R\x13minimumRatingsScore\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"\xc9\x01\n" +
 	"\x0eGetMovieOutput\x12\"\n" +
 	"\x05movie\x18\x01 \x03(\v2\f.movie.MovieR\x05movie\x12\x1f\n" +
 	"\vmovie_count\x18\x02 \x01(\x05R\n" +
 	"movieCount\x12+\n" +
-	"\x12movie_count_so_far\x18\x03 \x01(\x05R\x0fmovieCountSoFar\"\xe1\x02\n" +
+	"\x12movie_count_so_far\x18\x03 \x01(\x05R\x0fmovieCountSoFar\x12&\n" +
+	"\x0fnext_page_token\x18\x04 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x05 \x01(\x05R\ttotalSize\",\n" +
+	"\x0fGetMovieRequest\x12\x19\n" +
+	"\bmovie_id\x18\x01 \x01(\tR\amovieId\"4\n" +
+	"\x15BatchGetMoviesRequest\x12\x1b\n" +
+	"\tmovie_ids\x18\x01 \x03(\tR\bmovieIds\"h\n" +
+	"\x16BatchGetMoviesResponse\x12\"\n" +
+	"\x05movie\x18\x01 \x03(\v2\f.movie.MovieR\x05movie\x12*\n" +
+	"\x11missing_movie_ids\x18\x02 \x03(\tR\x0fmissingMovieIds\"\xb1\x04\n" +
+	"\x13SearchMoviesRequest\x12\x16\n" +
+	"\x06genres\x18\x01 \x03(\tR\x06genres\x122\n" +
+	"\vgenre_match\x18\x02 \x01(\x0e2\x11.movie.GenreMatchR\n" +
+	"genreMatch\x12%\n" +
+	"\x0ereleased_after\x18\x03 \x01(\tR\rreleasedAfter\x12'\n" +
+	"\x0freleased_before\x18\x04 \x01(\tR\x0ereleasedBefore\x12#\n" +
+	"\rdirector_name\x18\x05 \x01(\tR\fdirectorName\x12\x1d\n" +
+	"\n" +
+	"actor_name\x18\x06 \x01(\tR\tactorName\x12\x1b\n" +
+	"\tcrew_role\x18\a \x01(\tR\bcrewRole\x127\n" +
+	"\x15minimum_ratings_score\x18\b \x01(\x02H\x00R\x13minimumRatingsScore\x88\x01\x01\x127\n" +
+	"\x15maximum_ratings_score\x18\t \x01(\x02H\x01R\x13maximumRatingsScore\x88\x01\x01\x12)\n" +
+	"\asort_by\x18\n" +
+	" \x01(\x0e2\x10.movie.SortFieldR\x06sortBy\x12/\n" +
+	"\n" +
+	"sort_order\x18\v \x01(\x0e2\x10.movie.SortOrderR\tsortOrder\x12\x1b\n" +
+	"\tpage_size\x18\f \x01(\x05R\bpageSizeB\x18\n" +
+	"\x16_minimum_ratings_scoreB\x18\n" +
+	"\x16_maximum_ratings_score\"z\n" +
+	"\x14SearchMoviesResponse\x12\"\n" +
+	"\x05movie\x18\x01 \x03(\v2\f.movie.MovieR\x05movie\x12\x1f\n" +
+	"\vmovie_count\x18\x02 \x01(\x05R\n" +
+	"movieCount\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"J\n" +
+	"\x15FullTextSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"I\n" +
+	"\x17FullTextSearchHighlight\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x18\n" +
+	"\asnippet\x18\x02 \x01(\tR\asnippet\"\x8e\x01\n" +
+	"\x14FullTextSearchResult\x12\"\n" +
+	"\x05movie\x18\x01 \x01(\v2\f.movie.MovieR\x05movie\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x02R\x05score\x12<\n" +
+	"\thighlight\x18\x03 \x03(\v2\x1e.movie.FullTextSearchHighlightR\thighlight\"\x8f\x01\n" +
+	"\x16FullTextSearchResponse\x123\n" +
+	"\x06result\x18\x01 \x03(\v2\x1b.movie.FullTextSearchResultR\x06result\x12!\n" +
+	"\fresult_count\x18\x02 \x01(\x05R\vresultCount\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"\x94\x01\n" +
+	"\x12WatchMoviesRequest\x122\n" +
+	"\x15minimum_ratings_score\x18\x01 \x01(\x02R\x13minimumRatingsScore\x12'\n" +
+	"\x0fresume_revision\x18\x02 \x01(\x03R\x0eresumeRevision\x12!\n" +
+	"\fresume_epoch\x18\x03 \x01(\tR\vresumeEpoch\"\x8d\x01\n" +
+	"\n" +
+	"MovieEvent\x12)\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x15.movie.MovieEventTypeR\x04type\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x03R\brevision\x12\"\n" +
+	"\x05movie\x18\x03 \x01(\v2\f.movie.MovieR\x05movie\x12\x14\n" +
+	"\x05epoch\x18\x04 \x01(\tR\x05epoch\"H\n" +
+	"\x0eVersionedMovie\x12\"\n" +
+	"\x05movie\x18\x01 \x01(\v2\f.movie.MovieR\x05movie\x12\x12\n" +
+	"\x04etag\x18\x02 \x01(\tR\x04etag\"8\n" +
+	"\x12CreateMovieRequest\x12\"\n" +
+	"\x05movie\x18\x01 \x01(\v2\f.movie.MovieR\x05movie\"\x89\x01\n" +
+	"\x12UpdateMovieRequest\x12\"\n" +
+	"\x05movie\x18\x01 \x01(\v2\f.movie.MovieR\x05movie\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12\x12\n" +
+	"\x04etag\x18\x03 \x01(\tR\x04etag\"C\n" +
+	"\x12DeleteMovieRequest\x12\x19\n" +
+	"\bmovie_id\x18\x01 \x01(\tR\amovieId\x12\x12\n" +
+	"\x04etag\x18\x02 \x01(\tR\x04etag\"\x15\n" +
+	"\x13DeleteMovieResponse\"*\n" +
+	"\x12SetLogLevelRequest\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\tR\x05level\"R\n" +
+	"\x13SetLogLevelResponse\x12%\n" +
+	"\x0eprevious_level\x18\x01 \x01(\tR\rpreviousLevel\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\"\xe1\x02\n" +
 	"\x05Movie\x12\x19\n" +
 	"\bmovie_id\x18\x01 \x01(\tR\amovieId\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12!\n" +
@@ -487,7 +1863,27 @@ const file_movie_messages_proto_rawDesc = "" +
 	"\n" +
 	"CrewMember\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
-	"\x04role\x18\x02 \x01(\tR\x04roleB\x1dZ\x1bcase-studies/grpc/cmd/movieb\x06proto3"
+	"\x04role\x18\x02 \x01(\tR\x04role*S\n" +
+	"\n" +
+	"GenreMatch\x12\x1b\n" +
+	"\x17GENRE_MATCH_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fGENRE_MATCH_ANY\x10\x01\x12\x13\n" +
+	"\x0fGENRE_MATCH_ALL\x10\x02*q\n" +
+	"\tSortField\x12\x1a\n" +
+	"\x16SORT_FIELD_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11SORT_FIELD_RATING\x10\x01\x12\x1b\n" +
+	"\x17SORT_FIELD_RELEASE_DATE\x10\x02\x12\x14\n" +
+	"\x10SORT_FIELD_TITLE\x10\x03*\\\n" +
+	"\tSortOrder\x12\x1a\n" +
+	"\x16SORT_ORDER_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14SORT_ORDER_ASCENDING\x10\x01\x12\x19\n" +
+	"\x15SORT_ORDER_DESCENDING\x10\x02*\xb3\x01\n" +
+	"\x0eMovieEventType\x12 \n" +
+	"\x1cMOVIE_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16MOVIE_EVENT_TYPE_ADDED\x10\x01\x12\x1d\n" +
+	"\x19MOVIE_EVENT_TYPE_MODIFIED\x10\x02\x12\x1c\n" +
+	"\x18MOVIE_EVENT_TYPE_DELETED\x10\x03\x12&\n" +
+	"\"MOVIE_EVENT_TYPE_SNAPSHOT_COMPLETE\x10\x04B\x1dZ\x1bcase-studies/grpc/cmd/movieb\x06proto3"
 
 var (
 	file_movie_messages_proto_rawDescOnce sync.Once
@@ -501,27 +1897,65 @@ func file_movie_messages_proto_rawDescGZIP() []byte {
 	return file_movie_messages_proto_rawDescData
 }
 
-var file_movie_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_movie_messages_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_movie_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_movie_messages_proto_goTypes = []any{
-	(*GetMovieInput)(nil),  // 0: movie.GetMovieInput
-	(*GetMovieOutput)(nil), // 1: movie.GetMovieOutput
-	(*Movie)(nil),          // 2: movie.Movie
-	(*Director)(nil),       // 3: movie.Director
-	(*Producer)(nil),       // 4: movie.Producer
-	(*CastMember)(nil),     // 5: movie.CastMember
-	(*CrewMember)(nil),     // 6: movie.CrewMember
+	(GenreMatch)(0),                 // 0: movie.GenreMatch
+	(SortField)(0),                  // 1: movie.SortField
+	(SortOrder)(0),                  // 2: movie.SortOrder
+	(MovieEventType)(0),             // 3: movie.MovieEventType
+	(*GetMovieInput)(nil),           // 4: movie.GetMovieInput
+	(*GetMovieOutput)(nil),          // 5: movie.GetMovieOutput
+	(*GetMovieRequest)(nil),         // 6: movie.GetMovieRequest
+	(*BatchGetMoviesRequest)(nil),   // 7: movie.BatchGetMoviesRequest
+	(*BatchGetMoviesResponse)(nil),  // 8: movie.BatchGetMoviesResponse
+	(*SearchMoviesRequest)(nil),     // 9: movie.SearchMoviesRequest
+	(*SearchMoviesResponse)(nil),    // 10: movie.SearchMoviesResponse
+	(*FullTextSearchRequest)(nil),   // 11: movie.FullTextSearchRequest
+	(*FullTextSearchHighlight)(nil), // 12: movie.FullTextSearchHighlight
+	(*FullTextSearchResult)(nil),    // 13: movie.FullTextSearchResult
+	(*FullTextSearchResponse)(nil),  // 14: movie.FullTextSearchResponse
+	(*WatchMoviesRequest)(nil),      // 15: movie.WatchMoviesRequest
+	(*MovieEvent)(nil),              // 16: movie.MovieEvent
+	(*VersionedMovie)(nil),          // 17: movie.VersionedMovie
+	(*CreateMovieRequest)(nil),      // 18: movie.CreateMovieRequest
+	(*UpdateMovieRequest)(nil),      // 19: movie.UpdateMovieRequest
+	(*DeleteMovieRequest)(nil),      // 20: movie.DeleteMovieRequest
+	(*DeleteMovieResponse)(nil),     // 21: movie.DeleteMovieResponse
+	(*SetLogLevelRequest)(nil),      // 22: movie.SetLogLevelRequest
+	(*SetLogLevelResponse)(nil),     // 23: movie.SetLogLevelResponse
+	(*Movie)(nil),                   // 24: movie.Movie
+	(*Director)(nil),                // 25: movie.Director
+	(*Producer)(nil),                // 26: movie.Producer
+	(*CastMember)(nil),              // 27: movie.CastMember
+	(*CrewMember)(nil),              // 28: movie.CrewMember
+	(*fieldmaskpb.FieldMask)(nil),   // 29: google.protobuf.FieldMask
 }
 var file_movie_messages_proto_depIdxs = []int32{
-	2, // 0: movie.GetMovieOutput.movie:type_name -> movie.Movie
-	3, // 1: movie.Movie.director:type_name -> movie.Director
-	4, // 2: movie.Movie.producer:type_name -> movie.Producer
-	5, // 3: movie.Movie.cast:type_name -> movie.CastMember
-	6, // 4: movie.Movie.crew:type_name -> movie.CrewMember
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	24, // 0: movie.GetMovieOutput.movie:type_name -> movie.Movie
+	24, // 1: movie.BatchGetMoviesResponse.movie:type_name -> movie.Movie
+	0,  // 2: movie.SearchMoviesRequest.genre_match:type_name -> movie.GenreMatch
+	1,  // 3: movie.SearchMoviesRequest.sort_by:type_name -> movie.SortField
+	2,  // 4: movie.SearchMoviesRequest.sort_order:type_name -> movie.SortOrder
+	24, // 5: movie.SearchMoviesResponse.movie:type_name -> movie.Movie
+	24, // 6: movie.FullTextSearchResult.movie:type_name -> movie.Movie
+	12, // 7: movie.FullTextSearchResult.highlight:type_name -> movie.FullTextSearchHighlight
+	13, // 8: movie.FullTextSearchResponse.result:type_name -> movie.FullTextSearchResult
+	3,  // 9: movie.MovieEvent.type:type_name -> movie.MovieEventType
+	24, // 10: movie.MovieEvent.movie:type_name -> movie.Movie
+	24, // 11: movie.VersionedMovie.movie:type_name -> movie.Movie
+	24, // 12: movie.CreateMovieRequest.movie:type_name -> movie.Movie
+	24, // 13: movie.UpdateMovieRequest.movie:type_name -> movie.Movie
+	29, // 14: movie.UpdateMovieRequest.update_mask:type_name -> google.protobuf.FieldMask
+	25, // 15: movie.Movie.director:type_name -> movie.Director
+	26, // 16: movie.Movie.producer:type_name -> movie.Producer
+	27, // 17: movie.Movie.cast:type_name -> movie.CastMember
+	28, // 18: movie.Movie.crew:type_name -> movie.CrewMember
+	19, // [19:19] is the sub-list for method output_type
+	19, // [19:19] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_movie_messages_proto_init() }
@@ -529,18 +1963,20 @@ func file_movie_messages_proto_init() {
 	if File_movie_messages_proto != nil {
 		return
 	}
+	file_movie_messages_proto_msgTypes[5].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_movie_messages_proto_rawDesc), len(file_movie_messages_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      4,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_movie_messages_proto_goTypes,
 		DependencyIndexes: file_movie_messages_proto_depIdxs,
+		EnumInfos:         file_movie_messages_proto_enumTypes,
 		MessageInfos:      file_movie_messages_proto_msgTypes,
 	}.Build()
 	File_movie_messages_proto = out.File
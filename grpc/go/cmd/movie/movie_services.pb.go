@@ -24,25 +24,75 @@ var File_movie_services_proto protoreflect.FileDescriptor
 
 const file_movie_services_proto_rawDesc = "" +
 	"\n" +
-	"\x14movie_services.proto\x12\x05movie\x1a\x14movie_messages.proto2\x9c\x01\n" +
+	"\x14movie_services.proto\x12\x05movie\x1a\x14movie_messages.proto2\xfe\x03\n" +
 	"\x06Getter\x12C\n" +
 	"\x12GetMoviesByRatings\x12\x14.movie.GetMovieInput\x1a\x15.movie.GetMovieOutput\"\x00\x12M\n" +
-	"\x18GetMoviesByRatingsStream\x12\x14.movie.GetMovieInput\x1a\x15.movie.GetMovieOutput\"\x00(\x010\x01B\x1dZ\x1bcase-studies/grpc/cmd/movieb\x06proto3"
+	"\x18GetMoviesByRatingsStream\x12\x14.movie.GetMovieInput\x1a\x15.movie.GetMovieOutput\"\x00(\x010\x01\x122\n" +
+	"\bGetMovie\x12\x16.movie.GetMovieRequest\x1a\f.movie.Movie\"\x00\x12O\n" +
+	"\x0eBatchGetMovies\x12\x1c.movie.BatchGetMoviesRequest\x1a\x1d.movie.BatchGetMoviesResponse\"\x00\x12I\n" +
+	"\fSearchMovies\x12\x1a.movie.SearchMoviesRequest\x1a\x1b.movie.SearchMoviesResponse\"\x00\x12O\n" +
+	"\x0eFullTextSearch\x12\x1c.movie.FullTextSearchRequest\x1a\x1d.movie.FullTextSearchResponse\"\x00\x12?\n" +
+	"\vWatchMovies\x12\x19.movie.WatchMoviesRequest\x1a\x11.movie.MovieEvent\"\x000\x012\xa0\x02\n" +
+	"\n" +
+	"MovieAdmin\x12D\n" +
+	"\x11GetVersionedMovie\x12\x16.movie.GetMovieRequest\x1a\x15.movie.VersionedMovie\"\x00\x12A\n" +
+	"\vCreateMovie\x12\x19.movie.CreateMovieRequest\x1a\x15.movie.VersionedMovie\"\x00\x12A\n" +
+	"\vUpdateMovie\x12\x19.movie.UpdateMovieRequest\x1a\x15.movie.VersionedMovie\"\x00\x12F\n" +
+	"\vDeleteMovie\x12\x19.movie.DeleteMovieRequest\x1a\x1a.movie.DeleteMovieResponse\"\x002U\n" +
+	"\vServerAdmin\x12F\n" +
+	"\vSetLogLevel\x12\x19.movie.SetLogLevelRequest\x1a\x1a.movie.SetLogLevelResponse\"\x00B\x1dZ\x1bcase-studies/grpc/cmd/movieb\x06proto3"
 
 var file_movie_services_proto_goTypes = []any{
-	(*GetMovieInput)(nil),  // 0: movie.GetMovieInput
-	(*GetMovieOutput)(nil), // 1: movie.GetMovieOutput
+	(*GetMovieInput)(nil),          // 0: movie.GetMovieInput
+	(*GetMovieRequest)(nil),        // 1: movie.GetMovieRequest
+	(*BatchGetMoviesRequest)(nil),  // 2: movie.BatchGetMoviesRequest
+	(*SearchMoviesRequest)(nil),    // 3: movie.SearchMoviesRequest
+	(*FullTextSearchRequest)(nil),  // 4: movie.FullTextSearchRequest
+	(*WatchMoviesRequest)(nil),     // 5: movie.WatchMoviesRequest
+	(*CreateMovieRequest)(nil),     // 6: movie.CreateMovieRequest
+	(*UpdateMovieRequest)(nil),     // 7: movie.UpdateMovieRequest
+	(*DeleteMovieRequest)(nil),     // 8: movie.DeleteMovieRequest
+	(*SetLogLevelRequest)(nil),     // 9: movie.SetLogLevelRequest
+	(*GetMovieOutput)(nil),         // 10: movie.GetMovieOutput
+	(*Movie)(nil),                  // 11: movie.Movie
+	(*BatchGetMoviesResponse)(nil), // 12: movie.BatchGetMoviesResponse
+	(*SearchMoviesResponse)(nil),   // 13: movie.SearchMoviesResponse
+	(*FullTextSearchResponse)(nil), // 14: movie.FullTextSearchResponse
+	(*MovieEvent)(nil),             // 15: movie.MovieEvent
+	(*VersionedMovie)(nil),         // 16: movie.VersionedMovie
+	(*DeleteMovieResponse)(nil),    // 17: movie.DeleteMovieResponse
+	(*SetLogLevelResponse)(nil),    // 18: movie.SetLogLevelResponse
 }
 var file_movie_services_proto_depIdxs = []int32{
-	0, // 0: movie.Getter.GetMoviesByRatings:input_type -> movie.GetMovieInput
-	0, // 1: movie.Getter.GetMoviesByRatingsStream:input_type -> movie.GetMovieInput
-	1, // 2: movie.Getter.GetMoviesByRatings:output_type -> movie.GetMovieOutput
-	1, // 3: movie.Getter.GetMoviesByRatingsStream:output_type -> movie.GetMovieOutput
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: movie.Getter.GetMoviesByRatings:input_type -> movie.GetMovieInput
+	0,  // 1: movie.Getter.GetMoviesByRatingsStream:input_type -> movie.GetMovieInput
+	1,  // 2: movie.Getter.GetMovie:input_type -> movie.GetMovieRequest
+	2,  // 3: movie.Getter.BatchGetMovies:input_type -> movie.BatchGetMoviesRequest
+	3,  // 4: movie.Getter.SearchMovies:input_type -> movie.SearchMoviesRequest
+	4,  // 5: movie.Getter.FullTextSearch:input_type -> movie.FullTextSearchRequest
+	5,  // 6: movie.Getter.WatchMovies:input_type -> movie.WatchMoviesRequest
+	1,  // 7: movie.MovieAdmin.GetVersionedMovie:input_type -> movie.GetMovieRequest
+	6,  // 8: movie.MovieAdmin.CreateMovie:input_type -> movie.CreateMovieRequest
+	7,  // 9: movie.MovieAdmin.UpdateMovie:input_type -> movie.UpdateMovieRequest
+	8,  // 10: movie.MovieAdmin.DeleteMovie:input_type -> movie.DeleteMovieRequest
+	9,  // 11: movie.ServerAdmin.SetLogLevel:input_type -> movie.SetLogLevelRequest
+	10, // 12: movie.Getter.GetMoviesByRatings:output_type -> movie.GetMovieOutput
+	10, // 13: movie.Getter.GetMoviesByRatingsStream:output_type -> movie.GetMovieOutput
+	11, // 14: movie.Getter.GetMovie:output_type -> movie.Movie
+	12, // 15: movie.Getter.BatchGetMovies:output_type -> movie.BatchGetMoviesResponse
+	13, // 16: movie.Getter.SearchMovies:output_type -> movie.SearchMoviesResponse
+	14, // 17: movie.Getter.FullTextSearch:output_type -> movie.FullTextSearchResponse
+	15, // 18: movie.Getter.WatchMovies:output_type -> movie.MovieEvent
+	16, // 19: movie.MovieAdmin.GetVersionedMovie:output_type -> movie.VersionedMovie
+	16, // 20: movie.MovieAdmin.CreateMovie:output_type -> movie.VersionedMovie
+	16, // 21: movie.MovieAdmin.UpdateMovie:output_type -> movie.VersionedMovie
+	17, // 22: movie.MovieAdmin.DeleteMovie:output_type -> movie.DeleteMovieResponse
+	18, // 23: movie.ServerAdmin.SetLogLevel:output_type -> movie.SetLogLevelResponse
+	12, // [12:24] is the sub-list for method output_type
+	0,  // [0:12] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_movie_services_proto_init() }
@@ -59,7 +109,7 @@ func file_movie_services_proto_init() {
 			NumEnums:      0,
 			NumMessages:   0,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   3,
 		},
 		GoTypes:           file_movie_services_proto_goTypes,
 		DependencyIndexes: file_movie_services_proto_depIdxs,
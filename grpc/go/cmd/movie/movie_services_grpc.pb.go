@@ -21,6 +21,11 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	Getter_GetMoviesByRatings_FullMethodName       = "/movie.Getter/GetMoviesByRatings"
 	Getter_GetMoviesByRatingsStream_FullMethodName = "/movie.Getter/GetMoviesByRatingsStream"
+	Getter_GetMovie_FullMethodName                 = "/movie.Getter/GetMovie"
+	Getter_BatchGetMovies_FullMethodName           = "/movie.Getter/BatchGetMovies"
+	Getter_SearchMovies_FullMethodName             = "/movie.Getter/SearchMovies"
+	Getter_FullTextSearch_FullMethodName           = "/movie.Getter/FullTextSearch"
+	Getter_WatchMovies_FullMethodName              = "/movie.Getter/WatchMovies"
 )
 
 // GetterClient is the client API for Getter service.
@@ -29,6 +34,11 @@ const (
 type GetterClient interface {
 	GetMoviesByRatings(ctx context.Context, in *GetMovieInput, opts ...grpc.CallOption) (*GetMovieOutput, error)
 	GetMoviesByRatingsStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GetMovieInput, GetMovieOutput], error)
+	GetMovie(ctx context.Context, in *GetMovieRequest, opts ...grpc.CallOption) (*Movie, error)
+	BatchGetMovies(ctx context.Context, in *BatchGetMoviesRequest, opts ...grpc.CallOption) (*BatchGetMoviesResponse, error)
+	SearchMovies(ctx context.Context, in *SearchMoviesRequest, opts ...grpc.CallOption) (*SearchMoviesResponse, error)
+	FullTextSearch(ctx context.Context, in *FullTextSearchRequest, opts ...grpc.CallOption) (*FullTextSearchResponse, error)
+	WatchMovies(ctx context.Context, in *WatchMoviesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MovieEvent], error)
 }
 
 type getterClient struct {
@@ -62,12 +72,76 @@ func (c *getterClient) GetMoviesByRatingsStream(ctx context.Context, opts ...grp
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Getter_GetMoviesByRatingsStreamClient = grpc.BidiStreamingClient[GetMovieInput, GetMovieOutput]
 
+func (c *getterClient) GetMovie(ctx context.Context, in *GetMovieRequest, opts ...grpc.CallOption) (*Movie, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Movie)
+	err := c.cc.Invoke(ctx, Getter_GetMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *getterClient) BatchGetMovies(ctx context.Context, in *BatchGetMoviesRequest, opts ...grpc.CallOption) (*BatchGetMoviesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetMoviesResponse)
+	err := c.cc.Invoke(ctx, Getter_BatchGetMovies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *getterClient) SearchMovies(ctx context.Context, in *SearchMoviesRequest, opts ...grpc.CallOption) (*SearchMoviesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchMoviesResponse)
+	err := c.cc.Invoke(ctx, Getter_SearchMovies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *getterClient) FullTextSearch(ctx context.Context, in *FullTextSearchRequest, opts ...grpc.CallOption) (*FullTextSearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FullTextSearchResponse)
+	err := c.cc.Invoke(ctx, Getter_FullTextSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *getterClient) WatchMovies(ctx context.Context, in *WatchMoviesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MovieEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Getter_ServiceDesc.Streams[1], Getter_WatchMovies_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchMoviesRequest, MovieEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Getter_WatchMoviesClient = grpc.ServerStreamingClient[MovieEvent]
+
 // GetterServer is the server API for Getter service.
 // All implementations must embed UnimplementedGetterServer
 // for forward compatibility.
 type GetterServer interface {
 	GetMoviesByRatings(context.Context, *GetMovieInput) (*GetMovieOutput, error)
 	GetMoviesByRatingsStream(grpc.BidiStreamingServer[GetMovieInput, GetMovieOutput]) error
+	GetMovie(context.Context, *GetMovieRequest) (*Movie, error)
+	BatchGetMovies(context.Context, *BatchGetMoviesRequest) (*BatchGetMoviesResponse, error)
+	SearchMovies(context.Context, *SearchMoviesRequest) (*SearchMoviesResponse, error)
+	FullTextSearch(context.Context, *FullTextSearchRequest) (*FullTextSearchResponse, error)
+	WatchMovies(*WatchMoviesRequest, grpc.ServerStreamingServer[MovieEvent]) error
 	mustEmbedUnimplementedGetterServer()
 }
 
@@ -84,6 +158,21 @@ func (UnimplementedGetterServer) GetMoviesByRatings(context.Context, *GetMovieIn
 func (UnimplementedGetterServer) GetMoviesByRatingsStream(grpc.BidiStreamingServer[GetMovieInput, GetMovieOutput]) error {
 	return status.Errorf(codes.Unimplemented, "method GetMoviesByRatingsStream not implemented")
 }
+func (UnimplementedGetterServer) GetMovie(context.Context, *GetMovieRequest) (*Movie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMovie not implemented")
+}
+func (UnimplementedGetterServer) BatchGetMovies(context.Context, *BatchGetMoviesRequest) (*BatchGetMoviesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetMovies not implemented")
+}
+func (UnimplementedGetterServer) SearchMovies(context.Context, *SearchMoviesRequest) (*SearchMoviesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchMovies not implemented")
+}
+func (UnimplementedGetterServer) FullTextSearch(context.Context, *FullTextSearchRequest) (*FullTextSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FullTextSearch not implemented")
+}
+func (UnimplementedGetterServer) WatchMovies(*WatchMoviesRequest, grpc.ServerStreamingServer[MovieEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchMovies not implemented")
+}
 func (UnimplementedGetterServer) mustEmbedUnimplementedGetterServer() {}
 func (UnimplementedGetterServer) testEmbeddedByValue()                {}
 
@@ -130,6 +219,89 @@ func _Getter_GetMoviesByRatingsStream_Handler(srv interface{}, stream grpc.Serve
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Getter_GetMoviesByRatingsStreamServer = grpc.BidiStreamingServer[GetMovieInput, GetMovieOutput]
 
+func _Getter_GetMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GetterServer).GetMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Getter_GetMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GetterServer).GetMovie(ctx, req.(*GetMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Getter_BatchGetMovies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetMoviesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GetterServer).BatchGetMovies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Getter_BatchGetMovies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GetterServer).BatchGetMovies(ctx, req.(*BatchGetMoviesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Getter_SearchMovies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchMoviesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GetterServer).SearchMovies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Getter_SearchMovies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GetterServer).SearchMovies(ctx, req.(*SearchMoviesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Getter_FullTextSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FullTextSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GetterServer).FullTextSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Getter_FullTextSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GetterServer).FullTextSearch(ctx, req.(*FullTextSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Getter_WatchMovies_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchMoviesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GetterServer).WatchMovies(m, &grpc.GenericServerStream[WatchMoviesRequest, MovieEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Getter_WatchMoviesServer = grpc.ServerStreamingServer[MovieEvent]
+
 // Getter_ServiceDesc is the grpc.ServiceDesc for Getter service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -141,6 +313,22 @@ var Getter_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMoviesByRatings",
 			Handler:    _Getter_GetMoviesByRatings_Handler,
 		},
+		{
+			MethodName: "GetMovie",
+			Handler:    _Getter_GetMovie_Handler,
+		},
+		{
+			MethodName: "BatchGetMovies",
+			Handler:    _Getter_BatchGetMovies_Handler,
+		},
+		{
+			MethodName: "SearchMovies",
+			Handler:    _Getter_SearchMovies_Handler,
+		},
+		{
+			MethodName: "FullTextSearch",
+			Handler:    _Getter_FullTextSearch_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -149,6 +337,331 @@ var Getter_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "WatchMovies",
+			Handler:       _Getter_WatchMovies_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "movie_services.proto",
+}
+
+const (
+	MovieAdmin_GetVersionedMovie_FullMethodName = "/movie.MovieAdmin/GetVersionedMovie"
+	MovieAdmin_CreateMovie_FullMethodName       = "/movie.MovieAdmin/CreateMovie"
+	MovieAdmin_UpdateMovie_FullMethodName       = "/movie.MovieAdmin/UpdateMovie"
+	MovieAdmin_DeleteMovie_FullMethodName       = "/movie.MovieAdmin/DeleteMovie"
+)
+
+// MovieAdminClient is the client API for MovieAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MovieAdminClient interface {
+	GetVersionedMovie(ctx context.Context, in *GetMovieRequest, opts ...grpc.CallOption) (*VersionedMovie, error)
+	CreateMovie(ctx context.Context, in *CreateMovieRequest, opts ...grpc.CallOption) (*VersionedMovie, error)
+	UpdateMovie(ctx context.Context, in *UpdateMovieRequest, opts ...grpc.CallOption) (*VersionedMovie, error)
+	DeleteMovie(ctx context.Context, in *DeleteMovieRequest, opts ...grpc.CallOption) (*DeleteMovieResponse, error)
+}
+
+type movieAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMovieAdminClient(cc grpc.ClientConnInterface) MovieAdminClient {
+	return &movieAdminClient{cc}
+}
+
+func (c *movieAdminClient) GetVersionedMovie(ctx context.Context, in *GetMovieRequest, opts ...grpc.CallOption) (*VersionedMovie, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VersionedMovie)
+	err := c.cc.Invoke(ctx, MovieAdmin_GetVersionedMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieAdminClient) CreateMovie(ctx context.Context, in *CreateMovieRequest, opts ...grpc.CallOption) (*VersionedMovie, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VersionedMovie)
+	err := c.cc.Invoke(ctx, MovieAdmin_CreateMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieAdminClient) UpdateMovie(ctx context.Context, in *UpdateMovieRequest, opts ...grpc.CallOption) (*VersionedMovie, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VersionedMovie)
+	err := c.cc.Invoke(ctx, MovieAdmin_UpdateMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieAdminClient) DeleteMovie(ctx context.Context, in *DeleteMovieRequest, opts ...grpc.CallOption) (*DeleteMovieResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteMovieResponse)
+	err := c.cc.Invoke(ctx, MovieAdmin_DeleteMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MovieAdminServer is the server API for MovieAdmin service.
+// All implementations must embed UnimplementedMovieAdminServer
+// for forward compatibility.
+type MovieAdminServer interface {
+	GetVersionedMovie(context.Context, *GetMovieRequest) (*VersionedMovie, error)
+	CreateMovie(context.Context, *CreateMovieRequest) (*VersionedMovie, error)
+	UpdateMovie(context.Context, *UpdateMovieRequest) (*VersionedMovie, error)
+	DeleteMovie(context.Context, *DeleteMovieRequest) (*DeleteMovieResponse, error)
+	mustEmbedUnimplementedMovieAdminServer()
+}
+
+// UnimplementedMovieAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMovieAdminServer struct{}
+
+func (UnimplementedMovieAdminServer) GetVersionedMovie(context.Context, *GetMovieRequest) (*VersionedMovie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersionedMovie not implemented")
+}
+func (UnimplementedMovieAdminServer) CreateMovie(context.Context, *CreateMovieRequest) (*VersionedMovie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMovie not implemented")
+}
+func (UnimplementedMovieAdminServer) UpdateMovie(context.Context, *UpdateMovieRequest) (*VersionedMovie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMovie not implemented")
+}
+func (UnimplementedMovieAdminServer) DeleteMovie(context.Context, *DeleteMovieRequest) (*DeleteMovieResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMovie not implemented")
+}
+func (UnimplementedMovieAdminServer) mustEmbedUnimplementedMovieAdminServer() {}
+func (UnimplementedMovieAdminServer) testEmbeddedByValue()                    {}
+
+// UnsafeMovieAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MovieAdminServer will
+// result in compilation errors.
+type UnsafeMovieAdminServer interface {
+	mustEmbedUnimplementedMovieAdminServer()
+}
+
+func RegisterMovieAdminServer(s grpc.ServiceRegistrar, srv MovieAdminServer) {
+	// If the following call pancis, it indicates UnimplementedMovieAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MovieAdmin_ServiceDesc, srv)
+}
+
+func _MovieAdmin_GetVersionedMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieAdminServer).GetVersionedMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieAdmin_GetVersionedMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieAdminServer).GetVersionedMovie(ctx, req.(*GetMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieAdmin_CreateMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieAdminServer).CreateMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieAdmin_CreateMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieAdminServer).CreateMovie(ctx, req.(*CreateMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieAdmin_UpdateMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieAdminServer).UpdateMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieAdmin_UpdateMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieAdminServer).UpdateMovie(ctx, req.(*UpdateMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieAdmin_DeleteMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieAdminServer).DeleteMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieAdmin_DeleteMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieAdminServer).DeleteMovie(ctx, req.(*DeleteMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MovieAdmin_ServiceDesc is the grpc.ServiceDesc for MovieAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MovieAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "movie.MovieAdmin",
+	HandlerType: (*MovieAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetVersionedMovie",
+			Handler:    _MovieAdmin_GetVersionedMovie_Handler,
+		},
+		{
+			MethodName: "CreateMovie",
+			Handler:    _MovieAdmin_CreateMovie_Handler,
+		},
+		{
+			MethodName: "UpdateMovie",
+			Handler:    _MovieAdmin_UpdateMovie_Handler,
+		},
+		{
+			MethodName: "DeleteMovie",
+			Handler:    _MovieAdmin_DeleteMovie_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "movie_services.proto",
+}
+
+const (
+	ServerAdmin_SetLogLevel_FullMethodName = "/movie.ServerAdmin/SetLogLevel"
+)
+
+// ServerAdminClient is the client API for ServerAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ServerAdminClient interface {
+	// Changes the server's log level until it restarts or the level is changed again
+	SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error)
+}
+
+type serverAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewServerAdminClient(cc grpc.ClientConnInterface) ServerAdminClient {
+	return &serverAdminClient{cc}
+}
+
+func (c *serverAdminClient) SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLogLevelResponse)
+	err := c.cc.Invoke(ctx, ServerAdmin_SetLogLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ServerAdminServer is the server API for ServerAdmin service.
+// All implementations must embed UnimplementedServerAdminServer
+// for forward compatibility.
+type ServerAdminServer interface {
+	// Changes the server's log level until it restarts or the level is changed again
+	SetLogLevel(context.Context, *SetLogLevelRequest) (*SetLogLevelResponse, error)
+	mustEmbedUnimplementedServerAdminServer()
+}
+
+// UnimplementedServerAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedServerAdminServer struct{}
+
+func (UnimplementedServerAdminServer) SetLogLevel(context.Context, *SetLogLevelRequest) (*SetLogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (UnimplementedServerAdminServer) mustEmbedUnimplementedServerAdminServer() {}
+func (UnimplementedServerAdminServer) testEmbeddedByValue()                     {}
+
+// UnsafeServerAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ServerAdminServer will
+// result in compilation errors.
+type UnsafeServerAdminServer interface {
+	mustEmbedUnimplementedServerAdminServer()
+}
+
+func RegisterServerAdminServer(s grpc.ServiceRegistrar, srv ServerAdminServer) {
+	// If the following call pancis, it indicates UnimplementedServerAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ServerAdmin_ServiceDesc, srv)
+}
+
+func _ServerAdmin_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerAdminServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerAdmin_SetLogLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerAdminServer).SetLogLevel(ctx, req.(*SetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ServerAdmin_ServiceDesc is the grpc.ServiceDesc for ServerAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ServerAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "movie.ServerAdmin",
+	HandlerType: (*ServerAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _ServerAdmin_SetLogLevel_Handler,
+		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "movie_services.proto",
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -10,8 +11,12 @@ import (
 	"net/http"
 	"os"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"case-studies/grpc/internal/config"
 	internalMovie "case-studies/grpc/internal/movie"
 	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/validation"
 )
 
 const (
@@ -27,12 +32,28 @@ func main() {
 	caCert := flag.String("ca_cert", "", "Path to CA certificate")
 	flag.Parse()
 
-	observability.SetupLogger("info")
+	observability.SetupLogger("info", config.LoadLogRedactKeys()...)
 	observability.LogStartup(AppType, AppName, map[string]interface{}{
 		"server":     *serverAddr,
 		"min_rating": *minRating,
 	})
 
+	tracing := config.LoadTracingConfig()
+	if err := validation.ValidateTracesExporter(tracing.TracesExporter); err != nil {
+		observability.LogError("config-validation", "main", err, map[string]interface{}{
+			"field": "traces_exporter",
+		})
+		os.Exit(1)
+	}
+	shutdownTracing, err := observability.SetupTracing(context.Background(), AppName+"-"+AppType, tracing.TracesExporter, tracing.OTLPEndpoint)
+	if err != nil {
+		observability.LogError("tracing-setup", "main", err, map[string]interface{}{
+			"exporter": tracing.TracesExporter,
+		})
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
 	var httpClient *http.Client
 	if *clientCert != "" && *clientKey != "" {
 		cert, err := tls.LoadX509KeyPair(*clientCert, *clientKey)
@@ -70,23 +91,35 @@ func main() {
 			MinVersion:   tls.VersionTLS12,
 		}
 		transport := &http.Transport{TLSClientConfig: tlsConfig}
-		httpClient = &http.Client{Transport: transport}
+		httpClient = &http.Client{Transport: otelhttp.NewTransport(transport)}
 	} else {
-		httpClient = http.DefaultClient
+		httpClient = &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
 	}
 
 	url := fmt.Sprintf("%s/movies?min_rating=%f", *serverAddr, *minRating)
 
-	resp, err := httpClient.Get(url)
+	ctx, span := observability.StartSpan(context.Background(), "movies-fetch")
+	defer span.End()
+	requestID := observability.NewRequestID()
+	ctx = observability.ContextWithRequestID(ctx, requestID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		observability.LogErrorContext(ctx, "http-request", "main", err, map[string]interface{}{
+			"url": url,
+		})
+		os.Exit(1)
+	}
+	req.Header.Set(observability.RequestIDHeader, requestID)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		observability.LogError("http-request", "main", err, map[string]interface{}{
+		observability.LogErrorContext(ctx, "http-request", "main", err, map[string]interface{}{
 			"url": url,
 		})
 		os.Exit(1)
 	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		observability.LogError("http-response", "main", fmt.Errorf("server returned error status"), map[string]interface{}{
+		observability.LogErrorContext(ctx, "http-response", "main", fmt.Errorf("server returned error status"), map[string]interface{}{
 			"status_code": resp.StatusCode,
 			"body":        string(body),
 		})
@@ -95,13 +128,13 @@ func main() {
 	}
 	var moviesResp internalMovie.MovieResponse
 	if err := json.NewDecoder(resp.Body).Decode(&moviesResp); err != nil {
-		observability.LogError("json-decode", "main", err, nil)
+		observability.LogErrorContext(ctx, "json-decode", "main", err, nil)
 		resp.Body.Close()
 		os.Exit(1)
 	}
 	resp.Body.Close()
 
-	observability.LogSuccess("movies-fetch", "main", map[string]interface{}{
+	observability.LogSuccessContext(ctx, "movies-fetch", "main", map[string]interface{}{
 		"movie_count": moviesResp.MovieCount,
 		"url":         url,
 	})
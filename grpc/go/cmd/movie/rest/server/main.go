@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/json"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"case-studies/grpc/internal/config"
+	"case-studies/grpc/internal/metrics"
+	"case-studies/grpc/internal/middleware"
 	internalMovie "case-studies/grpc/internal/movie"
+	"case-studies/grpc/internal/movie/rest"
 	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/shutdown"
 	"case-studies/grpc/internal/validation"
 )
 
@@ -20,62 +28,75 @@ const (
 	AppName = "movie"
 )
 
-func loadMovies(path string) ([]internalMovie.Movie, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-	var movies []internalMovie.Movie
-	if err := json.NewDecoder(file).Decode(&movies); err != nil {
-		return nil, err
-	}
-	return movies, nil
-}
-
-func moviesHandler(w http.ResponseWriter, r *http.Request) {
-	minRatingStr := r.URL.Query().Get("min_rating")
-	minRating := float32(0.0)
-	if minRatingStr != "" {
-		val, err := strconv.ParseFloat(minRatingStr, 32)
-		if err != nil {
-			http.Error(w, "Invalid min_rating", http.StatusBadRequest)
-			return
-		}
-		minRating = float32(val)
-		if err := validation.ValidateMovieRatings(minRating); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-	}
-	movies, err := loadMovies("./assets/movie-data.json")
-	if err != nil {
-		http.Error(w, "Failed to load movies", http.StatusInternalServerError)
-		return
-	}
-	filtered := make([]internalMovie.Movie, 0)
-	for _, m := range movies {
-		if m.RatingsScore > minRating {
-			filtered = append(filtered, m)
-		}
-	}
-	resp := internalMovie.MovieResponse{Movies: filtered, MovieCount: len(filtered)}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
-}
-
 func main() {
 	serverCert := flag.String("server_cert", "", "Path to server certificate")
 	serverKey := flag.String("server_key", "", "Path to server private key")
 	caCert := flag.String("ca_cert", "", "Path to client CA certificate (for mTLS)")
 	addr := flag.String("addr", ":8080", "Address to listen on")
+	assetsFilePath := flag.String("assets-file-path", "", "The file path for assets (overrides ASSETS_FILE_PATH env var)")
+	movieRepository := flag.String("movie-repository", "", "Movie repository backend (overrides MOVIE_REPOSITORY env var)")
+	movieDatabasePath := flag.String("movie-database-path", "", "SQLite database path (overrides MOVIE_DATABASE_PATH env var)")
+	metricsPort := flag.Int("metrics-port", -1, "The port serving /metrics, 0 disables (overrides METRICS_PORT env var)")
 	flag.Parse()
 
-	observability.SetupLogger("info")
+	observability.SetupLogger("info", config.LoadLogRedactKeys()...)
+
+	cfg := config.LoadServerConfig()
+	if *assetsFilePath != "" {
+		cfg.AssetsFilePath = *assetsFilePath
+	}
+	if *movieRepository != "" {
+		cfg.MovieRepository = *movieRepository
+	}
+	if *movieDatabasePath != "" {
+		cfg.MovieDatabasePath = *movieDatabasePath
+	}
+	if *metricsPort >= 0 {
+		cfg.MetricsPort = *metricsPort
+	}
+	if err := validation.ValidateMovieRepository(cfg.MovieRepository); err != nil {
+		observability.LogError("config-validation", "main", err, map[string]interface{}{
+			"field": "movie_repository",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateTracesExporter(cfg.Tracing.TracesExporter); err != nil {
+		observability.LogError("config-validation", "main", err, map[string]interface{}{
+			"field": "traces_exporter",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateShutdownPeriods(cfg.ShutdownDrainPeriod, cfg.ShutdownTimeout); err != nil {
+		observability.LogError("config-validation", "main", err, map[string]interface{}{
+			"field": "shutdown",
+		})
+		os.Exit(1)
+	}
+	_, addrPort, _ := net.SplitHostPort(*addr)
+	servingPort, _ := strconv.Atoi(addrPort)
+	if err := validation.ValidateMetricsPort(cfg.MetricsPort, servingPort); err != nil {
+		observability.LogError("config-validation", "main", err, map[string]interface{}{
+			"field": "metrics_port",
+		})
+		os.Exit(1)
+	}
 	observability.LogStartup(AppType, AppName, map[string]interface{}{
 		"address": *addr,
 	})
 
+	shutdownTracing, err := observability.SetupTracing(context.Background(), AppName+"-"+AppType, cfg.Tracing.TracesExporter, cfg.Tracing.OTLPEndpoint)
+	if err != nil {
+		observability.LogError("tracing-setup", "main", err, map[string]interface{}{
+			"exporter": cfg.Tracing.TracesExporter,
+		})
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		shutdownTracing(ctx)
+	}()
+
 	if *serverCert == "" || *serverKey == "" {
 		observability.LogError("config-validation", "main", fmt.Errorf("server_cert and server_key are required for mTLS"), nil)
 		os.Exit(1)
@@ -103,18 +124,64 @@ func main() {
 		tlsConfig = &tls.Config{}
 	}
 
-	http.HandleFunc("/movies", moviesHandler)
+	repository, err := internalMovie.OpenMovieRepository(context.Background(), internalMovie.RepositoryOptions{
+		Backend:        cfg.MovieRepository,
+		AssetsFilePath: cfg.AssetsFilePath,
+		DatabasePath:   cfg.MovieDatabasePath,
+	})
+	if err != nil {
+		observability.LogError("movie-repository-open", "main", err, map[string]interface{}{
+			"backend": cfg.MovieRepository,
+		})
+		os.Exit(1)
+	}
+
+	metricsCtx, stopMetrics := context.WithCancel(context.Background())
+	defer stopMetrics()
+	var serverMetrics *metrics.Metrics
+	if cfg.MetricsPort > 0 {
+		serverMetrics = metrics.New("http")
+		go func() {
+			if err := serverMetrics.ListenAndServe(metricsCtx, cfg.MetricsPort, nil); err != nil {
+				observability.LogError("metrics-serve", "main", err, map[string]interface{}{
+					"port": cfg.MetricsPort,
+				})
+			}
+		}()
+	}
+
+	// otelhttp continues the caller's trace from the traceparent header and wraps the request in a span
+	http.Handle("/movies", otelhttp.NewHandler(middleware.RequestIDHandler(serverMetrics.InstrumentHandler("GET /movies", &rest.MoviesHandler{Repository: repository})), "GET /movies"))
 	server := &http.Server{
 		Addr:      *addr,
 		TLSConfig: tlsConfig,
 	}
 
 	observability.LogSuccess("server-listen", "main", map[string]interface{}{
-		"address": *addr,
+		"address":      *addr,
+		"metrics_port": cfg.MetricsPort,
 	})
 
-	if err := server.ListenAndServeTLS(*serverCert, *serverKey); err != nil {
+	ctx, stop := shutdown.NotifyContext(context.Background())
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServeTLS(*serverCert, *serverKey)
+	}()
+
+	select {
+	case err := <-serveErr:
 		observability.LogError("server-serve", "main", err, nil)
 		os.Exit(1)
+	case <-ctx.Done():
 	}
+
+	observability.LogShutdown(AppType, AppName)
+	err = shutdown.HTTP(server, shutdown.Options{
+		DrainPeriod: cfg.ShutdownDrainPeriod,
+		Timeout:     cfg.ShutdownTimeout,
+	})
+	observability.LogSuccess("server-stop", "main", map[string]interface{}{
+		"graceful": err == nil,
+	})
 }
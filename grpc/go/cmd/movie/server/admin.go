@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	movie "case-studies/grpc/cmd/movie"
+	internalMovie "case-studies/grpc/internal/movie"
+	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/validation"
+)
+
+// adminServer implements MovieAdmin on top of a repository that persists writes
+type adminServer struct {
+	movie.UnimplementedMovieAdminServer
+	repository internalMovie.WritableMovieRepository
+
+	// onWrite refreshes state derived from the repository, such as the Getter search index
+	onWrite func(ctx context.Context) error
+}
+
+func (server *adminServer) GetVersionedMovie(ctx context.Context, input *movie.GetMovieRequest) (*movie.VersionedMovie, error) {
+	if err := validation.ValidateMovieID(input.GetMovieId()); err != nil {
+		observability.LogErrorContext(ctx, "validation", "GetVersionedMovie", err, map[string]interface{}{
+			"movie_id": input.GetMovieId(),
+		})
+		return nil, err
+	}
+
+	m, err := server.repository.Get(ctx, input.GetMovieId())
+	if err != nil {
+		return nil, adminError(err, input.GetMovieId())
+	}
+
+	etag, err := internalMovie.MovieETag(m)
+	if err != nil {
+		observability.LogErrorContext(ctx, "movie-etag", "GetVersionedMovie", err, map[string]interface{}{
+			"movie_id": input.GetMovieId(),
+		})
+		return nil, adminError(err, input.GetMovieId())
+	}
+
+	return &movie.VersionedMovie{Movie: m, Etag: etag}, nil
+}
+
+func (server *adminServer) CreateMovie(ctx context.Context, input *movie.CreateMovieRequest) (*movie.VersionedMovie, error) {
+	start := time.Now()
+	observability.LogSuccessContext(ctx, "movie-create-start", "CreateMovie", map[string]interface{}{
+		"movie_id": input.GetMovie().GetMovieId(),
+	})
+
+	if err := validateMovie(input.GetMovie()); err != nil {
+		observability.LogErrorContext(ctx, "validation", "CreateMovie", err, map[string]interface{}{
+			"movie_id": input.GetMovie().GetMovieId(),
+		})
+		return nil, err
+	}
+
+	stored, err := server.repository.Create(ctx, input.GetMovie())
+	if err != nil {
+		observability.LogErrorContext(ctx, "movie-create", "CreateMovie", err, map[string]interface{}{
+			"movie_id": input.GetMovie().GetMovieId(),
+		})
+		return nil, adminError(err, input.GetMovie().GetMovieId())
+	}
+	server.refresh(ctx, "CreateMovie")
+
+	etag, err := internalMovie.MovieETag(stored)
+	if err != nil {
+		observability.LogErrorContext(ctx, "movie-etag", "CreateMovie", err, map[string]interface{}{
+			"movie_id": stored.GetMovieId(),
+		})
+		return nil, adminError(err, stored.GetMovieId())
+	}
+	observability.LogSuccessContext(ctx, "movie-create", "CreateMovie", map[string]interface{}{
+		"movie_id": stored.GetMovieId(),
+		"etag":     etag,
+		"duration": time.Since(start),
+	})
+
+	return &movie.VersionedMovie{Movie: stored, Etag: etag}, nil
+}
+
+func (server *adminServer) UpdateMovie(ctx context.Context, input *movie.UpdateMovieRequest) (*movie.VersionedMovie, error) {
+	start := time.Now()
+	movieID := input.GetMovie().GetMovieId()
+	observability.LogSuccessContext(ctx, "movie-update-start", "UpdateMovie", map[string]interface{}{
+		"movie_id":    movieID,
+		"update_mask": input.GetUpdateMask().GetPaths(),
+	})
+
+	if err := validation.ValidateMovieID(movieID); err != nil {
+		observability.LogErrorContext(ctx, "validation", "UpdateMovie", err, map[string]interface{}{
+			"movie_id": movieID,
+		})
+		return nil, err
+	}
+	if err := validation.ValidateETag(input.GetEtag()); err != nil {
+		observability.LogErrorContext(ctx, "validation", "UpdateMovie", err, map[string]interface{}{
+			"movie_id": movieID,
+		})
+		return nil, err
+	}
+
+	current, err := server.repository.Get(ctx, movieID)
+	if err != nil {
+		return nil, adminError(err, movieID)
+	}
+	// Fail fast on a stale etag; the repository re-checks it atomically with the write
+	currentETag, err := internalMovie.MovieETag(current)
+	if err != nil {
+		observability.LogErrorContext(ctx, "movie-etag", "UpdateMovie", err, map[string]interface{}{
+			"movie_id": movieID,
+		})
+		return nil, adminError(err, movieID)
+	}
+	if currentETag != input.GetEtag() {
+		return nil, adminError(internalMovie.ErrETagMismatch, movieID)
+	}
+
+	updated, err := internalMovie.ApplyUpdateMask(current, input.GetMovie(), input.GetUpdateMask())
+	if err != nil {
+		observability.LogErrorContext(ctx, "validation", "UpdateMovie", err, map[string]interface{}{
+			"movie_id":    movieID,
+			"update_mask": input.GetUpdateMask().GetPaths(),
+		})
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateMovie(updated); err != nil {
+		observability.LogErrorContext(ctx, "validation", "UpdateMovie", err, map[string]interface{}{
+			"movie_id": movieID,
+		})
+		return nil, err
+	}
+
+	stored, err := server.repository.Update(ctx, updated, input.GetEtag())
+	if err != nil {
+		observability.LogErrorContext(ctx, "movie-update", "UpdateMovie", err, map[string]interface{}{
+			"movie_id": movieID,
+		})
+		return nil, adminError(err, movieID)
+	}
+	server.refresh(ctx, "UpdateMovie")
+
+	etag, err := internalMovie.MovieETag(stored)
+	if err != nil {
+		observability.LogErrorContext(ctx, "movie-etag", "UpdateMovie", err, map[string]interface{}{
+			"movie_id": movieID,
+		})
+		return nil, adminError(err, movieID)
+	}
+	observability.LogSuccessContext(ctx, "movie-update", "UpdateMovie", map[string]interface{}{
+		"movie_id": movieID,
+		"etag":     etag,
+		"duration": time.Since(start),
+	})
+
+	return &movie.VersionedMovie{Movie: stored, Etag: etag}, nil
+}
+
+func (server *adminServer) DeleteMovie(ctx context.Context, input *movie.DeleteMovieRequest) (*movie.DeleteMovieResponse, error) {
+	start := time.Now()
+	observability.LogSuccessContext(ctx, "movie-delete-start", "DeleteMovie", map[string]interface{}{
+		"movie_id": input.GetMovieId(),
+	})
+
+	if err := validation.ValidateMovieID(input.GetMovieId()); err != nil {
+		observability.LogErrorContext(ctx, "validation", "DeleteMovie", err, map[string]interface{}{
+			"movie_id": input.GetMovieId(),
+		})
+		return nil, err
+	}
+	if err := validation.ValidateETag(input.GetEtag()); err != nil {
+		observability.LogErrorContext(ctx, "validation", "DeleteMovie", err, map[string]interface{}{
+			"movie_id": input.GetMovieId(),
+		})
+		return nil, err
+	}
+
+	if err := server.repository.Delete(ctx, input.GetMovieId(), input.GetEtag()); err != nil {
+		observability.LogErrorContext(ctx, "movie-delete", "DeleteMovie", err, map[string]interface{}{
+			"movie_id": input.GetMovieId(),
+		})
+		return nil, adminError(err, input.GetMovieId())
+	}
+	server.refresh(ctx, "DeleteMovie")
+
+	observability.LogSuccessContext(ctx, "movie-delete", "DeleteMovie", map[string]interface{}{
+		"movie_id": input.GetMovieId(),
+		"duration": time.Since(start),
+	})
+
+	return &movie.DeleteMovieResponse{}, nil
+}
+
+// refresh rebuilds derived state after a write; the write itself has already succeeded
+func (server *adminServer) refresh(ctx context.Context, function string) {
+	if server.onWrite == nil {
+		return
+	}
+	if err := server.onWrite(ctx); err != nil {
+		observability.LogErrorContext(ctx, "movie-data-load", function, err, nil)
+	}
+}
+
+func validateMovie(m *movie.Movie) error {
+	if m == nil {
+		return status.Error(codes.InvalidArgument, "movie cannot be empty")
+	}
+	if err := validation.ValidateMovieID(m.GetMovieId()); err != nil {
+		return err
+	}
+	if err := validation.ValidateString(m.GetTitle(), "title", 200, false); err != nil {
+		return err
+	}
+	if m.GetReleaseDate() != "" {
+		if err := validation.ValidateReleaseDate(m.GetReleaseDate()); err != nil {
+			return err
+		}
+	}
+	for _, genre := range m.GetGenre() {
+		if err := validation.ValidateString(genre, "genre", 100, false); err != nil {
+			return err
+		}
+	}
+	return validation.ValidateMovieRatings(m.GetRatingsScore())
+}
+
+func adminError(err error, movieID string) error {
+	switch {
+	case errors.Is(err, internalMovie.ErrMovieNotFound):
+		return status.Errorf(codes.NotFound, "movie %q not found", movieID)
+	case errors.Is(err, internalMovie.ErrMovieExists):
+		return status.Errorf(codes.AlreadyExists, "movie %q already exists", movieID)
+	case errors.Is(err, internalMovie.ErrETagMismatch):
+		return status.Errorf(codes.Aborted, "movie %q has been modified; fetch the latest version and retry", movieID)
+	case errors.Is(err, internalMovie.ErrMovieETag):
+		return status.Errorf(codes.Internal, "movie %q could not be encoded", movieID)
+	case errors.Is(err, internalMovie.ErrDataFileInvalid):
+		return status.Errorf(codes.FailedPrecondition, "movie data file failed to reload; fix it before editing movies: %v", err)
+	default:
+		return err
+	}
+}
@@ -1,24 +1,37 @@
 package main
 
 import (
-	"crypto/tls"
-	"crypto/x509"
+	"context"
 	"flag"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health"
 	grpc_health_v1 "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	"case-studies/grpc/cmd/movie"
+	"case-studies/grpc/internal/apikey"
+	"case-studies/grpc/internal/auth"
 	"case-studies/grpc/internal/config"
+	"case-studies/grpc/internal/metrics"
 	"case-studies/grpc/internal/middleware"
+	internalMovie "case-studies/grpc/internal/movie"
 	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/pagination"
+	"case-studies/grpc/internal/ratelimit"
+	"case-studies/grpc/internal/readiness"
+	"case-studies/grpc/internal/shutdown"
+	"case-studies/grpc/internal/tlsconfig"
 	"case-studies/grpc/internal/validation"
 )
 
@@ -27,10 +40,32 @@ const (
 	AppName = "movie"
 )
 
+// Readiness is checked again every readinessInterval. A server certificate expiring within
+// readinessMinCertValidity makes the server not ready, so traffic moves before handshakes fail.
+const (
+	readinessInterval        = 10 * time.Second
+	readinessMinCertValidity = 24 * time.Hour
+)
+
 func loadConfig() *config.ServerConfig {
 	flagPort := flag.Int("port", config.DefaultPort, "The server port")
+	flagMetricsPort := flag.Int("metrics-port", config.DefaultMetricsPort, "The port serving /metrics (0 disables)")
+	flagProbePort := flag.Int("probe-port", config.DefaultProbePort, "The port serving /healthz and /readyz (0 serves them on the metrics port)")
 	flagAssetsFilePath := flag.String("assets-file-path", config.DefaultAssetsFilePath, "The file path for assets")
 	flagLogLevel := flag.String("log-level", config.DefaultLogLevel, "Log level (debug, info, warn, error)")
+	flagMovieRepository := flag.String("movie-repository", config.DefaultMovieRepository, "Movie repository backend (json, sqlite)")
+	flagMovieReloadInterval := flag.Duration("movie-reload-interval", config.DefaultMovieReloadInterval, "How often to check movie-data.json for changes (0 disables)")
+	flagMovieDatabasePath := flag.String("movie-database-path", "", "SQLite database path (defaults to movie-data.db under the assets file path)")
+	flagAuthMode := flag.String("auth-mode", config.DefaultAuthMode, "Accepted credentials (api-key, jwt, both)")
+	flagShutdownDrainPeriod := flag.Duration("shutdown-drain-period", config.DefaultShutdownDrainPeriod, "How long to keep serving after reporting NOT_SERVING on SIGTERM")
+	flagShutdownTimeout := flag.Duration("shutdown-timeout", config.DefaultShutdownTimeout, "How long running calls may take after the drain before they are cut off")
+	flagMTLSPolicyFile := flag.String("mtls-policy-file", "", "Policy mapping client certificate identities to allowed RPCs (relative to the assets file path; empty disables)")
+	flagTLSMode := flag.String("tls-mode", "", "How to secure connections (mtls, tls, insecure); defaults to TLS_MODE, api-config.yaml or mtls")
+	flagTLSCertFile := flag.String("tls-cert-file", "", "Server certificate, relative to the assets file path (defaults to tls/server-public.key)")
+	flagTLSKeyFile := flag.String("tls-key-file", "", "Server private key, relative to the assets file path (defaults to tls/server-private.key)")
+	flagTLSCAFile := flag.String("tls-ca-file", "", "CA certificates verifying clients in mtls mode, relative to the assets file path (defaults to tls/ca-public.key)")
+	flagTLSMinVersion := flag.String("tls-min-version", "", "Minimum TLS version (1.2, 1.3); defaults to TLS_MIN_VERSION, api-config.yaml or 1.2")
+	flagTLSCipherSuites := flag.String("tls-cipher-suites", "", "Comma-separated TLS 1.2 cipher suites (defaults to Go's)")
 
 	flag.Parse()
 
@@ -39,6 +74,12 @@ func loadConfig() *config.ServerConfig {
 	if flag.CommandLine.Lookup("port").Value.String() != fmt.Sprintf("%d", config.DefaultPort) || flag.NFlag() > 0 {
 		baseConfig.Port = *flagPort
 	}
+	if *flagMetricsPort != config.DefaultMetricsPort {
+		baseConfig.MetricsPort = *flagMetricsPort
+	}
+	if *flagProbePort != config.DefaultProbePort {
+		baseConfig.ProbePort = *flagProbePort
+	}
 	if flag.CommandLine.Lookup("assets-file-path").Value.String() != config.DefaultAssetsFilePath || flag.NFlag() > 0 {
 		baseConfig.AssetsFilePath = *flagAssetsFilePath
 	}
@@ -48,12 +89,70 @@ func loadConfig() *config.ServerConfig {
 		baseConfig.LogLevel = *flagLogLevel
 	}
 
+	if flag.CommandLine.Lookup("movie-repository").Value.String() != config.DefaultMovieRepository {
+		baseConfig.MovieRepository = *flagMovieRepository
+	}
+	if *flagMovieDatabasePath != "" {
+		baseConfig.MovieDatabasePath = *flagMovieDatabasePath
+	}
+	if *flagMovieReloadInterval != config.DefaultMovieReloadInterval {
+		baseConfig.MovieReloadInterval = *flagMovieReloadInterval
+	}
+	if *flagAuthMode != config.DefaultAuthMode {
+		baseConfig.AuthMode = *flagAuthMode
+	}
+	if *flagMTLSPolicyFile != "" {
+		baseConfig.MTLSPolicyFile = *flagMTLSPolicyFile
+	}
+	if *flagShutdownDrainPeriod != config.DefaultShutdownDrainPeriod {
+		baseConfig.ShutdownDrainPeriod = *flagShutdownDrainPeriod
+	}
+	if *flagShutdownTimeout != config.DefaultShutdownTimeout {
+		baseConfig.ShutdownTimeout = *flagShutdownTimeout
+	}
+	if *flagTLSMode != "" {
+		baseConfig.TLS.Mode = *flagTLSMode
+	}
+	if *flagTLSCertFile != "" {
+		baseConfig.TLS.CertFile = *flagTLSCertFile
+	}
+	if *flagTLSKeyFile != "" {
+		baseConfig.TLS.KeyFile = *flagTLSKeyFile
+	}
+	if *flagTLSCAFile != "" {
+		baseConfig.TLS.CAFile = *flagTLSCAFile
+	}
+	if *flagTLSMinVersion != "" {
+		baseConfig.TLS.MinVersion = *flagTLSMinVersion
+	}
+	if *flagTLSCipherSuites != "" {
+		baseConfig.TLS.CipherSuites = config.SplitList(*flagTLSCipherSuites)
+	}
+
 	if err := validation.ValidatePort(baseConfig.Port); err != nil {
 		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
 			"field": "port",
 		})
 		os.Exit(1)
 	}
+	if err := validation.ValidateMetricsPort(baseConfig.MetricsPort, baseConfig.Port); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "metrics_port",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateProbePort(baseConfig.ProbePort, baseConfig.Port, baseConfig.MetricsPort); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "probe_port",
+		})
+		os.Exit(1)
+	}
+	if baseConfig.MetricsPort == 0 && baseConfig.ProbePort == 0 {
+		observability.LogWarning("probes-disabled", "loadConfig", map[string]interface{}{
+			"metrics_port": baseConfig.MetricsPort,
+			"probe_port":   baseConfig.ProbePort,
+		})
+	}
 	if err := validation.ValidateAssetsFilePath(baseConfig.AssetsFilePath); err != nil {
 		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
 			"field": "assets_file_path",
@@ -72,78 +171,320 @@ func loadConfig() *config.ServerConfig {
 		})
 		os.Exit(1)
 	}
+	if err := validation.ValidateMovieRepository(baseConfig.MovieRepository); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "movie_repository",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateMovieReloadInterval(baseConfig.MovieReloadInterval); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "movie_reload_interval",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateShutdownPeriods(baseConfig.ShutdownDrainPeriod, baseConfig.ShutdownTimeout); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "shutdown",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateTLSMode(baseConfig.TLS.Mode); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "tls_mode",
+		})
+		os.Exit(1)
+	}
+	// Policies match client certificates, which only mutual TLS asks for
+	if baseConfig.MTLSPolicyFile != "" && baseConfig.TLS.Mode != config.TLSModeMTLS {
+		observability.LogError("config-validation", "loadConfig", fmt.Errorf("an mTLS policy file requires TLS mode mtls"), map[string]interface{}{
+			"field": "mtls_policy_file",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateTLSProtocol(baseConfig.TLS.MinVersion, baseConfig.TLS.CipherSuites); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "tls",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateTLSReload(baseConfig.TLS.ReloadInterval, baseConfig.TLS.ExpiryWarning); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "tls",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateLogSampling(baseConfig.Logging.Sampling); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "logging.sampling",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateTracesExporter(baseConfig.Tracing.TracesExporter); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "traces_exporter",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateAuthMode(baseConfig.AuthMode); err != nil {
+		observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+			"field": "auth_mode",
+		})
+		os.Exit(1)
+	}
+	for _, k := range baseConfig.APIKeys {
+		if err := validation.ValidateAPIKeyLimits(k.RateLimit, k.RateBurst, k.DailyQuota); err != nil {
+			observability.LogError("config-validation", "loadConfig", err, map[string]interface{}{
+				"field":        "api_keys",
+				"api_key_name": k.Name,
+			})
+			os.Exit(1)
+		}
+	}
 
 	return baseConfig
 }
 
-func createGRPCServer(cfg *config.ServerConfig) *grpc.Server {
-	var validAPIKeys []string
-	for _, k := range cfg.APIKeys {
-		validAPIKeys = append(validAPIKeys, k.Key)
+// newJWTVerifier loads the JWKS file and exits if bearer tokens cannot be verified
+func newJWTVerifier(cfg *config.ServerConfig) *auth.JWTVerifier {
+	jwksPath := cfg.JWT.JWKSFile
+	if jwksPath == "" {
+		jwksPath = config.DefaultJWKSFileName
+	}
+	if !filepath.IsAbs(jwksPath) {
+		jwksPath = filepath.Join(cfg.AssetsFilePath, jwksPath)
 	}
 
-	certFilePath := filepath.Join(cfg.AssetsFilePath, "tls", "server-public.key")
-	keyFilePath := filepath.Join(cfg.AssetsFilePath, "tls", "server-private.key")
-
-	cert, err := tls.LoadX509KeyPair(certFilePath, keyFilePath)
+	keys, err := auth.LoadJWKS(jwksPath)
 	if err != nil {
-		observability.LogError("tls-load", "createGRPCServer", err, map[string]interface{}{
-			"cert_file": certFilePath,
-			"key_file":  keyFilePath,
+		observability.LogError("jwks-load", "newJWTVerifier", err, map[string]interface{}{
+			"jwks_file": jwksPath,
 		})
 		os.Exit(1)
 	}
+	verifier, err := auth.NewJWTVerifier(keys, auth.VerifierOptions{
+		Issuer:       cfg.JWT.Issuer,
+		Audience:     cfg.JWT.Audience,
+		ScopeClaim:   cfg.JWT.ScopeClaim,
+		ScopeMapping: cfg.JWT.ScopeMapping,
+		ClockSkew:    cfg.JWT.ClockSkew,
+	})
+	if err != nil {
+		observability.LogError("jwt-setup", "newJWTVerifier", err, nil)
+		os.Exit(1)
+	}
+
+	observability.LogSuccess("jwt-setup", "newJWTVerifier", map[string]interface{}{
+		"jwks_file": jwksPath,
+		"issuer":    cfg.JWT.Issuer,
+		"audience":  cfg.JWT.Audience,
+	})
+	return verifier
+}
+
+// loadPeerPolicy reads the mTLS policy file, returning nil when none is configured
+func loadPeerPolicy(cfg *config.ServerConfig) *auth.PeerPolicy {
+	if cfg.MTLSPolicyFile == "" {
+		return nil
+	}
+	policyPath := cfg.MTLSPolicyFile
+	if !filepath.IsAbs(policyPath) {
+		policyPath = filepath.Join(cfg.AssetsFilePath, policyPath)
+	}
 
-	// Load the CA certificate for client verification
-	caCertPath := filepath.Join(cfg.AssetsFilePath, "tls", "ca-public.key")
-	caCert, err := os.ReadFile(caCertPath)
+	policy, err := auth.LoadPeerPolicy(policyPath)
 	if err != nil {
-		observability.LogError("ca-cert-read", "createGRPCServer", err, map[string]interface{}{
-			"cert_file": caCertPath,
+		observability.LogError("mtls-policy-load", "loadPeerPolicy", err, map[string]interface{}{
+			"policy_file": policyPath,
 		})
 		os.Exit(1)
 	}
-	caPool := x509.NewCertPool()
-	if !caPool.AppendCertsFromPEM(caCert) {
-		observability.LogError("ca-cert-append", "createGRPCServer", fmt.Errorf("failed to append CA certificate to pool"), nil)
+
+	observability.LogSuccess("mtls-policy-load", "loadPeerPolicy", map[string]interface{}{
+		"policy_file": policyPath,
+	})
+	return policy
+}
+
+// createTransportCredentials secures connections as cfg.TLS.Mode asks. The reloader it returns
+// picks up rotated certificates and CA bundles until ctx is cancelled, and is nil in insecure mode.
+func createTransportCredentials(ctx context.Context, cfg *config.ServerConfig, serverMetrics *metrics.Metrics) (credentials.TransportCredentials, *tlsconfig.Reloader) {
+	if cfg.TLS.Mode == config.TLSModeInsecure {
+		observability.LogWarning("tls-disabled", "createTransportCredentials", map[string]interface{}{
+			"tls_mode": cfg.TLS.Mode,
+		})
+		return insecure.NewCredentials(), nil
+	}
+
+	var tlsFiles tlsconfig.Files
+	tlsFiles.CertFile, tlsFiles.KeyFile, tlsFiles.CAFile = cfg.TLS.Paths(cfg.AssetsFilePath, "server")
+	if cfg.TLS.Mode == config.TLSModeTLS {
+		tlsFiles.CAFile = ""
+	}
+	// Both were validated in loadConfig
+	minVersion, _ := tlsconfig.ParseVersion(cfg.TLS.MinVersion)
+	cipherSuites, _ := tlsconfig.ParseCipherSuites(cfg.TLS.CipherSuites)
+
+	certificates, err := tlsconfig.NewReloader(tlsFiles, tlsconfig.Options{
+		CheckInterval: cfg.TLS.ReloadInterval,
+		ExpiryWarning: cfg.TLS.ExpiryWarning,
+		Metrics:       serverMetrics,
+		MinVersion:    minVersion,
+		CipherSuites:  cipherSuites,
+	})
+	if err != nil {
+		observability.LogError("tls-load", "createTransportCredentials", err, map[string]interface{}{
+			"cert_file": tlsFiles.CertFile,
+			"key_file":  tlsFiles.KeyFile,
+			"ca_file":   tlsFiles.CAFile,
+		})
 		os.Exit(1)
 	}
+	go certificates.Watch(ctx)
+
+	return credentials.NewTLS(certificates.ServerConfig()), certificates
+}
 
-	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		ClientCAs:    caPool,
+// apiKeys is the API key state reloadAPIKeys replaces on SIGHUP; authenticator is nil in jwt mode
+type apiKeys struct {
+	authenticator *apikey.Authenticator
+	limiter       *ratelimit.Limiter
+}
+
+// apiKeyLimits maps each key name to its rate limit and daily quota
+func apiKeyLimits(keys []config.APIKeyConfig) map[string]ratelimit.Limits {
+	limits := make(map[string]ratelimit.Limits, len(keys))
+	for _, k := range keys {
+		limits[k.Name] = ratelimit.Limits{RequestsPerSecond: k.RateLimit, Burst: k.RateBurst, DailyQuota: k.DailyQuota}
+	}
+	return limits
+}
+
+func createGRPCServer(ctx context.Context, stopping <-chan struct{}, cfg *config.ServerConfig, serverMetrics *metrics.Metrics) (*grpc.Server, *health.Server, *readiness.Monitor, apiKeys) {
+	var authCredentials []middleware.Credential
+	var authenticator *apikey.Authenticator
+	if cfg.AuthMode != config.AuthModeJWT {
+		var err error
+		authenticator, err = apikey.NewAuthenticator(cfg.APIKeys, cfg.APIKeyPepper, time.Now)
+		if err != nil {
+			observability.LogError("api-key-setup", "createGRPCServer", err, nil)
+			os.Exit(1)
+		}
+		authCredentials = append(authCredentials, middleware.APIKeyCredential(authenticator))
 	}
+	if cfg.AuthMode != config.AuthModeAPIKey {
+		authCredentials = append(authCredentials, middleware.BearerTokenCredential(newJWTVerifier(cfg)))
+	}
+	limiter := ratelimit.NewLimiter(apiKeyLimits(cfg.APIKeys), time.Now)
+	methodScopes := map[string]string{
+		"/" + movie.Getter_ServiceDesc.ServiceName + "/":      config.ScopeMoviesRead,
+		"/" + movie.MovieAdmin_ServiceDesc.ServiceName + "/":  config.ScopeMoviesWrite,
+		"/" + movie.ServerAdmin_ServiceDesc.ServiceName + "/": config.ScopeServerAdmin,
+	}
+	// Probes carry no credentials and must not spend a key's quota; mTLS and the peer policy still apply
+	healthMethods := "/" + grpc_health_v1.Health_ServiceDesc.ServiceName + "/"
 
-	creds := credentials.NewTLS(tlsConfig)
+	creds, certificates := createTransportCredentials(ctx, cfg, serverMetrics)
+
+	unaryInterceptors := []grpc.UnaryServerInterceptor{middleware.RequestIDInterceptor()}
+	streamInterceptors := []grpc.StreamServerInterceptor{middleware.RequestIDStreamInterceptor()}
+	if serverMetrics != nil {
+		unaryInterceptors = append(unaryInterceptors, middleware.MetricsInterceptor(serverMetrics))
+		streamInterceptors = append(streamInterceptors, middleware.MetricsStreamInterceptor(serverMetrics))
+	}
+	unaryInterceptors = append(unaryInterceptors, middleware.ExemptMethods(middleware.AuthInterceptor(authCredentials...), healthMethods))
+	streamInterceptors = append(streamInterceptors, middleware.ExemptStreamMethods(middleware.AuthStreamInterceptor(authCredentials...), healthMethods))
+	if serverMetrics != nil {
+		unaryInterceptors = append(unaryInterceptors, middleware.APIKeyUsageInterceptor(serverMetrics))
+		streamInterceptors = append(streamInterceptors, middleware.APIKeyUsageStreamInterceptor(serverMetrics))
+	}
+	if policy := loadPeerPolicy(cfg); policy != nil {
+		unaryInterceptors = append(unaryInterceptors, middleware.PeerPolicyInterceptor(policy))
+		streamInterceptors = append(streamInterceptors, middleware.PeerPolicyStreamInterceptor(policy))
+	}
+	unaryInterceptors = append(unaryInterceptors,
+		middleware.APIKeyScopeInterceptor(methodScopes),
+		middleware.ExemptMethods(middleware.RateLimitInterceptor(limiter), healthMethods),
+		middleware.LoggingInterceptor(),
+		middleware.ErrorInterceptor(),
+		middleware.RecoveryInterceptor(),
+	)
+	streamInterceptors = append(streamInterceptors,
+		middleware.APIKeyScopeStreamInterceptor(methodScopes),
+		middleware.ExemptStreamMethods(middleware.RateLimitStreamInterceptor(limiter), healthMethods),
+		middleware.LoggingStreamInterceptor(),
+		middleware.ErrorStreamInterceptor(),
+		middleware.RecoveryStreamInterceptor(),
+	)
 
 	serverOpts := []grpc.ServerOption{
 		grpc.Creds(creds),
-		grpc.ChainUnaryInterceptor(
-			middleware.APIKeyAuthInterceptor(validAPIKeys),
-			middleware.LoggingInterceptor(),
-			middleware.ErrorInterceptor(),
-			middleware.RecoveryInterceptor(),
-		),
+		// Extracts the caller's W3C trace context and wraps each call in a server span; health probes are not traced
+		grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
 	}
 
 	grpcServer := grpc.NewServer(serverOpts...)
 
-	movieServer := &server{assetsFilePath: cfg.AssetsFilePath}
+	pageTokens, err := pagination.NewTokenCodec([]byte(cfg.PageTokenSecret))
+	if err != nil {
+		observability.LogError("page-token-setup", "createGRPCServer", err, nil)
+		os.Exit(1)
+	}
+
+	repository, err := internalMovie.OpenMovieRepository(context.Background(), internalMovie.RepositoryOptions{
+		Backend:        cfg.MovieRepository,
+		AssetsFilePath: cfg.AssetsFilePath,
+		DatabasePath:   cfg.MovieDatabasePath,
+	})
+	if err != nil {
+		observability.LogError("movie-repository-open", "createGRPCServer", err, map[string]interface{}{
+			"backend": cfg.MovieRepository,
+		})
+		os.Exit(1)
+	}
 
-	movies, err := movieServer.loadMovies()
+	changes, err := internalMovie.NewChangeFeed(changeHistorySize)
 	if err != nil {
+		observability.LogError("change-feed-setup", "createGRPCServer", err, nil)
+		os.Exit(1)
+	}
+
+	movieServer := &server{repository: repository, pageTokens: pageTokens, changes: changes, metrics: serverMetrics, stopping: stopping}
+
+	if err := movieServer.loadMovies(context.Background()); err != nil {
 		observability.LogError("movie-data-load", "createGRPCServer", err, nil)
 		os.Exit(1)
 	}
-	movieServer.movies = movies
 
-	movie.RegisterGetterServer(grpcServer, movieServer)
+	checks := []readiness.Check{
+		readiness.NotEmpty("movie-catalogue", movieServer.moviesLoaded),
+	}
+	if certificates != nil {
+		checks = append(checks, readiness.CertificateValid("server-certificate", certificates.Certificate, readinessMinCertValidity, time.Now))
+	}
+
+	// Only the JSON file can change underneath the server; SQLite is updated through the importer
+	if watcher, ok := repository.(*internalMovie.JSONFileRepository); ok && cfg.MovieReloadInterval > 0 {
+		checks = append(checks, readiness.Check{Name: "movie-data-reload", Run: watcher.ReloadErr})
+		go watcher.Watch(ctx, cfg.MovieReloadInterval, func(diff internalMovie.MovieDiff) {
+			if err := movieServer.loadMovies(context.Background()); err != nil {
+				observability.LogError("movie-data-load", "Watch", err, nil)
+			}
+		})
+		observability.LogSuccess("movie-data-watch", "createGRPCServer", map[string]interface{}{
+			"interval": cfg.MovieReloadInterval.String(),
+		})
+	}
+
+	services := registerMovieServices(grpcServer, movieServer, repository)
 
 	healthServer := health.NewServer()
 	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
 
-	healthServer.SetServingStatus("movie.Getter", grpc_health_v1.HealthCheckResponse_SERVING)
+	// The API services are SERVING only while every check passes, not merely once after loading
+	monitor := readiness.NewMonitor(healthServer, services, checks...)
 
 	reflection.Register(grpcServer)
 
@@ -151,18 +492,114 @@ func createGRPCServer(cfg *config.ServerConfig) *grpc.Server {
 		"service": "movie.Getter",
 	})
 
-	return grpcServer
+	return grpcServer, healthServer, monitor, apiKeys{authenticator: authenticator, limiter: limiter}
+}
+
+// registerMovieServices registers the API services and returns their names. MovieAdmin needs a
+// repository that can persist writes; ServerAdmin operates the process and is always registered.
+func registerMovieServices(registrar grpc.ServiceRegistrar, movieServer *server, repository internalMovie.MovieRepository) []string {
+	movie.RegisterGetterServer(registrar, movieServer)
+	movie.RegisterServerAdminServer(registrar, &serverAdminServer{})
+	services := []string{movie.Getter_ServiceDesc.ServiceName, movie.ServerAdmin_ServiceDesc.ServiceName}
+
+	if writable, ok := repository.(internalMovie.WritableMovieRepository); ok {
+		movie.RegisterMovieAdminServer(registrar, &adminServer{repository: writable, onWrite: movieServer.loadMovies})
+		services = append(services, movie.MovieAdmin_ServiceDesc.ServiceName)
+	}
+	return services
+}
+
+// reloadAPIKeys applies the api_keys section of api-config.yaml again, so keys generated, revoked
+// or rotated with cmd/apikey take effect without a restart, along with changed rate limits and
+// quotas. Keys keep the requests they have already spent.
+func reloadAPIKeys(cfg *config.ServerConfig, state apiKeys) {
+	if state.authenticator == nil {
+		return
+	}
+	keys, err := config.LoadAPIKeys(cfg.AssetsFilePath)
+	if err == nil {
+		err = state.reload(keys)
+	}
+	if err != nil {
+		observability.LogError("api-key-reload", "reloadAPIKeys", err, map[string]interface{}{
+			"assets_file_path": cfg.AssetsFilePath,
+		})
+		return
+	}
+
+	observability.LogSuccess("api-key-reload", "reloadAPIKeys", map[string]interface{}{
+		"api_keys": len(keys),
+	})
+}
+
+// reload checks the keys' limits, then swaps in the limits and the keys, or neither
+func (s apiKeys) reload(keys []config.APIKeyConfig) error {
+	for _, k := range keys {
+		if err := validation.ValidateAPIKeyLimits(k.RateLimit, k.RateBurst, k.DailyQuota); err != nil {
+			return fmt.Errorf("API key %q: %w", k.Name, err)
+		}
+	}
+	// Limits go first so a new key is never accepted before it is limited
+	previousLimits := s.limiter.Limits()
+	s.limiter.SetLimits(apiKeyLimits(keys))
+	if err := s.authenticator.Reload(keys); err != nil {
+		s.limiter.SetLimits(previousLimits)
+		return err
+	}
+	return nil
+}
+
+// reloadLogging applies the logging section of api-config.yaml again; an invalid section leaves
+// the current level and sampling in place
+func reloadLogging(cfg *config.ServerConfig) {
+	logging, err := config.LoadLoggingConfig(cfg.AssetsFilePath)
+	if err == nil {
+		err = validation.ValidateLogSampling(logging.Sampling)
+	}
+	if err == nil && logging.Level != "" {
+		err = validation.ValidateLogLevel(logging.Level)
+	}
+	if err != nil {
+		observability.LogError("logging-reload", "reloadLogging", err, map[string]interface{}{
+			"assets_file_path": cfg.AssetsFilePath,
+		})
+		return
+	}
+
+	if logging.Level != "" {
+		observability.SetLogLevel(logging.Level, "SIGHUP")
+	}
+	observability.SetLogSampling(logging.Sampling)
+	observability.LogSuccess("logging-reload", "reloadLogging", map[string]interface{}{
+		"log_level": observability.CurrentLogLevel(),
+		"sampling":  logging.Sampling,
+	})
 }
 
 func main() {
 	cfg := loadConfig()
-	observability.SetupLogger(cfg.LogLevel)
+	observability.SetupLogger(cfg.LogLevel, cfg.LogRedactKeys...)
 
 	observability.LogStartup(AppType, AppName, map[string]interface{}{
 		"port":        cfg.Port,
 		"environment": cfg.Environment,
 	})
 	observability.LogConfig(cfg.LogLevel)
+	observability.SetLogSampling(cfg.Logging.Sampling)
+	defer observability.NotifyLogLevelSignals()()
+
+	shutdownTracing, err := observability.SetupTracing(context.Background(), AppName+"-"+AppType, cfg.Tracing.TracesExporter, cfg.Tracing.OTLPEndpoint)
+	if err != nil {
+		observability.LogError("tracing-setup", "main", err, map[string]interface{}{
+			"exporter": cfg.Tracing.TracesExporter,
+		})
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		shutdownTracing(ctx)
+	}()
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Port))
 	if err != nil {
@@ -172,14 +609,74 @@ func main() {
 		os.Exit(1)
 	}
 
-	grpcServer := createGRPCServer(cfg)
+	var serverMetrics *metrics.Metrics
+	if cfg.MetricsPort > 0 {
+		serverMetrics = metrics.New("grpc")
+	}
+
+	// Background work started for the server stops with the first signal, before the drain
+	ctx, stop := shutdown.NotifyContext(context.Background())
+	defer stop()
+
+	// Closed after the drain so open watches end with UNAVAILABLE and clients resume elsewhere
+	stopping := make(chan struct{})
+	grpcServer, healthServer, monitor, keys := createGRPCServer(ctx, stopping, cfg, serverMetrics)
+	go monitor.Run(ctx, readinessInterval)
+	defer observability.NotifyReloadSignal(func() {
+		reloadLogging(cfg)
+		reloadAPIKeys(cfg, keys)
+	})()
+
+	// The metrics and probe listeners outlive the drain so scrapes still see the last calls and probes see NOT_SERVING
+	metricsCtx, stopMetrics := context.WithCancel(context.Background())
+	defer stopMetrics()
+	var probes http.Handler
+	if cfg.ProbePort > 0 {
+		go func() {
+			if err := monitor.ListenAndServe(metricsCtx, cfg.ProbePort); err != nil {
+				observability.LogError("probe-serve", "main", err, map[string]interface{}{
+					"port": cfg.ProbePort,
+				})
+			}
+		}()
+	} else {
+		probes = monitor.Handler()
+	}
+	if serverMetrics != nil {
+		go func() {
+			if err := serverMetrics.ListenAndServe(metricsCtx, cfg.MetricsPort, probes); err != nil {
+				observability.LogError("metrics-serve", "main", err, map[string]interface{}{
+					"port": cfg.MetricsPort,
+				})
+			}
+		}()
+	}
 
 	observability.LogSuccess("server-listen", "main", map[string]interface{}{
-		"address": lis.Addr(),
+		"address":      lis.Addr(),
+		"metrics_port": cfg.MetricsPort,
+		"probe_port":   cfg.ProbePort,
 	})
 
-	if err := grpcServer.Serve(lis); err != nil {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(lis)
+	}()
+
+	select {
+	case err := <-serveErr:
 		observability.LogError("server-serve", "main", err, nil)
 		os.Exit(1)
+	case <-ctx.Done():
 	}
+
+	observability.LogShutdown(AppType, AppName)
+	graceful := shutdown.GRPC(grpcServer, healthServer, shutdown.Options{
+		DrainPeriod: cfg.ShutdownDrainPeriod,
+		Timeout:     cfg.ShutdownTimeout,
+		BeforeStop:  func() { close(stopping) },
+	})
+	observability.LogSuccess("server-stop", "main", map[string]interface{}{
+		"graceful": graceful,
+	})
 }
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	movie "case-studies/grpc/cmd/movie"
+	"case-studies/grpc/internal/apikey"
+	"case-studies/grpc/internal/config"
+	internalMovie "case-studies/grpc/internal/movie"
+	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/ratelimit"
+)
+
+func newTestAPIKeys(t *testing.T, keys []config.APIKeyConfig) apiKeys {
+	t.Helper()
+	authenticator, err := apikey.NewAuthenticator(keys, "", time.Now)
+	if err != nil {
+		t.Fatalf("could not create authenticator: %v", err)
+	}
+	return apiKeys{authenticator: authenticator, limiter: ratelimit.NewLimiter(apiKeyLimits(keys), time.Now)}
+}
+
+func writeAPIConfig(t *testing.T, content string) *config.ServerConfig {
+	t.Helper()
+	assetsFilePath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(assetsFilePath, "api-config.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatalf("could not write api-config.yaml: %v", err)
+	}
+	return &config.ServerConfig{AssetsFilePath: assetsFilePath}
+}
+
+func TestReloadAPIKeysLimitsReloadedKeys(t *testing.T) {
+	observability.SetupLogger("info")
+
+	// Given a server started with one key, and a file adding a limited key and lowering the first key's quota
+	keys := newTestAPIKeys(t, []config.APIKeyConfig{{Name: "existing", Key: "existing-key", DailyQuota: 10}})
+	if _, err := keys.limiter.Allow("existing"); err != nil {
+		t.Fatalf("could not charge the existing key: %v", err)
+	}
+	cfg := writeAPIConfig(t, `api_keys:
+  - name: existing
+    key: existing-key
+    daily_quota: 2
+  - name: generated
+    key: generated-key
+    daily_quota: 1
+`)
+
+	// When
+	reloadAPIKeys(cfg, keys)
+
+	// Then
+	if principal, err := keys.authenticator.Authenticate("generated-key"); err != nil || principal.Name != "generated" {
+		t.Fatalf("Given a generated key, When reloading, Then expected it to authenticate, got %v (%v)", principal, err)
+	}
+	if _, err := keys.limiter.Allow("generated"); err != nil {
+		t.Fatalf("Given a generated key, When sending its first request, Then expected it to be allowed, got %v", err)
+	}
+	if _, err := keys.limiter.Allow("generated"); !errors.Is(err, ratelimit.ErrQuotaExceeded) {
+		t.Errorf("Given a generated key with a quota of 1, When sending a second request, Then expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, err := keys.limiter.Allow("existing"); err != nil {
+		t.Fatalf("Given an existing key with 1 request spent, When sending under the new quota, Then expected it to be allowed, got %v", err)
+	}
+	if _, err := keys.limiter.Allow("existing"); !errors.Is(err, ratelimit.ErrQuotaExceeded) {
+		t.Errorf("Given an existing key whose quota was lowered to 2, When sending a third request, Then expected ErrQuotaExceeded, got %v", err)
+	}
+}
+
+func TestReloadAPIKeysKeepsLimitsOnInvalidFile(t *testing.T) {
+	observability.SetupLogger("info")
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid limits", "api_keys:\n  - name: existing\n    key: existing-key\n    rate_limit: -1\n"},
+		{"key the authenticator refuses", "api_keys:\n  - name: existing\n    daily_quota: 5\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			keys := newTestAPIKeys(t, []config.APIKeyConfig{{Name: "existing", Key: "existing-key", DailyQuota: 1}})
+			cfg := writeAPIConfig(t, tt.content)
+
+			// When
+			reloadAPIKeys(cfg, keys)
+
+			// Then
+			if _, err := keys.authenticator.Authenticate("existing-key"); err != nil {
+				t.Errorf("Given %s, When reloading, Then expected the existing key to still authenticate, got %v", tt.name, err)
+			}
+			if limits := keys.limiter.Limits(); limits["existing"].DailyQuota != 1 {
+				t.Errorf("Given %s, When reloading, Then expected the existing quota of 1, got %+v", tt.name, limits)
+			}
+		})
+	}
+}
+
+func TestRegisterMovieServices(t *testing.T) {
+	observability.SetupLogger("info")
+	filePath := filepath.Join(t.TempDir(), "movie-data.json")
+	if err := os.WriteFile(filePath, []byte("[]"), 0o600); err != nil {
+		t.Fatalf("could not write movie-data.json: %v", err)
+	}
+	writable, err := internalMovie.NewJSONFileRepository(filePath)
+	if err != nil {
+		t.Fatalf("could not create repository: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		repository       internalMovie.MovieRepository
+		expectMovieAdmin bool
+	}{
+		// embedding only the query interface hides the write methods
+		{"read-only repository", struct{ internalMovie.MovieRepository }{writable}, false},
+		{"writable repository", writable, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			grpcServer := grpc.NewServer()
+
+			// When
+			services := registerMovieServices(grpcServer, &server{}, tt.repository)
+
+			// Then
+			registered := grpcServer.GetServiceInfo()
+			if _, ok := registered[movie.ServerAdmin_ServiceDesc.ServiceName]; !ok {
+				t.Errorf("Given a %s, When registering services, Then expected ServerAdmin to be registered, got %v", tt.name, services)
+			}
+			if _, ok := registered[movie.MovieAdmin_ServiceDesc.ServiceName]; ok != tt.expectMovieAdmin {
+				t.Errorf("Given a %s, When registering services, Then expected MovieAdmin registered to be %v, got %v", tt.name, tt.expectMovieAdmin, services)
+			}
+			if len(services) != len(registered) {
+				t.Errorf("Given a %s, When registering services, Then expected the returned names to match the %d registered services, got %v", tt.name, len(registered), services)
+			}
+		})
+	}
+}
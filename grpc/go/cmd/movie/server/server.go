@@ -2,21 +2,67 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"io"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	movie "case-studies/grpc/cmd/movie"
+	"case-studies/grpc/internal/fulltext"
+	"case-studies/grpc/internal/metrics"
+	internalMovie "case-studies/grpc/internal/movie"
 	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/pagination"
 	"case-studies/grpc/internal/validation"
 )
 
+const (
+	// maxBatchGetMovies caps the number of IDs accepted by a single BatchGetMovies call
+	maxBatchGetMovies = 100
+	// maxSearchGenres caps the number of genres accepted by a single SearchMovies call
+	maxSearchGenres = 20
+	// maxFullTextQueryLength caps the length of a FullTextSearch query
+	maxFullTextQueryLength = 200
+	// changeHistorySize is how many changes WatchMovies can replay to a resuming watcher
+	changeHistorySize = 1000
+)
+
+// Relative weights of movie fields in full-text relevance scoring
+const (
+	titleSearchWeight       = 3.0
+	genreSearchWeight       = 2.0
+	castSearchWeight        = 1.5
+	plotSummarySearchWeight = 1.0
+)
+
+// catalogue pairs a consistent view of the movies with the state derived from it
+type catalogue struct {
+	repository  internalMovie.MovieRepository
+	movies      []*movie.Movie
+	searchIndex *fulltext.Index
+	// Change feed revision the movies correspond to
+	revision int64
+}
+
 type server struct {
 	movie.UnimplementedGetterServer
-	assetsFilePath string
-	movies         []*movie.Movie
+	repository internalMovie.MovieRepository
+	pageTokens *pagination.TokenCodec
+	changes    *internalMovie.ChangeFeed
+	metrics    *metrics.Metrics
+	// Closed before the server stops so WatchMovies streams end instead of holding up the shutdown
+	stopping <-chan struct{}
+
+	// Replaced by loadMovies; handlers load it once so a reload never changes data mid-call
+	catalogue atomic.Pointer[catalogue]
+	// Serialises loadMovies so changes are published in order
+	loadMu sync.Mutex
 
 	// Protects moviesCountSoFar
 	mu sync.Mutex
@@ -24,27 +70,81 @@ type server struct {
 
 func (server *server) GetMoviesByRatings(ctx context.Context, input *movie.GetMovieInput) (*movie.GetMovieOutput, error) {
 	start := time.Now()
-	observability.LogSuccess("movie-request-start", "GetMoviesByRatings", map[string]interface{}{
+	observability.LogSuccessContext(ctx, "movie-request-start", "GetMoviesByRatings", map[string]interface{}{
 		"ratings_score": input.GetMinimumRatingsScore(),
 	})
 
 	if err := validation.ValidateMovieRatings(input.GetMinimumRatingsScore()); err != nil {
-		observability.LogError("validation", "GetMoviesByRatings", err, map[string]interface{}{
+		observability.LogErrorContext(ctx, "validation", "GetMoviesByRatings", err, map[string]interface{}{
 			"ratings_score": input.GetMinimumRatingsScore(),
 		})
 		return nil, err
 	}
 
+	if err := validation.ValidatePageSize(input.GetPageSize()); err != nil {
+		observability.LogErrorContext(ctx, "validation", "GetMoviesByRatings", err, map[string]interface{}{
+			"page_size": input.GetPageSize(),
+		})
+		return nil, err
+	}
+
 	sanitisedMinimumRatingsScore := input.GetMinimumRatingsScore()
 
-	filtered, moviesCount := server.filterMoviesByRating(server.movies, sanitisedMinimumRatingsScore)
+	filtered, moviesCount, err := server.filterMoviesByRating(ctx, server.catalogue.Load().repository, sanitisedMinimumRatingsScore)
+	if err != nil {
+		return nil, err
+	}
 
-	response := &movie.GetMovieOutput{Movie: filtered, MovieCount: moviesCount}
+	// Clients that never ask for pages still get every match, as before pagination existed
+	pageSize := len(filtered)
+	if input.GetPageSize() != 0 || input.GetPageToken() != "" {
+		pageSize = pagination.ResolvePageSize(input.GetPageSize())
+	}
+
+	pageStart := 0
+	if input.GetPageToken() != "" {
+		cursor, err := server.pageTokens.Decode(input.GetPageToken())
+		if err != nil {
+			observability.LogErrorContext(ctx, "page-token", "GetMoviesByRatings", err, nil)
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+		if cursor.MinimumRatingsScore != sanitisedMinimumRatingsScore {
+			observability.LogErrorContext(ctx, "page-token", "GetMoviesByRatings", pagination.ErrInvalidPageToken, map[string]interface{}{
+				"ratings_score":       sanitisedMinimumRatingsScore,
+				"token_ratings_score": cursor.MinimumRatingsScore,
+			})
+			return nil, status.Error(codes.InvalidArgument, "page token does not match minimum_ratings_score")
+		}
+		// Resume after the cursor rather than at an offset so pages stay stable if the slice changes
+		pageStart = sort.Search(len(filtered), func(i int) bool {
+			return isMovieAfterCursor(filtered[i], cursor)
+		})
+	}
+	pageEnd := min(pageStart+pageSize, len(filtered))
+	page := filtered[pageStart:pageEnd]
+
+	response := &movie.GetMovieOutput{Movie: page, MovieCount: moviesCount, TotalSize: moviesCount}
+
+	if pageEnd < len(filtered) {
+		last := page[len(page)-1]
+		nextPageToken, err := server.pageTokens.Encode(pagination.Cursor{
+			MinimumRatingsScore: sanitisedMinimumRatingsScore,
+			LastRatingsScore:    last.GetRatingsScore(),
+			LastMovieID:         last.GetMovieId(),
+		})
+		if err != nil {
+			observability.LogErrorContext(ctx, "page-token", "GetMoviesByRatings", err, nil)
+			return nil, err
+		}
+		response.NextPageToken = nextPageToken
+	}
 
 	duration := time.Since(start)
-	observability.LogSuccess("movie-request", "GetMoviesByRatings", map[string]interface{}{
+	observability.LogSuccessContext(ctx, "movie-request", "GetMoviesByRatings", map[string]interface{}{
 		"ratings_score": sanitisedMinimumRatingsScore,
-		"total_movies":  len(filtered),
+		"total_movies":  len(page),
+		"total_size":    moviesCount,
+		"has_next_page": response.GetNextPageToken() != "",
 		"duration":      duration,
 	})
 
@@ -54,7 +154,8 @@ func (server *server) GetMoviesByRatings(ctx context.Context, input *movie.GetMo
 func (server *server) GetMoviesByRatingsStream(stream movie.Getter_GetMoviesByRatingsStreamServer) error {
 	var moviesCountSoFar int32
 
-	movies := server.movies
+	// The whole stream reads one snapshot so running totals stay consistent across reloads
+	repository := server.catalogue.Load().repository
 
 	for {
 		getMovieInput, err := stream.Recv()
@@ -63,17 +164,17 @@ func (server *server) GetMoviesByRatingsStream(stream movie.Getter_GetMoviesByRa
 			return nil
 		}
 		if err != nil {
-			observability.LogError("stream-recv", "GetMoviesByRatingsStream", err, nil)
+			observability.LogErrorContext(stream.Context(), "stream-recv", "GetMoviesByRatingsStream", err, nil)
 			return err
 		}
 
 		start := time.Now()
-		observability.LogSuccess("stream-request-start", "GetMoviesByRatingsStream", map[string]interface{}{
+		observability.LogSuccessContext(stream.Context(), "stream-request-start", "GetMoviesByRatingsStream", map[string]interface{}{
 			"ratings_score": getMovieInput.GetMinimumRatingsScore(),
 		})
 
 		if err := validation.ValidateMovieRatings(getMovieInput.GetMinimumRatingsScore()); err != nil {
-			observability.LogError("validation", "GetMoviesByRatingsStream", err, map[string]interface{}{
+			observability.LogErrorContext(stream.Context(), "validation", "GetMoviesByRatingsStream", err, map[string]interface{}{
 				"ratings_score": getMovieInput.GetMinimumRatingsScore(),
 			})
 			return err
@@ -81,9 +182,13 @@ func (server *server) GetMoviesByRatingsStream(stream movie.Getter_GetMoviesByRa
 
 		sanitisedMinimumRatingsScore := getMovieInput.GetMinimumRatingsScore()
 
+		filtered, moviesCount, err := server.filterMoviesByRating(stream.Context(), repository, sanitisedMinimumRatingsScore)
+		if err != nil {
+			return err
+		}
+
 		server.mu.Lock()
 
-		filtered, moviesCount := server.filterMoviesByRating(movies, sanitisedMinimumRatingsScore)
 		moviesCountSoFar += moviesCount
 
 		server.mu.Unlock()
@@ -95,12 +200,12 @@ func (server *server) GetMoviesByRatingsStream(stream movie.Getter_GetMoviesByRa
 				MovieCountSoFar: moviesCountSoFar,
 			},
 		); err != nil {
-			observability.LogError("stream-send", "GetMoviesByRatingsStream", err, nil)
+			observability.LogErrorContext(stream.Context(), "stream-send", "GetMoviesByRatingsStream", err, nil)
 			return err
 		}
 
 		duration := time.Since(start)
-		observability.LogSuccess("stream-request", "GetMoviesByRatingsStream", map[string]interface{}{
+		observability.LogSuccessContext(stream.Context(), "stream-request", "GetMoviesByRatingsStream", map[string]interface{}{
 			"ratings_score": sanitisedMinimumRatingsScore,
 			"total_movies":  len(filtered),
 			"duration":      duration,
@@ -108,38 +213,399 @@ func (server *server) GetMoviesByRatingsStream(stream movie.Getter_GetMoviesByRa
 	}
 }
 
-func (server *server) loadMovies() ([]*movie.Movie, error) {
-	reader, err := NewMoviesFileReader(server.assetsFilePath)
+func (server *server) GetMovie(ctx context.Context, input *movie.GetMovieRequest) (*movie.Movie, error) {
+	start := time.Now()
+	observability.LogSuccessContext(ctx, "movie-get-start", "GetMovie", map[string]interface{}{
+		"movie_id": input.GetMovieId(),
+	})
+
+	if err := validation.ValidateMovieID(input.GetMovieId()); err != nil {
+		observability.LogErrorContext(ctx, "validation", "GetMovie", err, map[string]interface{}{
+			"movie_id": input.GetMovieId(),
+		})
+		return nil, err
+	}
+
+	m, err := server.catalogue.Load().repository.Get(ctx, input.GetMovieId())
+	if errors.Is(err, internalMovie.ErrMovieNotFound) {
+		return nil, status.Errorf(codes.NotFound, "movie %q not found", input.GetMovieId())
+	}
 	if err != nil {
-		observability.LogError("file-reader", "loadMovies", err, nil)
+		observability.LogErrorContext(ctx, "movie-get", "GetMovie", err, map[string]interface{}{
+			"movie_id": input.GetMovieId(),
+		})
+		return nil, err
+	}
+
+	duration := time.Since(start)
+	observability.LogSuccessContext(ctx, "movie-get", "GetMovie", map[string]interface{}{
+		"movie_id": input.GetMovieId(),
+		"duration": duration,
+	})
+
+	return m, nil
+}
+
+func (server *server) BatchGetMovies(ctx context.Context, input *movie.BatchGetMoviesRequest) (*movie.BatchGetMoviesResponse, error) {
+	start := time.Now()
+	observability.LogSuccessContext(ctx, "movie-batch-get-start", "BatchGetMovies", map[string]interface{}{
+		"requested_movies": len(input.GetMovieIds()),
+	})
+
+	if err := validation.ValidateBatchSize(len(input.GetMovieIds()), maxBatchGetMovies); err != nil {
+		observability.LogErrorContext(ctx, "validation", "BatchGetMovies", err, map[string]interface{}{
+			"requested_movies": len(input.GetMovieIds()),
+		})
 		return nil, err
 	}
-	defer reader.Close()
+	for _, movieID := range input.GetMovieIds() {
+		if err := validation.ValidateMovieID(movieID); err != nil {
+			observability.LogErrorContext(ctx, "validation", "BatchGetMovies", err, map[string]interface{}{
+				"movie_id": movieID,
+			})
+			return nil, err
+		}
+	}
+
+	repository := server.catalogue.Load().repository
+	response := &movie.BatchGetMoviesResponse{}
+	seen := make(map[string]bool, len(input.GetMovieIds()))
+	for _, movieID := range input.GetMovieIds() {
+		if seen[movieID] {
+			continue
+		}
+		seen[movieID] = true
 
-	var movies []*movie.Movie
-	if err := json.NewDecoder(reader).Decode(&movies); err != nil {
-		observability.LogError("json-decode", "loadMovies", err, nil)
+		m, err := repository.Get(ctx, movieID)
+		if errors.Is(err, internalMovie.ErrMovieNotFound) {
+			response.MissingMovieIds = append(response.MissingMovieIds, movieID)
+			continue
+		}
+		if err != nil {
+			observability.LogErrorContext(ctx, "movie-get", "BatchGetMovies", err, map[string]interface{}{
+				"movie_id": movieID,
+			})
+			return nil, err
+		}
+		response.Movie = append(response.Movie, m)
+	}
+
+	duration := time.Since(start)
+	observability.LogSuccessContext(ctx, "movie-batch-get", "BatchGetMovies", map[string]interface{}{
+		"total_movies":   len(response.GetMovie()),
+		"missing_movies": len(response.GetMissingMovieIds()),
+		"duration":       duration,
+	})
+
+	return response, nil
+}
+
+func (server *server) SearchMovies(ctx context.Context, input *movie.SearchMoviesRequest) (*movie.SearchMoviesResponse, error) {
+	start := time.Now()
+	observability.LogSuccessContext(ctx, "movie-search-start", "SearchMovies", map[string]interface{}{
+		"genres":          input.GetGenres(),
+		"released_after":  input.GetReleasedAfter(),
+		"released_before": input.GetReleasedBefore(),
+		"director_name":   input.GetDirectorName(),
+		"actor_name":      input.GetActorName(),
+		"crew_role":       input.GetCrewRole(),
+		"sort_by":         input.GetSortBy().String(),
+		"sort_order":      input.GetSortOrder().String(),
+	})
+
+	if err := validateSearchMoviesRequest(input); err != nil {
+		observability.LogErrorContext(ctx, "validation", "SearchMovies", err, nil)
+		return nil, err
+	}
+
+	query := searchQueryFromRequest(input)
+	filtered, err := server.catalogue.Load().repository.Query(ctx, query)
+	if err != nil {
+		observability.LogErrorContext(ctx, "movie-query", "SearchMovies", err, nil)
 		return nil, err
 	}
+	totalSize := len(filtered)
 
-	sort.Slice(movies, func(i, j int) bool {
-		return movies[i].GetRatingsScore() < movies[j].GetRatingsScore()
+	pageSize := pagination.ResolvePageSize(input.GetPageSize())
+	if len(filtered) > pageSize {
+		filtered = filtered[:pageSize]
+	}
+
+	response := &movie.SearchMoviesResponse{
+		Movie:      filtered,
+		MovieCount: int32(len(filtered)),
+		TotalSize:  int32(totalSize),
+	}
+
+	duration := time.Since(start)
+	observability.LogSuccessContext(ctx, "movie-search", "SearchMovies", map[string]interface{}{
+		"total_movies": len(filtered),
+		"total_size":   totalSize,
+		"duration":     duration,
 	})
 
-	return movies, nil
+	return response, nil
 }
 
-func (server *server) filterMoviesByRating(movies []*movie.Movie, minRating float32) ([]*movie.Movie, int32) {
-	var filtered []*movie.Movie
+func (server *server) FullTextSearch(ctx context.Context, input *movie.FullTextSearchRequest) (*movie.FullTextSearchResponse, error) {
+	start := time.Now()
+	observability.LogSuccessContext(ctx, "movie-full-text-search-start", "FullTextSearch", map[string]interface{}{
+		"query": input.GetQuery(),
+	})
+
+	if err := validation.ValidateString(input.GetQuery(), "query", maxFullTextQueryLength, false); err != nil {
+		observability.LogErrorContext(ctx, "validation", "FullTextSearch", err, map[string]interface{}{
+			"query": input.GetQuery(),
+		})
+		return nil, err
+	}
+	if err := validation.ValidatePageSize(input.GetPageSize()); err != nil {
+		observability.LogErrorContext(ctx, "validation", "FullTextSearch", err, map[string]interface{}{
+			"page_size": input.GetPageSize(),
+		})
+		return nil, err
+	}
+
+	sanitisedQuery := validation.SanitiseString(input.GetQuery())
+	if len(fulltext.Tokenise(sanitisedQuery)) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "query must contain at least one word")
+	}
+
+	current := server.catalogue.Load()
+	results, totalSize := current.searchIndex.Search(sanitisedQuery, pagination.ResolvePageSize(input.GetPageSize()))
+
+	response := &movie.FullTextSearchResponse{TotalSize: int32(totalSize)}
+	for _, result := range results {
+		m, err := current.repository.Get(ctx, result.ID)
+		if errors.Is(err, internalMovie.ErrMovieNotFound) {
+			continue
+		}
+		if err != nil {
+			observability.LogErrorContext(ctx, "movie-get", "FullTextSearch", err, map[string]interface{}{
+				"movie_id": result.ID,
+			})
+			return nil, err
+		}
+		searchResult := &movie.FullTextSearchResult{Movie: m, Score: float32(result.Score)}
+		for _, highlight := range result.Highlights {
+			searchResult.Highlight = append(searchResult.Highlight, &movie.FullTextSearchHighlight{
+				Field:   highlight.Field,
+				Snippet: highlight.Snippet,
+			})
+		}
+		response.Result = append(response.Result, searchResult)
+	}
+	response.ResultCount = int32(len(response.GetResult()))
+
+	duration := time.Since(start)
+	observability.LogSuccessContext(ctx, "movie-full-text-search", "FullTextSearch", map[string]interface{}{
+		"query":        sanitisedQuery,
+		"total_movies": len(response.GetResult()),
+		"total_size":   totalSize,
+		"duration":     duration,
+	})
+
+	return response, nil
+}
+
+func (server *server) WatchMovies(input *movie.WatchMoviesRequest, stream movie.Getter_WatchMoviesServer) error {
+	watcher := &internalMovie.Watcher{Changes: server.changes, Snapshot: server.watchSnapshot, Stopping: server.stopping}
+	return watcher.WatchMovies(input, stream)
+}
+
+// watchSnapshot returns the matching movies of the current catalogue and the revision it was loaded at
+func (server *server) watchSnapshot(ctx context.Context, minRating float32) ([]*movie.Movie, int64, error) {
+	current := server.catalogue.Load()
+	movies, _, err := server.filterMoviesByRating(ctx, current.repository, minRating)
+	return movies, current.revision, err
+}
+
+func validateSearchMoviesRequest(input *movie.SearchMoviesRequest) error {
+	if len(input.GetGenres()) > 0 {
+		if err := validation.ValidateBatchSize(len(input.GetGenres()), maxSearchGenres); err != nil {
+			return err
+		}
+	}
+	for _, genre := range input.GetGenres() {
+		if err := validation.ValidateString(genre, "genre", 100, false); err != nil {
+			return err
+		}
+	}
+	if err := validation.ValidateReleaseDateRange(input.GetReleasedAfter(), input.GetReleasedBefore()); err != nil {
+		return err
+	}
+	if err := validation.ValidateString(input.GetDirectorName(), "director_name", 100, true); err != nil {
+		return err
+	}
+	if err := validation.ValidateString(input.GetActorName(), "actor_name", 100, true); err != nil {
+		return err
+	}
+	if err := validation.ValidateString(input.GetCrewRole(), "crew_role", 100, true); err != nil {
+		return err
+	}
+	if input.MinimumRatingsScore != nil {
+		if err := validation.ValidateMovieRatings(input.GetMinimumRatingsScore()); err != nil {
+			return err
+		}
+	}
+	if input.MaximumRatingsScore != nil {
+		if err := validation.ValidateMovieRatings(input.GetMaximumRatingsScore()); err != nil {
+			return err
+		}
+	}
+	if input.MinimumRatingsScore != nil && input.MaximumRatingsScore != nil {
+		if err := validation.ValidateMovieRatingsRange(input.GetMinimumRatingsScore(), input.GetMaximumRatingsScore()); err != nil {
+			return err
+		}
+	}
+	return validation.ValidatePageSize(input.GetPageSize())
+}
+
+func searchQueryFromRequest(input *movie.SearchMoviesRequest) internalMovie.Query {
+	query := internalMovie.Query{
+		Genres:              input.GetGenres(),
+		GenreMatch:          internalMovie.GenreMatchAny,
+		ReleasedAfter:       input.GetReleasedAfter(),
+		ReleasedBefore:      input.GetReleasedBefore(),
+		DirectorName:        validation.SanitiseString(input.GetDirectorName()),
+		ActorName:           validation.SanitiseString(input.GetActorName()),
+		CrewRole:            validation.SanitiseString(input.GetCrewRole()),
+		MinimumRatingsScore: input.MinimumRatingsScore,
+		MaximumRatingsScore: input.MaximumRatingsScore,
+		SortBy:              internalMovie.SortByRating,
+		SortOrder:           internalMovie.SortDescending,
+	}
+
+	if input.GetGenreMatch() == movie.GenreMatch_GENRE_MATCH_ALL {
+		query.GenreMatch = internalMovie.GenreMatchAll
+	}
+
+	switch input.GetSortBy() {
+	case movie.SortField_SORT_FIELD_RELEASE_DATE:
+		query.SortBy = internalMovie.SortByReleaseDate
+	case movie.SortField_SORT_FIELD_TITLE:
+		query.SortBy = internalMovie.SortByTitle
+		query.SortOrder = internalMovie.SortAscending
+	}
+
+	switch input.GetSortOrder() {
+	case movie.SortOrder_SORT_ORDER_ASCENDING:
+		query.SortOrder = internalMovie.SortAscending
+	case movie.SortOrder_SORT_ORDER_DESCENDING:
+		query.SortOrder = internalMovie.SortDescending
+	}
+
+	return query
+}
+
+// loadMovies reads the movies from the repository and builds the indexes derived from them,
+// then publishes the changes since the previous load and the new catalogue
+func (server *server) loadMovies(ctx context.Context) error {
+	ctx, span := observability.StartSpan(ctx, "loadMovies")
+	defer span.End()
+
+	server.loadMu.Lock()
+	defer server.loadMu.Unlock()
+
+	repository := server.repository
+	if snapshots, ok := repository.(internalMovie.SnapshotRepository); ok {
+		repository = snapshots.Snapshot()
+	}
+
+	movies, err := repository.List(ctx)
+	if err != nil {
+		observability.RecordSpanError(span, err)
+		observability.LogErrorContext(ctx, "movie-list", "loadMovies", err, nil)
+		return err
+	}
+
+	var revision int64
+	if previous := server.catalogue.Load(); previous != nil {
+		// Publish before storing so a watcher never sees a catalogue revision the feed lacks
+		revision = server.changes.Publish(previous.movies, movies)
+	}
+
+	server.catalogue.Store(&catalogue{
+		repository:  repository,
+		movies:      movies,
+		searchIndex: buildSearchIndex(movies),
+		revision:    revision,
+	})
+	server.metrics.SetMoviesLoaded(len(movies))
+	span.SetAttributes(attribute.Int("movie.count", len(movies)), attribute.Int64("movie.revision", revision))
+
+	observability.LogSuccessContext(ctx, "movie-data-load", "loadMovies", map[string]interface{}{
+		"total_movies": len(movies),
+		"revision":     revision,
+	})
+	return nil
+}
+
+// moviesLoaded counts the movies in the current catalogue
+func (server *server) moviesLoaded() int {
+	if current := server.catalogue.Load(); current != nil {
+		return len(current.movies)
+	}
+	return 0
+}
+
+// buildSearchIndex indexes the text fields editors search by for FullTextSearch
+func buildSearchIndex(movies []*movie.Movie) *fulltext.Index {
+	documents := make([]fulltext.Document, 0, len(movies))
 	for _, m := range movies {
-		if m.GetRatingsScore() >= minRating {
-			filtered = append(filtered, m)
+		fields := []fulltext.Field{
+			{Name: "title", Text: m.GetTitle(), Weight: titleSearchWeight},
+			{Name: "plot_summary", Text: m.GetPlotSummary(), Weight: plotSummarySearchWeight},
+		}
+		for _, genre := range m.GetGenre() {
+			fields = append(fields, fulltext.Field{Name: "genre", Text: genre, Weight: genreSearchWeight})
+		}
+		for _, c := range m.GetCast() {
+			fields = append(fields,
+				fulltext.Field{Name: "cast.actor_name", Text: c.GetActorName(), Weight: castSearchWeight},
+				fulltext.Field{Name: "cast.character_name", Text: c.GetCharacterName(), Weight: castSearchWeight},
+			)
 		}
+		documents = append(documents, fulltext.Document{ID: m.GetMovieId(), Fields: fields})
+	}
+
+	start := time.Now()
+	index := fulltext.NewIndex(documents)
+	observability.LogSuccess("movie-search-index", "buildSearchIndex", map[string]interface{}{
+		"total_movies": len(documents),
+		"duration":     time.Since(start),
+	})
+	return index
+}
+
+func isMovieAfterCursor(m *movie.Movie, cursor pagination.Cursor) bool {
+	if m.GetRatingsScore() != cursor.LastRatingsScore {
+		return m.GetRatingsScore() > cursor.LastRatingsScore
+	}
+	return m.GetMovieId() > cursor.LastMovieID
+}
+
+func (server *server) filterMoviesByRating(ctx context.Context, repository internalMovie.MovieRepository, minRating float32) ([]*movie.Movie, int32, error) {
+	ctx, span := observability.StartSpan(ctx, "filterMoviesByRating", attribute.Float64("movie.minimum_ratings_score", float64(minRating)))
+	defer span.End()
+
+	filtered, err := repository.Query(ctx, internalMovie.Query{
+		MinimumRatingsScore: &minRating,
+		SortBy:              internalMovie.SortByRating,
+		SortOrder:           internalMovie.SortAscending,
+	})
+	if err != nil {
+		observability.RecordSpanError(span, err)
+		observability.LogErrorContext(ctx, "movie-filter", "filterMoviesByRating", err, map[string]interface{}{
+			"ratings_score": minRating,
+		})
+		return nil, 0, err
 	}
 
-	observability.LogSuccess("movie-filter", "filterMoviesByRating", map[string]interface{}{
+	span.SetAttributes(attribute.Int("movie.count", len(filtered)))
+	observability.LogSuccessContext(ctx, "movie-filter", "filterMoviesByRating", map[string]interface{}{
 		"ratings_score": minRating,
 		"total_movies":  len(filtered),
 	})
-	return filtered, int32(len(filtered))
+	return filtered, int32(len(filtered)), nil
 }
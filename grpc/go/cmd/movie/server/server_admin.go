@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	movie "case-studies/grpc/cmd/movie"
+	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/validation"
+)
+
+// serverAdminServer implements ServerAdmin, which operates the process rather than the catalogue,
+// so it is registered whatever the repository backend
+type serverAdminServer struct {
+	movie.UnimplementedServerAdminServer
+}
+
+func (server *serverAdminServer) SetLogLevel(ctx context.Context, input *movie.SetLogLevelRequest) (*movie.SetLogLevelResponse, error) {
+	if err := validation.ValidateLogLevel(input.GetLevel()); err != nil {
+		observability.LogErrorContext(ctx, "validation", "SetLogLevel", err, map[string]interface{}{
+			"log_level": input.GetLevel(),
+		})
+		return nil, err
+	}
+
+	previous, err := observability.SetLogLevel(input.GetLevel(), "SetLogLevel")
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	observability.LogSuccessContext(ctx, "log-level-set", "SetLogLevel", map[string]interface{}{
+		"previous_level": previous,
+		"log_level":      input.GetLevel(),
+	})
+
+	return &movie.SetLogLevelResponse{PreviousLevel: previous, Level: input.GetLevel()}, nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	movie "case-studies/grpc/cmd/movie"
+	internalMovie "case-studies/grpc/internal/movie"
+	"case-studies/grpc/internal/observability"
+)
+
+const testMovieData = `[
+  {"movie_id": "tt0000001", "title": "The Grand Adventure", "ratings_score": 8.5},
+  {"movie_id": "tt0000002", "title": "Lunar Glow", "ratings_score": 6.2}
+]`
+
+func newTestServer(t *testing.T) *server {
+	t.Helper()
+	observability.SetupLogger("info")
+	filePath := filepath.Join(t.TempDir(), "movie-data.json")
+	if err := os.WriteFile(filePath, []byte(testMovieData), 0o600); err != nil {
+		t.Fatalf("could not write movie-data.json: %v", err)
+	}
+	repository, err := internalMovie.NewJSONFileRepository(filePath)
+	if err != nil {
+		t.Fatalf("could not open repository: %v", err)
+	}
+	movieServer := &server{repository: repository}
+	if err := movieServer.loadMovies(context.Background()); err != nil {
+		t.Fatalf("could not load movies: %v", err)
+	}
+	return movieServer
+}
+
+func movieIDs(movies []*movie.Movie) []string {
+	ids := make([]string, len(movies))
+	for i, m := range movies {
+		ids[i] = m.GetMovieId()
+	}
+	return ids
+}
+
+func TestGetMovie(t *testing.T) {
+	movieServer := newTestServer(t)
+
+	tests := []struct {
+		name         string
+		movieID      string
+		expectedCode codes.Code
+	}{
+		{"known ID", "tt0000001", codes.OK},
+		{"unknown ID", "tt9999999", codes.NotFound},
+		{"invalid ID", "", codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			m, err := movieServer.GetMovie(context.Background(), &movie.GetMovieRequest{MovieId: tt.movieID})
+
+			// Then
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Fatalf("Given %s, When getting the movie, Then expected code %v, got %v (%v)", tt.name, tt.expectedCode, code, err)
+			}
+			if tt.expectedCode == codes.OK && m.GetMovieId() != tt.movieID {
+				t.Errorf("Given %s, When getting the movie, Then expected movie %s, got %s", tt.name, tt.movieID, m.GetMovieId())
+			}
+		})
+	}
+}
+
+func TestBatchGetMovies(t *testing.T) {
+	movieServer := newTestServer(t)
+
+	tests := []struct {
+		name            string
+		movieIDs        []string
+		expectedCode    codes.Code
+		expectedMovies  []string
+		expectedMissing []string
+	}{
+		{
+			name:           "known IDs in request order",
+			movieIDs:       []string{"tt0000002", "tt0000001"},
+			expectedMovies: []string{"tt0000002", "tt0000001"},
+		},
+		{
+			name:            "unknown IDs reported as missing",
+			movieIDs:        []string{"tt9999999", "tt0000001", "tt8888888"},
+			expectedMovies:  []string{"tt0000001"},
+			expectedMissing: []string{"tt9999999", "tt8888888"},
+		},
+		{
+			name:            "duplicate IDs merged",
+			movieIDs:        []string{"tt0000001", "tt9999999", "tt0000001", "tt9999999"},
+			expectedMovies:  []string{"tt0000001"},
+			expectedMissing: []string{"tt9999999"},
+		},
+		{
+			name:         "empty batch",
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "invalid ID",
+			movieIDs:     []string{"tt0000001", ""},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			response, err := movieServer.BatchGetMovies(context.Background(), &movie.BatchGetMoviesRequest{MovieIds: tt.movieIDs})
+
+			// Then
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Fatalf("Given %s, When batch getting, Then expected code %v, got %v (%v)", tt.name, tt.expectedCode, code, err)
+			}
+			if got := movieIDs(response.GetMovie()); !slices.Equal(got, tt.expectedMovies) {
+				t.Errorf("Given %s, When batch getting, Then expected movies %v, got %v", tt.name, tt.expectedMovies, got)
+			}
+			if got := response.GetMissingMovieIds(); !slices.Equal(got, tt.expectedMissing) {
+				t.Errorf("Given %s, When batch getting, Then expected missing IDs %v, got %v", tt.name, tt.expectedMissing, got)
+			}
+		})
+	}
+}
+
+func TestBatchGetMoviesSizeCap(t *testing.T) {
+	movieServer := newTestServer(t)
+
+	tests := []struct {
+		name         string
+		size         int
+		expectedCode codes.Code
+	}{
+		{"at the cap", maxBatchGetMovies, codes.OK},
+		{"over the cap", maxBatchGetMovies + 1, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ids := make([]string, tt.size)
+			for i := range ids {
+				ids[i] = fmt.Sprintf("tt%07d", i+1)
+			}
+
+			// When
+			response, err := movieServer.BatchGetMovies(context.Background(), &movie.BatchGetMoviesRequest{MovieIds: ids})
+
+			// Then
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Fatalf("Given %d IDs, When batch getting, Then expected code %v, got %v (%v)", tt.size, tt.expectedCode, code, err)
+			}
+			if tt.expectedCode != codes.OK {
+				if !strings.Contains(status.Convert(err).Message(), fmt.Sprint(maxBatchGetMovies)) {
+					t.Errorf("Given %d IDs, When batch getting, Then expected the message to name the cap, got %q", tt.size, status.Convert(err).Message())
+				}
+				return
+			}
+			if found := len(response.GetMovie()) + len(response.GetMissingMovieIds()); found != tt.size {
+				t.Errorf("Given %d IDs, When batch getting, Then expected every ID found or missing, got %d", tt.size, found)
+			}
+		})
+	}
+}
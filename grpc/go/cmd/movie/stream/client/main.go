@@ -21,7 +21,7 @@ const (
 
 func main() {
 	cfg := client.LoadConfig()
-	observability.SetupLogger(cfg.LogLevel)
+	observability.SetupLogger(cfg.LogLevel, cfg.LogRedactKeys...)
 
 	observability.LogStartup(AppType, AppName, map[string]interface{}{
 		"host":        cfg.Host,
@@ -29,6 +29,15 @@ func main() {
 		"environment": cfg.Environment,
 	})
 
+	shutdownTracing, err := observability.SetupTracing(context.Background(), AppName+"-"+AppType, cfg.Tracing.TracesExporter, cfg.Tracing.OTLPEndpoint)
+	if err != nil {
+		observability.LogError("tracing-setup", "main", err, map[string]interface{}{
+			"exporter": cfg.Tracing.TracesExporter,
+		})
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
 	conn, err := client.CreateGRPCConnection(cfg)
 	if err != nil {
 		observability.LogError("grpc-connect", "main", err, nil)
@@ -70,10 +79,11 @@ func makeGetterRequestStreamWithAPIKey(client movie.GetterClient, cfg *config.Mo
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", xApiKey)
+	ctx = observability.ContextWithRequestID(ctx, observability.NewRequestID())
 
 	stream, err := client.GetMoviesByRatingsStream(ctx)
 	if err != nil {
-		observability.LogError("stream-start", "makeGetterRequestStreamWithAPIKey", err, nil)
+		observability.LogErrorContext(ctx, "stream-start", "makeGetterRequestStreamWithAPIKey", err, nil)
 		return
 	}
 
@@ -88,10 +98,10 @@ func makeGetterRequestStreamWithAPIKey(client movie.GetterClient, cfg *config.Mo
 				return
 			}
 			if err != nil {
-				observability.LogError("stream-receive", "makeGetterRequestStreamWithAPIKey", err, nil)
+				observability.LogErrorContext(ctx, "stream-receive", "makeGetterRequestStreamWithAPIKey", err, nil)
 				return
 			}
-			observability.LogSuccess("stream-receive", "makeGetterRequestStreamWithAPIKey", map[string]interface{}{
+			observability.LogSuccessContext(ctx, "stream-receive", "makeGetterRequestStreamWithAPIKey", map[string]interface{}{
 				"count":        in.MovieCount,
 				"total_so_far": in.MovieCountSoFar,
 			})
@@ -102,7 +112,7 @@ func makeGetterRequestStreamWithAPIKey(client movie.GetterClient, cfg *config.Mo
 	for _, note := range getMovieInputs {
 		time.Sleep(3 * time.Second)
 		if err := stream.Send(note); err != nil {
-			observability.LogError("stream-send", "makeGetterRequestStreamWithAPIKey", err, map[string]interface{}{
+			observability.LogErrorContext(ctx, "stream-send", "makeGetterRequestStreamWithAPIKey", err, map[string]interface{}{
 				"note": note,
 			})
 			return
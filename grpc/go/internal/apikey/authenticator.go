@@ -0,0 +1,123 @@
+package apikey
+
+import (
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"case-studies/grpc/internal/auth"
+	"case-studies/grpc/internal/config"
+)
+
+var (
+	ErrUnknownKey     = errors.New("unknown API key")
+	ErrKeyDisabled    = errors.New("API key is disabled")
+	ErrKeyNotYetValid = errors.New("API key is not yet valid")
+	ErrKeyExpired     = errors.New("API key has expired")
+)
+
+type credential struct {
+	principal auth.Principal
+	plaintext string
+	salt      []byte
+	sum       []byte
+	entry     config.APIKeyConfig
+}
+
+func (c *credential) matches(key, pepper string) bool {
+	if c.sum != nil {
+		return hmac.Equal(digest(c.salt, key, pepper), c.sum)
+	}
+	return equalPlaintext(c.plaintext, key)
+}
+
+// Authenticator resolves incoming API keys against the keys in api-config.yaml
+type Authenticator struct {
+	credentials atomic.Pointer[[]credential]
+	pepper      string
+	now         func() time.Time
+}
+
+// NewAuthenticator checks and prepares the configured keys. Each entry needs a unique name and
+// exactly one of key (plaintext) or hash. now defaults to time.Now when nil.
+func NewAuthenticator(keys []config.APIKeyConfig, pepper string, now func() time.Time) (*Authenticator, error) {
+	if now == nil {
+		now = time.Now
+	}
+	a := &Authenticator{pepper: pepper, now: now}
+	if err := a.Reload(keys); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reload replaces the configured keys, so revoked and rotated keys take effect without a
+// restart. Invalid keys leave the current ones in use.
+func (a *Authenticator) Reload(keys []config.APIKeyConfig) error {
+	credentials := make([]credential, 0, len(keys))
+	names := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.Name == "" {
+			return fmt.Errorf("API key entry without a name")
+		}
+		if names[k.Name] {
+			return fmt.Errorf("duplicate API key name %q", k.Name)
+		}
+		names[k.Name] = true
+
+		c := credential{
+			principal: auth.Principal{Name: k.Name, Scopes: k.GrantedScopes(), Method: auth.MethodAPIKey},
+			entry:     k,
+		}
+		switch {
+		case k.Key != "" && k.Hash != "":
+			return fmt.Errorf("API key %q sets both key and hash", k.Name)
+		case k.Hash != "":
+			salt, sum, err := parseHash(k.Hash)
+			if err != nil {
+				return fmt.Errorf("API key %q: %w", k.Name, err)
+			}
+			c.salt, c.sum = salt, sum
+		case k.Key != "":
+			c.plaintext = k.Key
+		default:
+			return fmt.Errorf("API key %q needs a key or hash", k.Name)
+		}
+		credentials = append(credentials, c)
+	}
+	a.credentials.Store(&credentials)
+	return nil
+}
+
+// Authenticate returns the principal for key. Every configured key is checked so the time taken
+// does not reveal which entry matched. A key that matches but is disabled, expired or not yet valid
+// returns its principal together with the reason it was refused.
+func (a *Authenticator) Authenticate(key string) (auth.Principal, error) {
+	credentials := *a.credentials.Load()
+	var match *credential
+	for i := range credentials {
+		if credentials[i].matches(key, a.pepper) && match == nil {
+			match = &credentials[i]
+		}
+	}
+	if match == nil {
+		return auth.Principal{}, ErrUnknownKey
+	}
+
+	return match.principal, CheckValidity(match.entry, a.now())
+}
+
+// CheckValidity reports why k would be refused at now, or nil if it is usable
+func CheckValidity(k config.APIKeyConfig, now time.Time) error {
+	switch {
+	case k.Disabled:
+		return ErrKeyDisabled
+	case !k.NotBefore.IsZero() && now.Before(k.NotBefore):
+		return ErrKeyNotYetValid
+	case !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt):
+		return ErrKeyExpired
+	}
+	return nil
+}
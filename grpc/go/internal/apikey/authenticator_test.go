@@ -0,0 +1,137 @@
+package apikey
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"case-studies/grpc/internal/config"
+)
+
+func TestAuthenticatorAuthenticate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	hash, err := Hash("hashed-secret", "pepper")
+	if err != nil {
+		t.Fatalf("could not hash key: %v", err)
+	}
+	authenticator, err := NewAuthenticator([]config.APIKeyConfig{
+		{Name: "plain", Key: "plain-secret"},
+		{Name: "hashed", Hash: hash, Scopes: []string{config.ScopeMoviesRead, config.ScopeMoviesWrite}},
+		{Name: "disabled", Key: "disabled-secret", Disabled: true},
+		{Name: "future", Key: "future-secret", NotBefore: now.Add(time.Hour)},
+		{Name: "expired", Key: "expired-secret", ExpiresAt: now},
+		{Name: "current", Key: "current-secret", NotBefore: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+	}, "pepper", func() time.Time { return now })
+	if err != nil {
+		t.Fatalf("could not create authenticator: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		key            string
+		expectedName   string
+		expectedScopes int
+		expectedErr    error
+	}{
+		{"plaintext key", "plain-secret", "plain", 1, nil},
+		{"hashed key", "hashed-secret", "hashed", 2, nil},
+		{"unknown key", "nope", "", 0, ErrUnknownKey},
+		{"hash is not accepted as the key", hash, "", 0, ErrUnknownKey},
+		{"disabled key", "disabled-secret", "disabled", 1, ErrKeyDisabled},
+		{"key before not_before", "future-secret", "future", 1, ErrKeyNotYetValid},
+		{"key at expires_at", "expired-secret", "expired", 1, ErrKeyExpired},
+		{"key within its validity window", "current-secret", "current", 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			principal, err := authenticator.Authenticate(tt.key)
+
+			// Then
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Given %s, When authenticating, Then expected error %v, got %v", tt.name, tt.expectedErr, err)
+			}
+			if principal.Name != tt.expectedName || len(principal.Scopes) != tt.expectedScopes {
+				t.Errorf("Given %s, When authenticating, Then expected %s with %d scopes, got %+v", tt.name, tt.expectedName, tt.expectedScopes, principal)
+			}
+		})
+	}
+}
+
+func TestNewAuthenticatorErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []config.APIKeyConfig
+	}{
+		{"missing name", []config.APIKeyConfig{{Key: "secret"}}},
+		{"duplicate name", []config.APIKeyConfig{{Name: "a", Key: "one"}, {Name: "a", Key: "two"}}},
+		{"key and hash", []config.APIKeyConfig{{Name: "a", Key: "one", Hash: "sha256:00:00"}}},
+		{"neither key nor hash", []config.APIKeyConfig{{Name: "a"}}},
+		{"malformed hash", []config.APIKeyConfig{{Name: "a", Hash: "plaintext"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			_, err := NewAuthenticator(tt.keys, "", nil)
+
+			// Then
+			if err == nil {
+				t.Errorf("Given %s, When creating an authenticator, Then expected an error, got nil", tt.name)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorReload(t *testing.T) {
+	tests := []struct {
+		name              string
+		keys              []config.APIKeyConfig
+		wantErr           bool
+		expectedOldKeyErr error
+		expectedNewKeyErr error
+	}{
+		{
+			name:              "revoked key",
+			keys:              []config.APIKeyConfig{{Name: "partner", Key: "old-secret", Disabled: true}},
+			expectedOldKeyErr: ErrKeyDisabled,
+			expectedNewKeyErr: ErrUnknownKey,
+		},
+		{
+			name:              "rotated key",
+			keys:              []config.APIKeyConfig{{Name: "partner", Key: "new-secret"}},
+			expectedOldKeyErr: ErrUnknownKey,
+		},
+		{
+			name:              "invalid keys keep the current ones",
+			keys:              []config.APIKeyConfig{{Name: "partner", Key: "new-secret"}, {Name: "partner", Key: "other-secret"}},
+			wantErr:           true,
+			expectedNewKeyErr: ErrUnknownKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			authenticator, err := NewAuthenticator([]config.APIKeyConfig{{Name: "partner", Key: "old-secret"}}, "", nil)
+			if err != nil {
+				t.Fatalf("could not create authenticator: %v", err)
+			}
+
+			// When
+			err = authenticator.Reload(tt.keys)
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Given a %s, When reloading, Then expected error %v, got %v", tt.name, tt.wantErr, err)
+			}
+			if _, err := authenticator.Authenticate("old-secret"); !errors.Is(err, tt.expectedOldKeyErr) {
+				t.Errorf("Given a %s, When authenticating the old key, Then expected error %v, got %v", tt.name, tt.expectedOldKeyErr, err)
+			}
+			if _, err := authenticator.Authenticate("new-secret"); !errors.Is(err, tt.expectedNewKeyErr) {
+				t.Errorf("Given a %s, When authenticating the new key, Then expected error %v, got %v", tt.name, tt.expectedNewKeyErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package apikey
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"case-studies/grpc/internal/atomicfile"
+	"case-studies/grpc/internal/config"
+)
+
+const keySize = 32
+
+var (
+	ErrKeyNotFound  = errors.New("API key not found")
+	ErrDuplicateKey = errors.New("API key name already exists")
+)
+
+// Generate returns a new random API key
+func Generate() (string, error) {
+	b := make([]byte, keySize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate key: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ConfigFile edits api-config.yaml in place. It works on the YAML node tree so comments,
+// ordering and fields this package does not know about survive an edit.
+type ConfigFile struct {
+	path string
+	perm os.FileMode
+	doc  yaml.Node
+	keys *yaml.Node
+}
+
+// LoadConfigFile reads the file at path; a missing file starts an empty configuration
+func LoadConfigFile(path string) (*ConfigFile, error) {
+	f := &ConfigFile{path: path, perm: 0o600}
+	content, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if info, err := os.Stat(path); err == nil {
+			f.perm = info.Mode().Perm()
+		}
+		if err := yaml.Unmarshal(content, &f.doc); err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", path, err)
+		}
+	}
+
+	if f.doc.Kind == 0 {
+		f.doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := f.doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: expected a mapping at the top level", path)
+	}
+	if _, value := mappingField(root, "api_keys"); value != nil {
+		f.keys = value
+	} else {
+		f.keys = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "api_keys"}, f.keys)
+	}
+	// "api_keys:" with nothing after it parses as null
+	if f.keys.Kind == yaml.ScalarNode && f.keys.Tag == "!!null" {
+		*f.keys = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	}
+	if f.keys.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s: api_keys must be a list", path)
+	}
+	return f, nil
+}
+
+// Keys decodes every entry
+func (f *ConfigFile) Keys() ([]config.APIKeyConfig, error) {
+	keys := make([]config.APIKeyConfig, 0, len(f.keys.Content))
+	for _, node := range f.keys.Content {
+		var k config.APIKeyConfig
+		if err := node.Decode(&k); err != nil {
+			return nil, fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// Add appends a new entry; names must be unique
+func (f *ConfigFile) Add(k config.APIKeyConfig) error {
+	if _, err := f.find(k.Name); err == nil {
+		return fmt.Errorf("%w: %s", ErrDuplicateKey, k.Name)
+	}
+	var node yaml.Node
+	if err := node.Encode(k); err != nil {
+		return err
+	}
+	f.keys.Content = append(f.keys.Content, &node)
+	return nil
+}
+
+// Update applies mutate to the named entry. Only the fields mutate changes are rewritten.
+func (f *ConfigFile) Update(name string, mutate func(*config.APIKeyConfig)) error {
+	node, err := f.find(name)
+	if err != nil {
+		return err
+	}
+	var before config.APIKeyConfig
+	if err := node.Decode(&before); err != nil {
+		return err
+	}
+	after := before
+	after.Scopes = append([]string(nil), before.Scopes...)
+	mutate(&after)
+
+	var beforeNode, afterNode yaml.Node
+	if err := beforeNode.Encode(before); err != nil {
+		return err
+	}
+	if err := afterNode.Encode(after); err != nil {
+		return err
+	}
+	// Drop fields that became empty, then set the ones that changed
+	for i := 0; i < len(beforeNode.Content); i += 2 {
+		field := beforeNode.Content[i].Value
+		if _, value := mappingField(&afterNode, field); value == nil {
+			removeMappingField(node, field)
+		}
+	}
+	for i := 0; i < len(afterNode.Content); i += 2 {
+		field, value := afterNode.Content[i].Value, afterNode.Content[i+1]
+		if _, old := mappingField(&beforeNode, field); old != nil && nodesEqual(old, value) {
+			continue
+		}
+		if _, existing := mappingField(node, field); existing != nil {
+			*existing = *value
+		} else {
+			node.Content = append(node.Content, afterNode.Content[i], value)
+		}
+	}
+	return nil
+}
+
+// Remove deletes the named entry
+func (f *ConfigFile) Remove(name string) error {
+	node, err := f.find(name)
+	if err != nil {
+		return err
+	}
+	for i, n := range f.keys.Content {
+		if n == node {
+			f.keys.Content = append(f.keys.Content[:i], f.keys.Content[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Save writes the file atomically, keeping its permissions
+func (f *ConfigFile) Save() error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&f.doc); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(f.path, buf.Bytes(), f.perm)
+}
+
+func (f *ConfigFile) find(name string) (*yaml.Node, error) {
+	for _, node := range f.keys.Content {
+		if _, value := mappingField(node, "name"); value != nil && value.Value == name {
+			return node, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+}
+
+func mappingField(mapping *yaml.Node, field string) (key, value *yaml.Node) {
+	if mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == field {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func removeMappingField(mapping *yaml.Node, field string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == field {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func nodesEqual(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !nodesEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+package apikey
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"case-studies/grpc/internal/config"
+)
+
+const testAPIConfig = `# Keys for local development
+api_keys:
+  - name: reader
+    key: reader-secret # legacy plaintext entry
+    owner: team-a
+  - name: editor
+    hash: "sha256:00ff:abcd"
+    scopes:
+      - movies:read
+      - movies:write
+`
+
+func writeAPIConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "api-config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o640); err != nil {
+		t.Fatalf("could not write api config: %v", err)
+	}
+	return path
+}
+
+func TestConfigFileEdits(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		edit        func(f *ConfigFile) error
+		expectedErr error
+		contains    []string
+		excludes    []string
+	}{
+		{
+			name: "add keeps existing entries and comments",
+			edit: func(f *ConfigFile) error {
+				return f.Add(config.APIKeyConfig{Name: "partner", Hash: "sha256:11:22", ExpiresAt: expiresAt})
+			},
+			contains: []string{"# Keys for local development", "# legacy plaintext entry", "owner: team-a", "name: partner", "expires_at: 2026-01-01T00:00:00Z"},
+		},
+		{
+			name: "update replaces only changed fields",
+			edit: func(f *ConfigFile) error {
+				return f.Update("reader", func(k *config.APIKeyConfig) {
+					k.Key = ""
+					k.Hash = "sha256:33:44"
+				})
+			},
+			contains: []string{"hash: sha256:33:44", "owner: team-a", `hash: "sha256:00ff:abcd"`},
+			excludes: []string{"reader-secret"},
+		},
+		{
+			name: "revoke sets the disabled flag",
+			edit: func(f *ConfigFile) error {
+				return f.Update("editor", func(k *config.APIKeyConfig) { k.Disabled = true })
+			},
+			contains: []string{"disabled: true", "- movies:write"},
+		},
+		{
+			name:     "remove drops the entry",
+			edit:     func(f *ConfigFile) error { return f.Remove("reader") },
+			contains: []string{"name: editor"},
+			excludes: []string{"name: reader"},
+		},
+		{
+			name:        "duplicate name",
+			edit:        func(f *ConfigFile) error { return f.Add(config.APIKeyConfig{Name: "editor", Hash: "sha256:11:22"}) },
+			expectedErr: ErrDuplicateKey,
+		},
+		{
+			name:        "unknown name",
+			edit:        func(f *ConfigFile) error { return f.Update("nobody", func(k *config.APIKeyConfig) {}) },
+			expectedErr: ErrKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			path := writeAPIConfig(t, testAPIConfig)
+			f, err := LoadConfigFile(path)
+			if err != nil {
+				t.Fatalf("could not load api config: %v", err)
+			}
+
+			// When
+			err = tt.edit(f)
+
+			// Then
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Given %s, When editing, Then expected error %v, got %v", tt.name, tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if err := f.Save(); err != nil {
+				t.Fatalf("Given %s, When saving, Then expected no error, got %v", tt.name, err)
+			}
+			content, _ := os.ReadFile(path)
+			for _, want := range tt.contains {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("Given %s, When saved, Then expected the file to contain %q, got:\n%s", tt.name, want, content)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(string(content), unwanted) {
+					t.Errorf("Given %s, When saved, Then expected the file not to contain %q, got:\n%s", tt.name, unwanted, content)
+				}
+			}
+			if info, _ := os.Stat(path); info.Mode().Perm() != 0o640 {
+				t.Errorf("Given %s, When saved, Then expected permissions 0640 to be kept, got %v", tt.name, info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestConfigFileKeys(t *testing.T) {
+	// Given
+	f, err := LoadConfigFile(writeAPIConfig(t, testAPIConfig))
+	if err != nil {
+		t.Fatalf("could not load api config: %v", err)
+	}
+
+	// When
+	keys, err := f.Keys()
+
+	// Then
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("Given two entries, When listing keys, Then expected 2 keys, got %d (err %v)", len(keys), err)
+	}
+	if keys[1].Name != "editor" || len(keys[1].Scopes) != 2 {
+		t.Errorf("Given the editor entry, When listing keys, Then expected two scopes, got %+v", keys[1])
+	}
+}
+
+func TestLoadConfigFileMissing(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "api-config.yaml")
+	f, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("Given a missing file, When loading, Then expected no error, got %v", err)
+	}
+
+	// When
+	if err := f.Add(config.APIKeyConfig{Name: "first", Hash: "sha256:11:22"}); err != nil {
+		t.Fatalf("could not add key: %v", err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatalf("could not save: %v", err)
+	}
+
+	// Then
+	reloaded, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("could not reload: %v", err)
+	}
+	keys, _ := reloaded.Keys()
+	if len(keys) != 1 || keys[0].Name != "first" {
+		t.Errorf("Given a new file, When saved and reloaded, Then expected the first key, got %+v", keys)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("Given a new file, When saved, Then expected permissions 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	// When
+	first, err := Generate()
+	second, _ := Generate()
+
+	// Then
+	if err != nil || len(first) < 40 || first == second {
+		t.Errorf("Given two generated keys, When compared, Then expected distinct long keys, got %q and %q (err %v)", first, second, err)
+	}
+}
@@ -0,0 +1,71 @@
+package apikey
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// hashScheme prefixes stored hashes so the algorithm can change without breaking existing entries
+const hashScheme = "sha256"
+
+const saltSize = 16
+
+var ErrInvalidHash = errors.New("invalid API key hash")
+
+// Hash returns the stored form of key: a random salt and HMAC-SHA256(pepper, salt || key),
+// encoded as "sha256:<salt hex>:<digest hex>". The pepper is kept out of the config file so a
+// leaked api-config.yaml alone is not enough to brute-force the keys.
+func Hash(key, pepper string) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("could not generate salt: %w", err)
+	}
+	return hashScheme + ":" + hex.EncodeToString(salt) + ":" + hex.EncodeToString(digest(salt, key, pepper)), nil
+}
+
+// Verify reports whether key matches a hash produced by Hash, in constant time
+func Verify(encoded, key, pepper string) (bool, error) {
+	salt, expected, err := parseHash(encoded)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal(digest(salt, key, pepper), expected), nil
+}
+
+// ValidateHash checks that encoded is in the format produced by Hash
+func ValidateHash(encoded string) error {
+	_, _, err := parseHash(encoded)
+	return err
+}
+
+func parseHash(encoded string) (salt, sum []byte, err error) {
+	parts := strings.Split(encoded, ":")
+	if len(parts) != 3 || parts[0] != hashScheme {
+		return nil, nil, fmt.Errorf("%w: expected %s:<salt>:<digest>", ErrInvalidHash, hashScheme)
+	}
+	if salt, err = hex.DecodeString(parts[1]); err != nil || len(salt) == 0 {
+		return nil, nil, fmt.Errorf("%w: bad salt", ErrInvalidHash)
+	}
+	if sum, err = hex.DecodeString(parts[2]); err != nil || len(sum) != sha256.Size {
+		return nil, nil, fmt.Errorf("%w: bad digest", ErrInvalidHash)
+	}
+	return salt, sum, nil
+}
+
+func digest(salt []byte, key, pepper string) []byte {
+	mac := hmac.New(sha256.New, []byte(pepper))
+	mac.Write(salt)
+	mac.Write([]byte(key))
+	return mac.Sum(nil)
+}
+
+// equalPlaintext compares a plaintext key from the config in constant time
+func equalPlaintext(configured, key string) bool {
+	return subtle.ConstantTimeCompare([]byte(configured), []byte(key)) == 1
+}
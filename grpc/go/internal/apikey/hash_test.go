@@ -0,0 +1,88 @@
+package apikey
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHashAndVerify(t *testing.T) {
+	hash, err := Hash("abcd-efgh-1234-5678", "pepper")
+	if err != nil {
+		t.Fatalf("could not hash key: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		pepper   string
+		expected bool
+	}{
+		{"matching key and pepper", "abcd-efgh-1234-5678", "pepper", true},
+		{"wrong key", "abcd-efgh-1234-5679", "pepper", false},
+		{"wrong pepper", "abcd-efgh-1234-5678", "other", false},
+		{"empty key", "", "pepper", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			ok, err := Verify(hash, tt.key, tt.pepper)
+
+			// Then
+			if err != nil {
+				t.Fatalf("Given %s, When verifying, Then expected no error, got %v", tt.name, err)
+			}
+			if ok != tt.expected {
+				t.Errorf("Given %s, When verifying, Then expected %v, got %v", tt.name, tt.expected, ok)
+			}
+		})
+	}
+}
+
+func TestHashIsSalted(t *testing.T) {
+	// Given
+	first, _ := Hash("abcd-efgh-1234-5678", "")
+
+	// When
+	second, _ := Hash("abcd-efgh-1234-5678", "")
+
+	// Then
+	if first == second {
+		t.Errorf("Given the same key twice, When hashing, Then expected different salts, got %s twice", first)
+	}
+	if strings.Contains(first, "abcd") {
+		t.Errorf("Given a key, When hashing, Then expected the key not to appear in %s", first)
+	}
+}
+
+func TestValidateHash(t *testing.T) {
+	valid, _ := Hash("key", "")
+
+	tests := []struct {
+		name    string
+		hash    string
+		wantErr bool
+	}{
+		{"valid hash", valid, false},
+		{"plaintext", "abcd-efgh-1234-5678", true},
+		{"unknown scheme", "md5:00:00", true},
+		{"bad salt", "sha256:zz:" + strings.Repeat("0", 64), true},
+		{"short digest", "sha256:00:0000", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			err := ValidateHash(tt.hash)
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Given %s, When validating, Then expected error = %v, got %v", tt.name, tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidHash) {
+				t.Errorf("Given %s, When validating, Then expected ErrInvalidHash, got %v", tt.name, err)
+			}
+		})
+	}
+}
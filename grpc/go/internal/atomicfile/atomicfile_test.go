@@ -0,0 +1,63 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *string
+	}{
+		{"new file", nil},
+		{"replaces existing file", func() *string { s := "old content"; return &s }()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			dir := t.TempDir()
+			path := filepath.Join(dir, "data.json")
+			if tt.existing != nil {
+				if err := os.WriteFile(path, []byte(*tt.existing), 0o600); err != nil {
+					t.Fatalf("could not create existing file: %v", err)
+				}
+			}
+
+			// When
+			err := WriteFile(path, []byte("new content"), 0o644)
+
+			// Then
+			if err != nil {
+				t.Fatalf("Given %s, When writing, Then expected no error, got %v", tt.name, err)
+			}
+			content, _ := os.ReadFile(path)
+			if string(content) != "new content" {
+				t.Errorf("Given %s, When writing, Then expected %q, got %q", tt.name, "new content", content)
+			}
+			info, _ := os.Stat(path)
+			if info.Mode().Perm() != 0o644 {
+				t.Errorf("Given %s, When writing, Then expected mode 0644, got %v", tt.name, info.Mode().Perm())
+			}
+			entries, _ := os.ReadDir(dir)
+			if len(entries) != 1 {
+				t.Errorf("Given %s, When writing, Then expected no temporary files left, got %d entries", tt.name, len(entries))
+			}
+		})
+	}
+}
+
+func TestWriteFileMissingDirectory(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "missing", "data.json")
+
+	// When
+	err := WriteFile(path, []byte("content"), 0o644)
+
+	// Then
+	if err == nil {
+		t.Errorf("Given a missing directory, When writing, Then expected an error, got nil")
+	}
+}
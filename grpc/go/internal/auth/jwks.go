@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// minRSAKeyBits rejects RSA keys too short to be trusted
+const minRSAKeyBits = 2048
+
+// jsonWebKey is the subset of RFC 7517 needed for RS256 and ES256 public keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type verificationKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// KeySet holds the public keys tokens may be signed with
+type KeySet struct {
+	keys []verificationKey
+}
+
+// LoadJWKS reads a JSON Web Key Set file
+func LoadJWKS(path string) (*KeySet, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(content)
+}
+
+// ParseJWKS parses a JSON Web Key Set. Keys that are not for signatures are skipped;
+// unsupported or malformed signing keys are an error so a bad file is noticed at startup.
+func ParseJWKS(content []byte) (*KeySet, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("could not parse JWKS: %w", err)
+	}
+
+	set := &KeySet{}
+	for i, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, alg, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("JWKS key %d (kid %q): %w", i, jwk.Kid, err)
+		}
+		if jwk.Alg != "" && jwk.Alg != alg {
+			return nil, fmt.Errorf("JWKS key %d (kid %q): alg %s does not match a %s key", i, jwk.Kid, jwk.Alg, jwk.Kty)
+		}
+		set.keys = append(set.keys, verificationKey{kid: jwk.Kid, alg: alg, key: key})
+	}
+	if len(set.keys) == 0 {
+		return nil, errors.New("JWKS contains no signing keys")
+	}
+	return set, nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, string, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, "", fmt.Errorf("bad modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, "", errors.New("bad exponent")
+		}
+		if n.BitLen() < minRSAKeyBits {
+			return nil, "", fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, "RS256", nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, "", fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, "", fmt.Errorf("bad x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, "", fmt.Errorf("bad y coordinate: %w", err)
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, "", errors.New("point is not on P-256")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, "ES256", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// candidates returns the keys that may have signed a token with the given header
+func (s *KeySet) candidates(kid, alg string) []verificationKey {
+	var keys []verificationKey
+	for _, k := range s.keys {
+		if k.alg == alg && (kid == "" || k.kid == kid) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
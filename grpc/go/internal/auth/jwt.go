@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultScopeClaim is the OAuth 2.0 claim holding space-separated scopes
+const DefaultScopeClaim = "scope"
+
+// maxTokenLength bounds the work done on a token before its signature is checked
+const maxTokenLength = 8 << 10
+
+var (
+	ErrMalformedToken       = errors.New("malformed token")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrInvalidSignature     = errors.New("invalid token signature")
+	ErrTokenExpired         = errors.New("token has expired")
+	ErrTokenNotYetValid     = errors.New("token is not yet valid")
+	ErrInvalidIssuer        = errors.New("unexpected token issuer")
+	ErrInvalidAudience      = errors.New("token is not for this audience")
+)
+
+// VerifierOptions configures which tokens a JWTVerifier accepts
+type VerifierOptions struct {
+	Issuer   string
+	Audience string
+	// ScopeClaim names the claim holding scopes, either space-separated or as an array; defaults to "scope"
+	ScopeClaim string
+	// ScopeMapping translates claim values to server scopes; unmapped values are kept as they are
+	ScopeMapping map[string][]string
+	// ClockSkew is the leeway allowed on exp and nbf
+	ClockSkew time.Duration
+	// Now defaults to time.Now
+	Now func() time.Time
+}
+
+// JWTVerifier validates RS256 and ES256 bearer tokens against a local key set
+type JWTVerifier struct {
+	keys    *KeySet
+	options VerifierOptions
+}
+
+// NewJWTVerifier returns a verifier for tokens issued by options.Issuer for options.Audience
+func NewJWTVerifier(keys *KeySet, options VerifierOptions) (*JWTVerifier, error) {
+	if keys == nil {
+		return nil, errors.New("JWT verifier needs a key set")
+	}
+	if options.Issuer == "" || options.Audience == "" {
+		return nil, errors.New("JWT verifier needs an issuer and an audience")
+	}
+	if options.ScopeClaim == "" {
+		options.ScopeClaim = DefaultScopeClaim
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	return &JWTVerifier{keys: keys, options: options}, nil
+}
+
+type tokenHeader struct {
+	Alg  string   `json:"alg"`
+	Kid  string   `json:"kid"`
+	Crit []string `json:"crit"`
+}
+
+type tokenClaims struct {
+	Issuer    string       `json:"iss"`
+	Subject   string       `json:"sub"`
+	Audience  audience     `json:"aud"`
+	ExpiresAt *numericDate `json:"exp"`
+	NotBefore *numericDate `json:"nbf"`
+}
+
+// Verify checks the token's signature and registered claims and returns its subject as a principal
+func (v *JWTVerifier) Verify(token string) (Principal, error) {
+	if len(token) > maxTokenLength {
+		return Principal{}, fmt.Errorf("%w: token too long", ErrMalformedToken)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Principal{}, fmt.Errorf("%w: expected three segments", ErrMalformedToken)
+	}
+
+	var header tokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Principal{}, fmt.Errorf("%w: header: %v", ErrMalformedToken, err)
+	}
+	if header.Alg != "RS256" && header.Alg != "ES256" {
+		return Principal{}, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, header.Alg)
+	}
+	if len(header.Crit) > 0 {
+		return Principal{}, fmt.Errorf("%w: critical header parameters %v", ErrMalformedToken, header.Crit)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: signature: %v", ErrMalformedToken, err)
+	}
+	if !v.verifySignature(header, parts[0]+"."+parts[1], signature) {
+		return Principal{}, ErrInvalidSignature
+	}
+
+	var claims tokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Principal{}, fmt.Errorf("%w: claims: %v", ErrMalformedToken, err)
+	}
+	var rawClaims map[string]json.RawMessage
+	if err := decodeSegment(parts[1], &rawClaims); err != nil {
+		return Principal{}, fmt.Errorf("%w: claims: %v", ErrMalformedToken, err)
+	}
+
+	now := v.options.Now()
+	switch {
+	case claims.ExpiresAt == nil:
+		return Principal{}, fmt.Errorf("%w: missing exp", ErrMalformedToken)
+	case !now.Before(claims.ExpiresAt.Add(v.options.ClockSkew)):
+		return Principal{}, ErrTokenExpired
+	case claims.NotBefore != nil && now.Add(v.options.ClockSkew).Before(claims.NotBefore.Time):
+		return Principal{}, ErrTokenNotYetValid
+	case claims.Issuer != v.options.Issuer:
+		return Principal{}, fmt.Errorf("%w: %q", ErrInvalidIssuer, claims.Issuer)
+	case !slices.Contains(claims.Audience, v.options.Audience):
+		return Principal{}, ErrInvalidAudience
+	case claims.Subject == "":
+		return Principal{}, fmt.Errorf("%w: missing sub", ErrMalformedToken)
+	}
+
+	scopes, err := v.scopes(rawClaims[v.options.ScopeClaim])
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %s claim: %v", ErrMalformedToken, v.options.ScopeClaim, err)
+	}
+	return Principal{Name: claims.Subject, Scopes: scopes, Method: MethodJWT}, nil
+}
+
+func (v *JWTVerifier) verifySignature(header tokenHeader, signingInput string, signature []byte) bool {
+	digest := sha256.Sum256([]byte(signingInput))
+	for _, k := range v.keys.candidates(header.Kid, header.Alg) {
+		switch key := k.key.(type) {
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+				return true
+			}
+		case *ecdsa.PublicKey:
+			// JWS encodes ES256 signatures as the fixed-size concatenation r || s
+			if len(signature) != 64 {
+				continue
+			}
+			r := new(big.Int).SetBytes(signature[:32])
+			s := new(big.Int).SetBytes(signature[32:])
+			if ecdsa.Verify(key, digest[:], r, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (v *JWTVerifier) scopes(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var values []string
+	var spaceSeparated string
+	if err := json.Unmarshal(raw, &spaceSeparated); err == nil {
+		values = strings.Fields(spaceSeparated)
+	} else if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, errors.New("expected a string or an array of strings")
+	}
+
+	var scopes []string
+	for _, value := range values {
+		mapped, ok := v.options.ScopeMapping[value]
+		if !ok {
+			mapped = []string{value}
+		}
+		for _, scope := range mapped {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// audience accepts both forms of the aud claim: a single string or an array
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return errors.New("aud must be a string or an array of strings")
+	}
+	*a = many
+	return nil
+}
+
+// numericDate is a JWT timestamp in seconds since the epoch, possibly fractional
+type numericDate struct {
+	time.Time
+}
+
+func (d *numericDate) UnmarshalJSON(b []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(b, &seconds); err != nil {
+		return errors.New("expected a numeric date")
+	}
+	if math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return errors.New("expected a finite numeric date")
+	}
+	whole, fraction := math.Modf(seconds)
+	d.Time = time.Unix(int64(whole), int64(fraction*1e9))
+	return nil
+}
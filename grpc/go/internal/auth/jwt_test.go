@@ -0,0 +1,287 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testSigner struct {
+	kid string
+	alg string
+	key crypto.Signer
+}
+
+func newTestSigners(t *testing.T) (rsaSigner, ecSigner testSigner) {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate EC key: %v", err)
+	}
+	return testSigner{kid: "rsa-1", alg: "RS256", key: rsaKey}, testSigner{kid: "ec-1", alg: "ES256", key: ecKey}
+}
+
+// testJWKS renders the public halves of signers as a JWKS document
+func testJWKS(t *testing.T, signers ...testSigner) []byte {
+	t.Helper()
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	var keys []map[string]string
+	for _, s := range signers {
+		switch pub := s.key.Public().(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, map[string]string{"kty": "RSA", "kid": s.kid, "alg": s.alg, "use": "sig", "n": b64(pub.N.Bytes()), "e": b64(big.NewInt(int64(pub.E)).Bytes())})
+		case *ecdsa.PublicKey:
+			keys = append(keys, map[string]string{"kty": "EC", "kid": s.kid, "crv": "P-256", "x": b64(pub.X.FillBytes(make([]byte, 32))), "y": b64(pub.Y.FillBytes(make([]byte, 32)))})
+		}
+	}
+	content, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		t.Fatalf("could not encode JWKS: %v", err)
+	}
+	return content
+}
+
+func signTestToken(t *testing.T, s testSigner, header map[string]interface{}, claims map[string]interface{}) string {
+	t.Helper()
+	if header == nil {
+		header = map[string]interface{}{"alg": s.alg, "kid": s.kid, "typ": "JWT"}
+	}
+	encode := func(v interface{}) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("could not encode token segment: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signingInput := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch key := s.key.(type) {
+	case *rsa.PrivateKey:
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("could not sign token: %v", err)
+		}
+		signature = sig
+	case *ecdsa.PrivateKey:
+		r, sv, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatalf("could not sign token: %v", err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), sv.FillBytes(make([]byte, 32))...)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTVerifierVerify(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	rsaSigner, ecSigner := newTestSigners(t)
+	_, untrusted := newTestSigners(t)
+	keys, err := ParseJWKS(testJWKS(t, rsaSigner, ecSigner))
+	if err != nil {
+		t.Fatalf("could not parse JWKS: %v", err)
+	}
+	verifier, err := NewJWTVerifier(keys, VerifierOptions{
+		Issuer:       "https://auth.example.com",
+		Audience:     "movie-api",
+		ScopeMapping: map[string][]string{"movie-editor": {"movies:read", "movies:write"}},
+		ClockSkew:    30 * time.Second,
+		Now:          func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("could not create verifier: %v", err)
+	}
+
+	validClaims := func(overrides map[string]interface{}) map[string]interface{} {
+		claims := map[string]interface{}{
+			"iss":   "https://auth.example.com",
+			"aud":   "movie-api",
+			"sub":   "catalogue-service",
+			"exp":   now.Add(time.Hour).Unix(),
+			"nbf":   now.Add(-time.Minute).Unix(),
+			"scope": "movies:read",
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(claims, k)
+				continue
+			}
+			claims[k] = v
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedErr    error
+		expectedScopes []string
+	}{
+		{
+			name:           "RS256 token",
+			token:          signTestToken(t, rsaSigner, nil, validClaims(nil)),
+			expectedScopes: []string{"movies:read"},
+		},
+		{
+			name:           "ES256 token",
+			token:          signTestToken(t, ecSigner, nil, validClaims(nil)),
+			expectedScopes: []string{"movies:read"},
+		},
+		{
+			name:           "audience array and mapped scopes",
+			token:          signTestToken(t, rsaSigner, nil, validClaims(map[string]interface{}{"aud": []string{"other", "movie-api"}, "scope": "movie-editor"})),
+			expectedScopes: []string{"movies:read", "movies:write"},
+		},
+		{
+			name:           "scope array",
+			token:          signTestToken(t, rsaSigner, nil, validClaims(map[string]interface{}{"scope": []string{"movies:read", "movies:read"}})),
+			expectedScopes: []string{"movies:read"},
+		},
+		{
+			name:           "expiry within the clock skew",
+			token:          signTestToken(t, rsaSigner, nil, validClaims(map[string]interface{}{"exp": now.Add(-10 * time.Second).Unix()})),
+			expectedScopes: []string{"movies:read"},
+		},
+		{
+			name:        "expired token",
+			token:       signTestToken(t, rsaSigner, nil, validClaims(map[string]interface{}{"exp": now.Add(-time.Minute).Unix()})),
+			expectedErr: ErrTokenExpired,
+		},
+		{
+			name:        "token without exp",
+			token:       signTestToken(t, rsaSigner, nil, validClaims(map[string]interface{}{"exp": nil})),
+			expectedErr: ErrMalformedToken,
+		},
+		{
+			name:        "token not yet valid",
+			token:       signTestToken(t, rsaSigner, nil, validClaims(map[string]interface{}{"nbf": now.Add(time.Minute).Unix()})),
+			expectedErr: ErrTokenNotYetValid,
+		},
+		{
+			name:        "wrong issuer",
+			token:       signTestToken(t, rsaSigner, nil, validClaims(map[string]interface{}{"iss": "https://evil.example.com"})),
+			expectedErr: ErrInvalidIssuer,
+		},
+		{
+			name:        "wrong audience",
+			token:       signTestToken(t, rsaSigner, nil, validClaims(map[string]interface{}{"aud": "billing-api"})),
+			expectedErr: ErrInvalidAudience,
+		},
+		{
+			name:        "untrusted key",
+			token:       signTestToken(t, testSigner{kid: "ec-1", alg: "ES256", key: untrusted.key}, nil, validClaims(nil)),
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "algorithm confusion",
+			token:       signTestToken(t, rsaSigner, map[string]interface{}{"alg": "ES256", "kid": "rsa-1"}, validClaims(nil)),
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "alg none",
+			token:       signTestToken(t, rsaSigner, map[string]interface{}{"alg": "none"}, validClaims(nil)),
+			expectedErr: ErrUnsupportedAlgorithm,
+		},
+		{
+			name:        "tampered claims",
+			token:       tamperClaims(t, signTestToken(t, rsaSigner, nil, validClaims(nil))),
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "not a JWT",
+			token:       "abcd-efgh-1234-5678",
+			expectedErr: ErrMalformedToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			principal, err := verifier.Verify(tt.token)
+
+			// Then
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Given %s, When verifying, Then expected error %v, got %v", tt.name, tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if principal.Name != "catalogue-service" || principal.Method != MethodJWT {
+				t.Errorf("Given %s, When verifying, Then expected the catalogue-service subject, got %+v", tt.name, principal)
+			}
+			if !slices.Equal(principal.Scopes, tt.expectedScopes) {
+				t.Errorf("Given %s, When verifying, Then expected scopes %v, got %v", tt.name, tt.expectedScopes, principal.Scopes)
+			}
+		})
+	}
+}
+
+// tamperClaims swaps the payload for one granting write access while keeping the original signature
+func tamperClaims(t *testing.T, token string) string {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	payload = []byte(strings.Replace(string(payload), `"movies:read"`, `"movies:write"`, 1))
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	return strings.Join(parts, ".")
+}
+
+func TestParseJWKSErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid JSON", "{"},
+		{"no keys", `{"keys": []}`},
+		{"only encryption keys", `{"keys": [{"kty": "RSA", "use": "enc", "n": "AQAB", "e": "AQAB"}]}`},
+		{"short RSA key", `{"keys": [{"kty": "RSA", "n": "AQAB", "e": "AQAB"}]}`},
+		{"unsupported curve", `{"keys": [{"kty": "EC", "crv": "P-384", "x": "AQ", "y": "AQ"}]}`},
+		{"point off the curve", `{"keys": [{"kty": "EC", "crv": "P-256", "x": "AQ", "y": "AQ"}]}`},
+		{"symmetric key", `{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			_, err := ParseJWKS([]byte(tt.content))
+
+			// Then
+			if err == nil {
+				t.Errorf("Given %s, When parsing the JWKS, Then expected an error, got nil", tt.name)
+			}
+		})
+	}
+}
+
+func TestParseJWKSRejectsMismatchedAlg(t *testing.T) {
+	// Given
+	_, ecSigner := newTestSigners(t)
+	var document map[string][]map[string]string
+	json.Unmarshal(testJWKS(t, ecSigner), &document)
+	document["keys"][0]["alg"] = "RS256"
+	content, _ := json.Marshal(document)
+
+	// When
+	_, err := ParseJWKS(content)
+
+	// Then
+	if err == nil {
+		t.Errorf("Given an EC key labelled RS256, When parsing the JWKS, Then expected an error, got nil")
+	}
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// PeerIdentity is the subject of the client certificate a connection was verified with
+type PeerIdentity struct {
+	CommonName string
+	// URIs are the certificate's URI subject alternative names, e.g. SPIFFE IDs
+	URIs []string
+}
+
+// String names the identity for logs, preferring the first URI SAN over the common name
+func (id PeerIdentity) String() string {
+	if len(id.URIs) > 0 {
+		return id.URIs[0]
+	}
+	return id.CommonName
+}
+
+// PeerIdentityFromContext returns the identity of the verified client certificate on the
+// connection carrying ctx. It reports false for plaintext connections and for TLS connections
+// whose client certificate was not verified against the CA pool.
+func PeerIdentityFromContext(ctx context.Context) (PeerIdentity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return PeerIdentity{}, false
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return PeerIdentity{}, false
+	}
+	return peerIdentity(tlsInfo.State.VerifiedChains[0][0]), true
+}
+
+func peerIdentity(cert *x509.Certificate) PeerIdentity {
+	id := PeerIdentity{CommonName: cert.Subject.CommonName}
+	for _, uri := range cert.URIs {
+		id.URIs = append(id.URIs, uri.String())
+	}
+	return id
+}
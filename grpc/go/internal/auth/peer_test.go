@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func TestPeerIdentityFromContext(t *testing.T) {
+	spiffeID, _ := url.Parse("spiffe://case-studies/movie-admin")
+	adminCert := &x509.Certificate{Subject: pkix.Name{CommonName: "movie-admin"}, URIs: []*url.URL{spiffeID}}
+	peerContext := func(authInfo credentials.AuthInfo) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: authInfo})
+	}
+
+	tests := []struct {
+		name             string
+		ctx              context.Context
+		expectedOK       bool
+		expectedIdentity PeerIdentity
+	}{
+		{
+			name:             "verified client certificate",
+			ctx:              peerContext(credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{adminCert}}}}),
+			expectedOK:       true,
+			expectedIdentity: PeerIdentity{CommonName: "movie-admin", URIs: []string{"spiffe://case-studies/movie-admin"}},
+		},
+		{
+			name: "unverified client certificate",
+			ctx:  peerContext(credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{adminCert}}}),
+		},
+		{
+			name: "connection without TLS",
+			ctx:  peerContext(nil),
+		},
+		{
+			name: "context without a peer",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			identity, ok := PeerIdentityFromContext(tt.ctx)
+
+			// Then
+			if ok != tt.expectedOK {
+				t.Fatalf("Given a %s, When reading the peer identity, Then expected ok %v, got %v", tt.name, tt.expectedOK, ok)
+			}
+			if identity.CommonName != tt.expectedIdentity.CommonName || !slices.Equal(identity.URIs, tt.expectedIdentity.URIs) {
+				t.Errorf("Given a %s, When reading the peer identity, Then expected %+v, got %+v", tt.name, tt.expectedIdentity, identity)
+			}
+		})
+	}
+}
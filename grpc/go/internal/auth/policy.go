@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Identity pattern prefixes used in a peer policy
+const (
+	IdentityCommonNamePrefix = "cn:"
+	IdentityURIPrefix        = "uri:"
+	// AnyIdentity matches every verified client certificate
+	AnyIdentity = "*"
+	// AnyMethod matches every RPC
+	AnyMethod = "*"
+)
+
+// PeerPolicy maps client certificate identities to the RPC methods they may call.
+// Anything no rule grants is refused.
+type PeerPolicy struct {
+	rules []PeerPolicyRule
+}
+
+// PeerPolicyRule grants an identity a set of methods
+type PeerPolicyRule struct {
+	// Identity is "cn:<common name>", "uri:<URI SAN>" or "*"
+	Identity string `yaml:"identity"`
+	// Methods are full method names ("/movie.MovieAdmin/CreateMovie"), service prefixes
+	// ("/movie.MovieAdmin/") or "*"
+	Methods []string `yaml:"methods"`
+}
+
+// LoadPeerPolicy reads a policy file
+func LoadPeerPolicy(path string) (*PeerPolicy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePeerPolicy(content)
+}
+
+// ParsePeerPolicy parses a policy document with a top-level "rules" list
+func ParsePeerPolicy(content []byte) (*PeerPolicy, error) {
+	var document struct {
+		Rules []PeerPolicyRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("could not parse peer policy: %w", err)
+	}
+	return NewPeerPolicy(document.Rules)
+}
+
+// NewPeerPolicy validates rules so a typo is noticed at startup rather than as a refused call
+func NewPeerPolicy(rules []PeerPolicyRule) (*PeerPolicy, error) {
+	if len(rules) == 0 {
+		return nil, errors.New("peer policy has no rules")
+	}
+	for i, rule := range rules {
+		switch {
+		case rule.Identity == AnyIdentity:
+		case strings.HasPrefix(rule.Identity, IdentityCommonNamePrefix) && len(rule.Identity) > len(IdentityCommonNamePrefix):
+		case strings.HasPrefix(rule.Identity, IdentityURIPrefix) && len(rule.Identity) > len(IdentityURIPrefix):
+		default:
+			return nil, fmt.Errorf("peer policy rule %d: identity %q must be cn:<name>, uri:<uri> or *", i, rule.Identity)
+		}
+		if len(rule.Methods) == 0 {
+			return nil, fmt.Errorf("peer policy rule %d (%s): no methods", i, rule.Identity)
+		}
+		for _, method := range rule.Methods {
+			if method != AnyMethod && (!strings.HasPrefix(method, "/") || strings.Count(method, "/") != 2) {
+				return nil, fmt.Errorf("peer policy rule %d (%s): method %q must be /package.Service/Method, /package.Service/ or *", i, rule.Identity, method)
+			}
+		}
+	}
+	return &PeerPolicy{rules: rules}, nil
+}
+
+// Allows reports whether any rule matching id grants fullMethod
+func (p *PeerPolicy) Allows(id PeerIdentity, fullMethod string) bool {
+	for _, rule := range p.rules {
+		if rule.matchesIdentity(id) && rule.grants(fullMethod) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r PeerPolicyRule) matchesIdentity(id PeerIdentity) bool {
+	switch {
+	case r.Identity == AnyIdentity:
+		return true
+	case strings.HasPrefix(r.Identity, IdentityCommonNamePrefix):
+		return id.CommonName == strings.TrimPrefix(r.Identity, IdentityCommonNamePrefix)
+	case strings.HasPrefix(r.Identity, IdentityURIPrefix):
+		return slices.Contains(id.URIs, strings.TrimPrefix(r.Identity, IdentityURIPrefix))
+	}
+	return false
+}
+
+func (r PeerPolicyRule) grants(fullMethod string) bool {
+	for _, method := range r.Methods {
+		if method == AnyMethod || method == fullMethod {
+			return true
+		}
+		// "/package.Service/" grants every method of the service
+		if strings.HasSuffix(method, "/") && strings.HasPrefix(fullMethod, method) {
+			return true
+		}
+	}
+	return false
+}
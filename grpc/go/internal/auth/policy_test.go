@@ -0,0 +1,79 @@
+package auth
+
+import "testing"
+
+func TestPeerPolicyAllows(t *testing.T) {
+	// Given
+	policy, err := ParsePeerPolicy([]byte(`
+rules:
+  - identity: "cn:movie-admin"
+    methods: ["/movie.MovieAdmin/"]
+  - identity: "uri:spiffe://case-studies/importer"
+    methods: ["/movie.MovieAdmin/CreateMovie"]
+  - identity: "*"
+    methods: ["/movie.Getter/", "/grpc.health.v1.Health/Check"]
+`))
+	if err != nil {
+		t.Fatalf("could not parse policy: %v", err)
+	}
+	admin := PeerIdentity{CommonName: "movie-admin"}
+	importer := PeerIdentity{CommonName: "importer", URIs: []string{"spiffe://case-studies/importer"}}
+	client := PeerIdentity{CommonName: "grpc-client"}
+
+	tests := []struct {
+		name     string
+		identity PeerIdentity
+		method   string
+		expected bool
+	}{
+		{"admin writes", admin, "/movie.MovieAdmin/DeleteMovie", true},
+		{"admin reads through the wildcard rule", admin, "/movie.Getter/GetMovie", true},
+		{"importer creates by URI", importer, "/movie.MovieAdmin/CreateMovie", true},
+		{"importer deletes", importer, "/movie.MovieAdmin/DeleteMovie", false},
+		{"client reads", client, "/movie.Getter/GetMovies", true},
+		{"client health check", client, "/grpc.health.v1.Health/Check", true},
+		{"client health watch", client, "/grpc.health.v1.Health/Watch", false},
+		{"client writes", client, "/movie.MovieAdmin/CreateMovie", false},
+		{"service prefix does not match a longer service name", admin, "/movie.MovieAdminV2/CreateMovie", false},
+		{"common name given as a URI", PeerIdentity{URIs: []string{"movie-admin"}}, "/movie.MovieAdmin/CreateMovie", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			allowed := policy.Allows(tt.identity, tt.method)
+
+			// Then
+			if allowed != tt.expected {
+				t.Errorf("Given %s, When checking %s, Then expected allowed %v, got %v", tt.identity, tt.method, tt.expected, allowed)
+			}
+		})
+	}
+}
+
+func TestParsePeerPolicyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid YAML", "rules: ["},
+		{"no rules", "rules: []"},
+		{"bare identity", `rules: [{identity: movie-admin, methods: ["*"]}]`},
+		{"empty common name", `rules: [{identity: "cn:", methods: ["*"]}]`},
+		{"no methods", `rules: [{identity: "cn:movie-admin"}]`},
+		{"method without a service", `rules: [{identity: "cn:movie-admin", methods: ["CreateMovie"]}]`},
+		{"method with too many segments", `rules: [{identity: "cn:movie-admin", methods: ["/movie/MovieAdmin/CreateMovie"]}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			_, err := ParsePeerPolicy([]byte(tt.content))
+
+			// Then
+			if err == nil {
+				t.Errorf("Given %s, When parsing the peer policy, Then expected an error, got nil", tt.name)
+			}
+		})
+	}
+}
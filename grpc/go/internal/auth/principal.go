@@ -0,0 +1,30 @@
+package auth
+
+import "context"
+
+// Authentication methods recorded on a Principal
+const (
+	MethodAPIKey = "api-key"
+	MethodJWT    = "jwt"
+)
+
+// Principal is the identity a request authenticated as
+type Principal struct {
+	// Name is the API key name or the token subject
+	Name   string
+	Scopes []string
+	Method string
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated principal
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored by NewContext
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
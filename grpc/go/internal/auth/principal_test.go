@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPrincipalContext(t *testing.T) {
+	// Given
+	ctx := NewContext(context.Background(), Principal{Name: "test-key", Method: MethodAPIKey})
+
+	// When
+	principal, ok := FromContext(ctx)
+	_, emptyOK := FromContext(context.Background())
+
+	// Then
+	if !ok || principal.Name != "test-key" {
+		t.Errorf("Given a context with a principal, When reading it, Then expected test-key, got %+v (ok %v)", principal, ok)
+	}
+	if emptyOK {
+		t.Errorf("Given a context without a principal, When reading it, Then expected none")
+	}
+}
@@ -4,30 +4,194 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	DefaultHost           = "localhost"
-	DefaultPort           = 50051
-	DefaultName           = "world"
-	DefaultAssetsFilePath = "./assets"
-	DefaultEnvironment    = "development"
-	DefaultLogLevel       = "info"
+	DefaultHost            = "localhost"
+	DefaultPort            = 50051
+	DefaultName            = "world"
+	DefaultAssetsFilePath  = "./assets"
+	DefaultEnvironment     = "development"
+	DefaultLogLevel        = "info"
+	DefaultMovieRepository = "json"
+
+	DefaultMovieReloadInterval = 30 * time.Second
+
+	// DefaultMetricsPort serves /metrics; 0 disables it
+	DefaultMetricsPort = 9090
+	// DefaultProbePort of 0 serves /healthz and /readyz on the metrics port instead of their own
+	DefaultProbePort = 0
+
+	// DefaultShutdownDrainPeriod and DefaultShutdownTimeout fit in Kubernetes' default 30s grace period
+	DefaultShutdownDrainPeriod = 5 * time.Second
+	DefaultShutdownTimeout     = 20 * time.Second
+
+	DefaultTLSMode       = TLSModeMTLS
+	DefaultTLSMinVersion = "1.2"
+	// DefaultTLSReloadInterval is how often certificate files are checked for rotation
+	DefaultTLSReloadInterval = 30 * time.Second
+	// DefaultTLSExpiryWarning warns about certificates expiring within 30 days
+	DefaultTLSExpiryWarning = 30 * 24 * time.Hour
+
+	DefaultTracesExporter = "none"
+	DefaultOTLPEndpoint   = "localhost:4317"
+
+	DefaultAuthMode     = AuthModeAPIKey
+	DefaultJWKSFileName = "jwks.json"
+)
+
+// Authentication modes: which credentials the server accepts
+const (
+	AuthModeAPIKey = "api-key"
+	AuthModeJWT    = "jwt"
+	AuthModeBoth   = "both"
+)
+
+// TLS modes: how connections between the movie server and its clients are secured
+const (
+	// TLSModeMTLS verifies certificates on both sides
+	TLSModeMTLS = "mtls"
+	// TLSModeTLS verifies only the server certificate
+	TLSModeTLS = "tls"
+	// TLSModeInsecure sends plaintext, for local debugging behind a trusted proxy
+	TLSModeInsecure = "insecure"
+)
+
+// API key scopes; keys configured without scopes may only read
+const (
+	ScopeMoviesRead  = "movies:read"
+	ScopeMoviesWrite = "movies:write"
+	// ScopeServerAdmin allows operating the server itself, such as changing its log level
+	ScopeServerAdmin = "server:admin"
 )
 
 type APIKeyConfig struct {
 	Name string `yaml:"name"`
-	Key  string `yaml:"key"`
+	// Key is the plaintext key; prefer Hash so the file does not hold usable credentials
+	Key string `yaml:"key,omitempty"`
+	// Hash is the salted, peppered hash of the key as produced by apikey.Hash
+	Hash   string   `yaml:"hash,omitempty"`
+	Scopes []string `yaml:"scopes,omitempty"`
+	// NotBefore and ExpiresAt bound when the key is accepted; zero values leave that side open
+	NotBefore time.Time `yaml:"not_before,omitempty"`
+	ExpiresAt time.Time `yaml:"expires_at,omitempty"`
+	Disabled  bool      `yaml:"disabled,omitempty"`
+	// RateLimit is the sustained requests per second; zero disables rate limiting
+	RateLimit float64 `yaml:"rate_limit,omitempty"`
+	// RateBurst is how many requests may arrive at once; defaults to RateLimit rounded up
+	RateBurst int `yaml:"rate_burst,omitempty"`
+	// DailyQuota is the number of requests allowed per UTC day; zero means unlimited
+	DailyQuota int64 `yaml:"daily_quota,omitempty"`
+}
+
+// GrantedScopes returns the configured scopes, or read-only access when none are set
+func (k APIKeyConfig) GrantedScopes() []string {
+	if len(k.Scopes) == 0 {
+		return []string{ScopeMoviesRead}
+	}
+	return k.Scopes
+}
+
+// JWTConfig configures bearer token authentication
+type JWTConfig struct {
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	// JWKSFile is resolved against the assets file path unless absolute; defaults to jwks.json
+	JWKSFile string `yaml:"jwks_file,omitempty"`
+	// ScopeClaim names the claim holding scopes; defaults to "scope"
+	ScopeClaim string `yaml:"scope_claim,omitempty"`
+	// ScopeMapping translates claim values (e.g. roles) to server scopes
+	ScopeMapping map[string][]string `yaml:"scope_mapping,omitempty"`
+	ClockSkew    time.Duration       `yaml:"clock_skew,omitempty"`
+}
+
+// LoggingConfig is the logging section of api-config.yaml; the server re-reads it on SIGHUP
+type LoggingConfig struct {
+	// Level applies unless LOG_LEVEL or -log-level is set, and replaces the current level on reload
+	Level string `yaml:"level,omitempty"`
+	// Sampling logs only 1 in N successes of each named operation; errors are always logged
+	Sampling map[string]int `yaml:"sampling,omitempty"`
+}
+
+// TracingConfig selects where spans are exported, using the standard OpenTelemetry variables
+type TracingConfig struct {
+	// TracesExporter is none, otlp or stdout
+	TracesExporter string
+	// OTLPEndpoint is the collector's host:port for the otlp exporter
+	OTLPEndpoint string
+}
+
+// TLSConfig secures connections between the movie server and its clients. The server reads it
+// from the tls section of api-config.yaml and clients from the client_tls section, then TLS_*
+// variables and flags.
+type TLSConfig struct {
+	// Mode is mtls, tls or insecure
+	Mode string `yaml:"mode,omitempty"`
+	// CertFile, KeyFile and CAFile are resolved against the assets file path unless absolute; see Paths
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+	CAFile   string `yaml:"ca_file,omitempty"`
+	// ServerName is the name clients verify the server certificate against; empty uses the host dialled
+	ServerName string `yaml:"server_name,omitempty"`
+	// MinVersion is 1.2 or 1.3
+	MinVersion string `yaml:"min_version,omitempty"`
+	// CipherSuites restricts TLS 1.2 to the named suites; empty keeps Go's defaults
+	CipherSuites []string `yaml:"cipher_suites,omitempty"`
+	// ReloadInterval is the least time between checks of the certificate files for changes
+	ReloadInterval time.Duration `yaml:"reload_interval,omitempty"`
+	// ExpiryWarning logs a warning for loaded certificates expiring within it
+	ExpiryWarning time.Duration `yaml:"expiry_warning,omitempty"`
+}
+
+// Paths returns the certificate, key and CA files. Unset files default to <name>-public.key,
+// <name>-private.key and ca-public.key in the tls directory under assetsFilePath.
+func (c TLSConfig) Paths(assetsFilePath, name string) (certFile, keyFile, caFile string) {
+	resolve := func(path, defaultName string) string {
+		if path == "" {
+			return filepath.Join(assetsFilePath, "tls", defaultName)
+		}
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(assetsFilePath, path)
+	}
+	return resolve(c.CertFile, name+"-public.key"), resolve(c.KeyFile, name+"-private.key"), resolve(c.CAFile, "ca-public.key")
 }
 
 type ServerConfig struct {
-	Port           int
+	Port        int
+	MetricsPort int
+	// ProbePort serves /healthz and /readyz on their own listener; 0 leaves them on the metrics port
+	ProbePort      int
 	AssetsFilePath string
 	APIKeys        []APIKeyConfig
-	LogLevel       string
-	Environment    string
+	// AuthMode selects API keys, JWT bearer tokens or both
+	AuthMode string
+	JWT      JWTConfig
+	LogLevel string
+	// LogRedactKeys are log fields masked in addition to the logger's defaults
+	LogRedactKeys   []string
+	Logging         LoggingConfig
+	Environment     string
+	PageTokenSecret string
+	// MTLSPolicyFile maps client certificate identities to the RPCs they may call; empty disables the check
+	MTLSPolicyFile string
+	// APIKeyPepper is mixed into API key hashes and kept out of api-config.yaml
+	APIKeyPepper      string
+	MovieRepository   string
+	MovieDatabasePath string
+	Tracing           TracingConfig
+	TLS               TLSConfig
+	// MovieReloadInterval is how often the JSON repository checks its file for changes; zero disables reloading
+	MovieReloadInterval time.Duration
+	// ShutdownDrainPeriod is how long the server keeps serving after reporting NOT_SERVING on SIGTERM
+	ShutdownDrainPeriod time.Duration
+	// ShutdownTimeout is how long running calls may take after the drain before they are cut off
+	ShutdownTimeout time.Duration
 }
 
 type ClientConfig struct {
@@ -37,9 +201,10 @@ type ClientConfig struct {
 
 type HelloWorldClientConfig struct {
 	ClientConfig
-	Name        string
-	Environment string
-	LogLevel    string
+	Name          string
+	Environment   string
+	LogLevel      string
+	LogRedactKeys []string
 }
 
 type MovieClientConfig struct {
@@ -47,7 +212,10 @@ type MovieClientConfig struct {
 	AssetsFilePath string
 	APIKey         string
 	LogLevel       string
+	LogRedactKeys  []string
 	Environment    string
+	Tracing        TracingConfig
+	TLS            TLSConfig
 }
 
 func validateLogLevel(level string) string {
@@ -77,9 +245,17 @@ func validateEnvironment(env string) string {
 
 func LoadServerConfig() *ServerConfig {
 	config := &ServerConfig{
-		Port:           DefaultPort,
-		AssetsFilePath: DefaultAssetsFilePath,
-		Environment:    DefaultEnvironment,
+		Port:                DefaultPort,
+		MetricsPort:         DefaultMetricsPort,
+		ProbePort:           DefaultProbePort,
+		AssetsFilePath:      DefaultAssetsFilePath,
+		Environment:         DefaultEnvironment,
+		MovieRepository:     DefaultMovieRepository,
+		MovieReloadInterval: DefaultMovieReloadInterval,
+		AuthMode:            DefaultAuthMode,
+		ShutdownDrainPeriod: DefaultShutdownDrainPeriod,
+		ShutdownTimeout:     DefaultShutdownTimeout,
+		TLS:                 DefaultTLSConfig(),
 	}
 
 	if env := os.Getenv("ENVIRONMENT"); env != "" {
@@ -94,6 +270,18 @@ func LoadServerConfig() *ServerConfig {
 		}
 	}
 
+	if envMetricsPortStr := os.Getenv("METRICS_PORT"); envMetricsPortStr != "" {
+		if p, err := strconv.Atoi(envMetricsPortStr); err == nil {
+			config.MetricsPort = p
+		}
+	}
+
+	if envProbePortStr := os.Getenv("PROBE_PORT"); envProbePortStr != "" {
+		if p, err := strconv.Atoi(envProbePortStr); err == nil {
+			config.ProbePort = p
+		}
+	}
+
 	if assetsFilePath := os.Getenv("ASSETS_FILE_PATH"); assetsFilePath != "" {
 		config.AssetsFilePath = assetsFilePath
 	}
@@ -102,6 +290,52 @@ func LoadServerConfig() *ServerConfig {
 	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
 		config.LogLevel = validateLogLevel(logLevel)
 	}
+	config.LogRedactKeys = LoadLogRedactKeys()
+
+	if movieRepository := os.Getenv("MOVIE_REPOSITORY"); movieRepository != "" {
+		config.MovieRepository = movieRepository
+	}
+
+	if movieDatabasePath := os.Getenv("MOVIE_DATABASE_PATH"); movieDatabasePath != "" {
+		config.MovieDatabasePath = movieDatabasePath
+	}
+
+	if movieReloadInterval := os.Getenv("MOVIE_RELOAD_INTERVAL"); movieReloadInterval != "" {
+		if d, err := time.ParseDuration(movieReloadInterval); err == nil {
+			config.MovieReloadInterval = d
+		}
+	}
+
+	if drainPeriod := os.Getenv("SHUTDOWN_DRAIN_PERIOD"); drainPeriod != "" {
+		if d, err := time.ParseDuration(drainPeriod); err == nil {
+			config.ShutdownDrainPeriod = d
+		}
+	}
+
+	if shutdownTimeout := os.Getenv("SHUTDOWN_TIMEOUT"); shutdownTimeout != "" {
+		if d, err := time.ParseDuration(shutdownTimeout); err == nil {
+			config.ShutdownTimeout = d
+		}
+	}
+
+	// Shared secret so page tokens stay valid across restarts and replicas
+	if pageTokenSecret := os.Getenv("PAGE_TOKEN_SECRET"); pageTokenSecret != "" {
+		config.PageTokenSecret = pageTokenSecret
+	}
+
+	config.Tracing = LoadTracingConfig()
+
+	if authMode := os.Getenv("AUTH_MODE"); authMode != "" {
+		config.AuthMode = authMode
+	}
+
+	if mtlsPolicyFile := os.Getenv("MTLS_POLICY_FILE"); mtlsPolicyFile != "" {
+		config.MTLSPolicyFile = mtlsPolicyFile
+	}
+
+	if apiKeyPepper := os.Getenv("API_KEY_PEPPER"); apiKeyPepper != "" {
+		config.APIKeyPepper = apiKeyPepper
+	}
 
 	// Load API keys from YAML file
 	apiConfigPath := filepath.Join(config.AssetsFilePath, "api-config.yaml")
@@ -109,11 +343,22 @@ func LoadServerConfig() *ServerConfig {
 		defer f.Close()
 		var data struct {
 			APIKeys []APIKeyConfig `yaml:"api_keys"`
+			JWT     JWTConfig      `yaml:"jwt"`
+			Logging LoggingConfig  `yaml:"logging"`
+			TLS     TLSConfig      `yaml:"tls"`
 		}
+		data.TLS = config.TLS
 		if err := yaml.NewDecoder(f).Decode(&data); err == nil {
 			config.APIKeys = data.APIKeys
+			config.JWT = data.JWT
+			config.Logging = data.Logging
+			config.TLS = data.TLS
+			if data.Logging.Level != "" && os.Getenv("LOG_LEVEL") == "" {
+				config.LogLevel = validateLogLevel(data.Logging.Level)
+			}
 		}
 	}
+	loadTLSConfigFromEnv(&config.TLS)
 
 	return config
 }
@@ -155,6 +400,7 @@ func LoadHelloWorldClientConfig() *HelloWorldClientConfig {
 	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
 		config.LogLevel = validateLogLevel(logLevel)
 	}
+	config.LogRedactKeys = LoadLogRedactKeys()
 
 	return config
 }
@@ -185,14 +431,151 @@ func LoadMovieClientConfig() *MovieClientConfig {
 		config.APIKey = envKey
 	}
 
+	config.Tracing = LoadTracingConfig()
+	config.TLS = LoadClientTLSConfig(config.AssetsFilePath)
+
 	// Allow explicit LOG_LEVEL override
 	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
 		config.LogLevel = validateLogLevel(logLevel)
 	}
+	config.LogRedactKeys = LoadLogRedactKeys()
 
 	return config
 }
 
+// LoadLoggingConfig re-reads the logging section of api-config.yaml under assetsFilePath
+func LoadLoggingConfig(assetsFilePath string) (LoggingConfig, error) {
+	content, err := os.ReadFile(filepath.Join(assetsFilePath, "api-config.yaml"))
+	if err != nil {
+		return LoggingConfig{}, err
+	}
+	var data struct {
+		Logging LoggingConfig `yaml:"logging"`
+	}
+	if err := yaml.Unmarshal(content, &data); err != nil {
+		return LoggingConfig{}, err
+	}
+	return data.Logging, nil
+}
+
+// LoadAPIKeys re-reads the api_keys section of api-config.yaml under assetsFilePath
+func LoadAPIKeys(assetsFilePath string) ([]APIKeyConfig, error) {
+	content, err := os.ReadFile(filepath.Join(assetsFilePath, "api-config.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	var data struct {
+		APIKeys []APIKeyConfig `yaml:"api_keys"`
+	}
+	if err := yaml.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+	return data.APIKeys, nil
+}
+
+// LoadLogRedactKeys reads the comma-separated LOG_REDACT_KEYS
+func LoadLogRedactKeys() []string {
+	return SplitList(os.Getenv("LOG_REDACT_KEYS"))
+}
+
+// SplitList splits a comma-separated value, dropping blank entries
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// LoadTracingConfig reads OTEL_TRACES_EXPORTER and OTEL_EXPORTER_OTLP_ENDPOINT
+func LoadTracingConfig() TracingConfig {
+	config := TracingConfig{
+		TracesExporter: DefaultTracesExporter,
+		OTLPEndpoint:   DefaultOTLPEndpoint,
+	}
+
+	if exporter := os.Getenv("OTEL_TRACES_EXPORTER"); exporter != "" {
+		config.TracesExporter = exporter
+	}
+
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		config.OTLPEndpoint = endpoint
+	}
+
+	return config
+}
+
+// DefaultTLSConfig is mutual TLS over TLS 1.2 or later with the certificates under the assets file path
+func DefaultTLSConfig() TLSConfig {
+	return TLSConfig{
+		Mode:           DefaultTLSMode,
+		MinVersion:     DefaultTLSMinVersion,
+		ReloadInterval: DefaultTLSReloadInterval,
+		ExpiryWarning:  DefaultTLSExpiryWarning,
+	}
+}
+
+// LoadClientTLSConfig reads the client_tls section of api-config.yaml under assetsFilePath, then the
+// TLS_* variables, over DefaultTLSConfig. A missing or unreadable file leaves the defaults.
+func LoadClientTLSConfig(assetsFilePath string) TLSConfig {
+	config := DefaultTLSConfig()
+	if content, err := os.ReadFile(filepath.Join(assetsFilePath, "api-config.yaml")); err == nil {
+		var data struct {
+			TLS TLSConfig `yaml:"client_tls"`
+		}
+		data.TLS = config
+		if err := yaml.Unmarshal(content, &data); err == nil {
+			config = data.TLS
+		}
+	}
+	loadTLSConfigFromEnv(&config)
+	return config
+}
+
+func loadTLSConfigFromEnv(config *TLSConfig) {
+	if mode := os.Getenv("TLS_MODE"); mode != "" {
+		config.Mode = mode
+	}
+
+	if certFile := os.Getenv("TLS_CERT_FILE"); certFile != "" {
+		config.CertFile = certFile
+	}
+
+	if keyFile := os.Getenv("TLS_KEY_FILE"); keyFile != "" {
+		config.KeyFile = keyFile
+	}
+
+	if caFile := os.Getenv("TLS_CA_FILE"); caFile != "" {
+		config.CAFile = caFile
+	}
+
+	if serverName := os.Getenv("TLS_SERVER_NAME"); serverName != "" {
+		config.ServerName = serverName
+	}
+
+	if minVersion := os.Getenv("TLS_MIN_VERSION"); minVersion != "" {
+		config.MinVersion = minVersion
+	}
+
+	if cipherSuites := SplitList(os.Getenv("TLS_CIPHER_SUITES")); len(cipherSuites) > 0 {
+		config.CipherSuites = cipherSuites
+	}
+
+	if reloadInterval := os.Getenv("TLS_RELOAD_INTERVAL"); reloadInterval != "" {
+		if d, err := time.ParseDuration(reloadInterval); err == nil {
+			config.ReloadInterval = d
+		}
+	}
+
+	if expiryWarning := os.Getenv("TLS_EXPIRY_WARNING"); expiryWarning != "" {
+		if d, err := time.ParseDuration(expiryWarning); err == nil {
+			config.ExpiryWarning = d
+		}
+	}
+}
+
 func loadClientConfigFromEnv(config *ClientConfig) {
 	if envHost := os.Getenv("SERVER_HOST"); envHost != "" {
 		config.Host = envHost
@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"testing"
+	"time"
 )
 
 func withEnvVars(t *testing.T, envVars map[string]string, testFn func()) {
@@ -119,25 +122,65 @@ func TestLoadServerConfig(t *testing.T) {
 			name:    "default values",
 			envVars: map[string]string{},
 			expectedConfig: &ServerConfig{
-				Port:           DefaultPort,
-				AssetsFilePath: DefaultAssetsFilePath,
-				Environment:    DefaultEnvironment,
-				LogLevel:       "debug",
+				Port:                DefaultPort,
+				MetricsPort:         DefaultMetricsPort,
+				AssetsFilePath:      DefaultAssetsFilePath,
+				Environment:         DefaultEnvironment,
+				LogLevel:            "debug",
+				MovieRepository:     DefaultMovieRepository,
+				MovieReloadInterval: DefaultMovieReloadInterval,
+				ShutdownDrainPeriod: DefaultShutdownDrainPeriod,
+				ShutdownTimeout:     DefaultShutdownTimeout,
 			},
 		},
 		{
 			name: "custom values",
 			envVars: map[string]string{
-				"ENVIRONMENT":      "production",
-				"SERVER_PORT":      "8080",
-				"ASSETS_FILE_PATH": "/custom/assets",
-				"LOG_LEVEL":        "error",
+				"ENVIRONMENT":           "production",
+				"SERVER_PORT":           "8080",
+				"METRICS_PORT":          "0",
+				"PROBE_PORT":            "8086",
+				"ASSETS_FILE_PATH":      "/custom/assets",
+				"LOG_LEVEL":             "error",
+				"PAGE_TOKEN_SECRET":     "page-secret",
+				"MOVIE_REPOSITORY":      "sqlite",
+				"MOVIE_DATABASE_PATH":   "/custom/movies.db",
+				"MOVIE_RELOAD_INTERVAL": "5s",
+				"MTLS_POLICY_FILE":      "mtls-policy.yaml",
+				"SHUTDOWN_DRAIN_PERIOD": "0s",
+				"SHUTDOWN_TIMEOUT":      "1m",
 			},
 			expectedConfig: &ServerConfig{
-				Port:           8080,
-				AssetsFilePath: "/custom/assets",
-				Environment:    "production",
-				LogLevel:       "error",
+				Port:                8080,
+				MetricsPort:         0,
+				ProbePort:           8086,
+				AssetsFilePath:      "/custom/assets",
+				Environment:         "production",
+				LogLevel:            "error",
+				PageTokenSecret:     "page-secret",
+				MovieRepository:     "sqlite",
+				MovieDatabasePath:   "/custom/movies.db",
+				MovieReloadInterval: 5 * time.Second,
+				MTLSPolicyFile:      "mtls-policy.yaml",
+				ShutdownDrainPeriod: 0,
+				ShutdownTimeout:     time.Minute,
+			},
+		},
+		{
+			name: "invalid reload interval keeps default",
+			envVars: map[string]string{
+				"MOVIE_RELOAD_INTERVAL": "often",
+			},
+			expectedConfig: &ServerConfig{
+				Port:                DefaultPort,
+				MetricsPort:         DefaultMetricsPort,
+				AssetsFilePath:      DefaultAssetsFilePath,
+				Environment:         DefaultEnvironment,
+				LogLevel:            "debug",
+				MovieRepository:     DefaultMovieRepository,
+				MovieReloadInterval: DefaultMovieReloadInterval,
+				ShutdownDrainPeriod: DefaultShutdownDrainPeriod,
+				ShutdownTimeout:     DefaultShutdownTimeout,
 			},
 		},
 	}
@@ -152,6 +195,12 @@ func TestLoadServerConfig(t *testing.T) {
 				if config.Port != tt.expectedConfig.Port {
 					t.Errorf("Given envVars %v, When loading server config, Then expected Port %d, got %d", tt.envVars, tt.expectedConfig.Port, config.Port)
 				}
+				if config.MetricsPort != tt.expectedConfig.MetricsPort {
+					t.Errorf("Given envVars %v, When loading server config, Then expected MetricsPort %d, got %d", tt.envVars, tt.expectedConfig.MetricsPort, config.MetricsPort)
+				}
+				if config.ProbePort != tt.expectedConfig.ProbePort {
+					t.Errorf("Given envVars %v, When loading server config, Then expected ProbePort %d, got %d", tt.envVars, tt.expectedConfig.ProbePort, config.ProbePort)
+				}
 				if config.AssetsFilePath != tt.expectedConfig.AssetsFilePath {
 					t.Errorf("Given envVars %v, When loading server config, Then expected AssetsFilePath %q, got %q", tt.envVars, tt.expectedConfig.AssetsFilePath, config.AssetsFilePath)
 				}
@@ -161,6 +210,24 @@ func TestLoadServerConfig(t *testing.T) {
 				if config.LogLevel != tt.expectedConfig.LogLevel {
 					t.Errorf("Given envVars %v, When loading server config, Then expected LogLevel %q, got %q", tt.envVars, tt.expectedConfig.LogLevel, config.LogLevel)
 				}
+				if config.PageTokenSecret != tt.expectedConfig.PageTokenSecret {
+					t.Errorf("Given envVars %v, When loading server config, Then expected PageTokenSecret %q, got %q", tt.envVars, tt.expectedConfig.PageTokenSecret, config.PageTokenSecret)
+				}
+				if config.MovieRepository != tt.expectedConfig.MovieRepository {
+					t.Errorf("Given envVars %v, When loading server config, Then expected MovieRepository %q, got %q", tt.envVars, tt.expectedConfig.MovieRepository, config.MovieRepository)
+				}
+				if config.MovieDatabasePath != tt.expectedConfig.MovieDatabasePath {
+					t.Errorf("Given envVars %v, When loading server config, Then expected MovieDatabasePath %q, got %q", tt.envVars, tt.expectedConfig.MovieDatabasePath, config.MovieDatabasePath)
+				}
+				if config.MovieReloadInterval != tt.expectedConfig.MovieReloadInterval {
+					t.Errorf("Given envVars %v, When loading server config, Then expected MovieReloadInterval %v, got %v", tt.envVars, tt.expectedConfig.MovieReloadInterval, config.MovieReloadInterval)
+				}
+				if config.MTLSPolicyFile != tt.expectedConfig.MTLSPolicyFile {
+					t.Errorf("Given envVars %v, When loading server config, Then expected MTLSPolicyFile %q, got %q", tt.envVars, tt.expectedConfig.MTLSPolicyFile, config.MTLSPolicyFile)
+				}
+				if config.ShutdownDrainPeriod != tt.expectedConfig.ShutdownDrainPeriod || config.ShutdownTimeout != tt.expectedConfig.ShutdownTimeout {
+					t.Errorf("Given envVars %v, When loading server config, Then expected shutdown drain %v and timeout %v, got %v and %v", tt.envVars, tt.expectedConfig.ShutdownDrainPeriod, tt.expectedConfig.ShutdownTimeout, config.ShutdownDrainPeriod, config.ShutdownTimeout)
+				}
 			})
 		})
 	}
@@ -217,9 +284,16 @@ func TestLoadServerConfigWithAPIKeys(t *testing.T) {
 	apiConfigPath := filepath.Join(tempDir, "api-config.yaml")
 	apiConfigContent := `api_keys:
   - name: "test-key-1"
-    key: "key-123"
+    hash: "sha256:00ff:abcd"
+    not_before: 2025-01-01T00:00:00Z
+    expires_at: 2026-01-01T00:00:00Z
+    disabled: true
   - name: "test-key-2"
-    key: "key-456"`
+    key: "key-456"
+    scopes: ["movies:read", "movies:write"]
+    rate_limit: 5.5
+    rate_burst: 10
+    daily_quota: 1000`
 
 	err := os.WriteFile(apiConfigPath, []byte(apiConfigContent), 0644)
 	if err != nil {
@@ -232,11 +306,112 @@ func TestLoadServerConfigWithAPIKeys(t *testing.T) {
 
 		// Then
 		if len(config.APIKeys) != 2 {
-			t.Errorf("Given API config file, When loading server config, Then expected 2 API keys, got %d", len(config.APIKeys))
+			t.Fatalf("Given API config file, When loading server config, Then expected 2 API keys, got %d", len(config.APIKeys))
+		}
+		if len(config.APIKeys[1].Scopes) != 2 {
+			t.Errorf("Given API config file, When loading server config, Then expected 2 scopes on test-key-2, got %v", config.APIKeys[1].Scopes)
+		}
+		if k := config.APIKeys[1]; k.RateLimit != 5.5 || k.RateBurst != 10 || k.DailyQuota != 1000 {
+			t.Errorf("Given API config file, When loading server config, Then expected limits 5.5/10/1000 on test-key-2, got %v/%d/%d", k.RateLimit, k.RateBurst, k.DailyQuota)
+		}
+		if k := config.APIKeys[0]; k.RateLimit != 0 || k.DailyQuota != 0 {
+			t.Errorf("Given API config file, When loading server config, Then expected test-key-1 to be unlimited, got %v/%d", k.RateLimit, k.DailyQuota)
+		}
+		if k := config.APIKeys[0]; k.Hash != "sha256:00ff:abcd" || k.Key != "" || !k.Disabled {
+			t.Errorf("Given API config file, When loading server config, Then expected a disabled hashed test-key-1, got %+v", k)
+		}
+		if k := config.APIKeys[0]; !k.NotBefore.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) || !k.ExpiresAt.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Given API config file, When loading server config, Then expected the validity window of test-key-1, got %v to %v", k.NotBefore, k.ExpiresAt)
 		}
 	})
 }
 
+func TestLoadServerConfigWithLogging(t *testing.T) {
+	// Given
+	tempDir := t.TempDir()
+	apiConfigContent := `logging:
+  level: debug
+  sampling:
+    movie-filter: 10`
+	if err := os.WriteFile(filepath.Join(tempDir, "api-config.yaml"), []byte(apiConfigContent), 0644); err != nil {
+		t.Fatalf("Failed to create test API config file: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		envVars       map[string]string
+		expectedLevel string
+	}{
+		{"file level", map[string]string{"ASSETS_FILE_PATH": tempDir}, "debug"},
+		{"LOG_LEVEL wins over the file", map[string]string{"ASSETS_FILE_PATH": tempDir, "LOG_LEVEL": "warn"}, "warn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withEnvVars(t, tt.envVars, func() {
+				// When
+				config := LoadServerConfig()
+
+				// Then
+				if config.LogLevel != tt.expectedLevel {
+					t.Errorf("Given envVars %v, When loading server config, Then expected log level %s, got %s", tt.envVars, tt.expectedLevel, config.LogLevel)
+				}
+				if config.Logging.Sampling["movie-filter"] != 10 {
+					t.Errorf("Given a logging section, When loading server config, Then expected movie-filter sampled 1 in 10, got %v", config.Logging.Sampling)
+				}
+			})
+		})
+	}
+
+	// When
+	logging, err := LoadLoggingConfig(tempDir)
+
+	// Then
+	if err != nil || logging.Level != "debug" || logging.Sampling["movie-filter"] != 10 {
+		t.Errorf("Given a logging section, When reloading it, Then expected level debug and movie-filter 1 in 10, got %+v, %v", logging, err)
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	// Given
+	tempDir := t.TempDir()
+	content := "api_keys:\n  - name: partner\n    hash: sha256:c2FsdA:ZGlnZXN0\n    disabled: true\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "api-config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write api-config.yaml: %v", err)
+	}
+
+	// When
+	keys, err := LoadAPIKeys(tempDir)
+
+	// Then
+	if err != nil || len(keys) != 1 || keys[0].Name != "partner" || !keys[0].Disabled {
+		t.Errorf("Given a revoked key, When reloading the API keys, Then expected partner disabled, got %+v, %v", keys, err)
+	}
+}
+
+func TestAPIKeyConfigGrantedScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      APIKeyConfig
+		expected []string
+	}{
+		{"no scopes defaults to read", APIKeyConfig{Name: "reader"}, []string{ScopeMoviesRead}},
+		{"configured scopes", APIKeyConfig{Name: "editor", Scopes: []string{ScopeMoviesRead, ScopeMoviesWrite}}, []string{ScopeMoviesRead, ScopeMoviesWrite}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			scopes := tt.key.GrantedScopes()
+
+			// Then
+			if !slices.Equal(scopes, tt.expected) {
+				t.Errorf("Given key %q, When getting granted scopes, Then expected %v, got %v", tt.key.Name, tt.expected, scopes)
+			}
+		})
+	}
+}
+
 func TestLoadMovieClientConfig(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -309,3 +484,231 @@ func TestLoadMovieClientConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadServerConfigWithJWT(t *testing.T) {
+	// Given
+	tempDir := t.TempDir()
+	apiConfigContent := `api_keys: []
+jwt:
+  issuer: https://auth.example.com
+  audience: movie-api
+  scope_claim: roles
+  scope_mapping:
+    movie-editor: ["movies:read", "movies:write"]
+  clock_skew: 30s`
+	if err := os.WriteFile(filepath.Join(tempDir, "api-config.yaml"), []byte(apiConfigContent), 0644); err != nil {
+		t.Fatalf("Failed to create test API config file: %v", err)
+	}
+
+	withEnvVars(t, map[string]string{"ASSETS_FILE_PATH": tempDir, "AUTH_MODE": AuthModeBoth}, func() {
+		// When
+		config := LoadServerConfig()
+
+		// Then
+		if config.AuthMode != AuthModeBoth {
+			t.Errorf("Given AUTH_MODE=both, When loading server config, Then expected AuthMode both, got %q", config.AuthMode)
+		}
+		jwt := config.JWT
+		if jwt.Issuer != "https://auth.example.com" || jwt.Audience != "movie-api" || jwt.ScopeClaim != "roles" || jwt.ClockSkew != 30*time.Second {
+			t.Errorf("Given a jwt section, When loading server config, Then expected it to be decoded, got %+v", jwt)
+		}
+		if len(jwt.ScopeMapping["movie-editor"]) != 2 {
+			t.Errorf("Given a scope mapping, When loading server config, Then expected two scopes for movie-editor, got %v", jwt.ScopeMapping)
+		}
+	})
+}
+
+func TestLoadLogRedactKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected []string
+	}{
+		{"unset", map[string]string{}, nil},
+		{"comma-separated with spaces", map[string]string{"LOG_REDACT_KEYS": " password, ,session_token "}, []string{"password", "session_token"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withEnvVars(t, tt.envVars, func() {
+				// When
+				keys := LoadLogRedactKeys()
+
+				// Then
+				if !slices.Equal(keys, tt.expected) {
+					t.Errorf("Given envVars %v, When loading log redact keys, Then expected %v, got %v", tt.envVars, tt.expected, keys)
+				}
+			})
+		})
+	}
+}
+
+func TestLoadClientTLSConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected TLSConfig
+	}{
+		{"unset", map[string]string{}, DefaultTLSConfig()},
+		{
+			"custom values",
+			map[string]string{
+				"TLS_MODE":            "tls",
+				"TLS_CERT_FILE":       "certs/client.pem",
+				"TLS_KEY_FILE":        "certs/client-key.pem",
+				"TLS_CA_FILE":         "/etc/ssl/ca.pem",
+				"TLS_SERVER_NAME":     "grpc-movie-server",
+				"TLS_MIN_VERSION":     "1.3",
+				"TLS_CIPHER_SUITES":   "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+				"TLS_RELOAD_INTERVAL": "1m",
+				"TLS_EXPIRY_WARNING":  "168h",
+			},
+			TLSConfig{
+				Mode:           TLSModeTLS,
+				CertFile:       "certs/client.pem",
+				KeyFile:        "certs/client-key.pem",
+				CAFile:         "/etc/ssl/ca.pem",
+				ServerName:     "grpc-movie-server",
+				MinVersion:     "1.3",
+				CipherSuites:   []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+				ReloadInterval: time.Minute,
+				ExpiryWarning:  7 * 24 * time.Hour,
+			},
+		},
+		{"invalid durations keep defaults", map[string]string{"TLS_RELOAD_INTERVAL": "often", "TLS_EXPIRY_WARNING": "soon"}, DefaultTLSConfig()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withEnvVars(t, tt.envVars, func() {
+				// When
+				config := LoadClientTLSConfig(t.TempDir())
+
+				// Then
+				if !reflect.DeepEqual(config, tt.expected) {
+					t.Errorf("Given envVars %v, When loading TLS config, Then expected %+v, got %+v", tt.envVars, tt.expected, config)
+				}
+			})
+		})
+	}
+}
+
+func TestLoadClientTLSConfigFromFile(t *testing.T) {
+	// Given
+	tempDir := t.TempDir()
+	apiConfigContent := `tls:
+  mode: insecure
+  cert_file: certs/server.pem
+client_tls:
+  mode: tls
+  ca_file: certs/ca.pem
+  server_name: grpc-movie-server
+  min_version: "1.3"`
+	if err := os.WriteFile(filepath.Join(tempDir, "api-config.yaml"), []byte(apiConfigContent), 0644); err != nil {
+		t.Fatalf("Failed to create test API config file: %v", err)
+	}
+
+	tests := []struct {
+		name               string
+		envVars            map[string]string
+		expectedMode       string
+		expectedServerName string
+	}{
+		{"file values", map[string]string{}, TLSModeTLS, "grpc-movie-server"},
+		{"variables win over the file", map[string]string{"TLS_MODE": "mtls", "TLS_SERVER_NAME": "localhost"}, TLSModeMTLS, "localhost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withEnvVars(t, tt.envVars, func() {
+				// When
+				config := LoadClientTLSConfig(tempDir)
+
+				// Then
+				if config.Mode != tt.expectedMode || config.ServerName != tt.expectedServerName {
+					t.Errorf("Given envVars %v, When loading client TLS config, Then expected mode %s and server name %s, got %s and %s", tt.envVars, tt.expectedMode, tt.expectedServerName, config.Mode, config.ServerName)
+				}
+				if config.CAFile != "certs/ca.pem" || config.CertFile != "" || config.MinVersion != "1.3" {
+					t.Errorf("Given a client_tls section, When loading client TLS config, Then expected its values and not the server's tls section, got %+v", config)
+				}
+				if config.ReloadInterval != DefaultTLSReloadInterval {
+					t.Errorf("Given a client_tls section without reload_interval, When loading client TLS config, Then expected the default %v, got %v", DefaultTLSReloadInterval, config.ReloadInterval)
+				}
+			})
+		})
+	}
+}
+
+func TestLoadServerConfigWithTLS(t *testing.T) {
+	// Given
+	tempDir := t.TempDir()
+	apiConfigContent := `tls:
+  mode: tls
+  cert_file: certs/server.pem
+  min_version: "1.3"
+  reload_interval: 1m`
+	if err := os.WriteFile(filepath.Join(tempDir, "api-config.yaml"), []byte(apiConfigContent), 0644); err != nil {
+		t.Fatalf("Failed to create test API config file: %v", err)
+	}
+
+	tests := []struct {
+		name               string
+		envVars            map[string]string
+		expectedMode       string
+		expectedMinVersion string
+	}{
+		{"file values", map[string]string{"ASSETS_FILE_PATH": tempDir}, TLSModeTLS, "1.3"},
+		{"TLS_MODE wins over the file", map[string]string{"ASSETS_FILE_PATH": tempDir, "TLS_MODE": "mtls"}, TLSModeMTLS, "1.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withEnvVars(t, tt.envVars, func() {
+				// When
+				config := LoadServerConfig()
+
+				// Then
+				if config.TLS.Mode != tt.expectedMode || config.TLS.MinVersion != tt.expectedMinVersion {
+					t.Errorf("Given envVars %v, When loading server config, Then expected TLS mode %s and minimum version %s, got %s and %s", tt.envVars, tt.expectedMode, tt.expectedMinVersion, config.TLS.Mode, config.TLS.MinVersion)
+				}
+				if config.TLS.CertFile != "certs/server.pem" || config.TLS.ReloadInterval != time.Minute {
+					t.Errorf("Given a tls section, When loading server config, Then expected its cert file and reload interval, got %+v", config.TLS)
+				}
+				if config.TLS.ExpiryWarning != DefaultTLSExpiryWarning {
+					t.Errorf("Given a tls section without expiry_warning, When loading server config, Then expected the default %v, got %v", DefaultTLSExpiryWarning, config.TLS.ExpiryWarning)
+				}
+			})
+		})
+	}
+}
+
+func TestTLSConfigPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   TLSConfig
+		expected [3]string
+	}{
+		{
+			name:     "unset files",
+			config:   TLSConfig{},
+			expected: [3]string{"assets/tls/server-public.key", "assets/tls/server-private.key", "assets/tls/ca-public.key"},
+		},
+		{
+			name:     "relative and absolute files",
+			config:   TLSConfig{CertFile: "certs/server.pem", KeyFile: "/etc/ssl/server-key.pem", CAFile: "/etc/ssl/ca.pem"},
+			expected: [3]string{"assets/certs/server.pem", "/etc/ssl/server-key.pem", "/etc/ssl/ca.pem"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			certFile, keyFile, caFile := tt.config.Paths("assets", "server")
+
+			// Then
+			if got := [3]string{certFile, keyFile, caFile}; got != tt.expected {
+				t.Errorf("Given %s, When resolving paths, Then expected %v, got %v", tt.name, tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package fulltext
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// prefixMatchWeight discounts terms that only share a prefix with the query term
+	prefixMatchWeight = 0.5
+	// maxPrefixExpansions bounds how many indexed terms a single query term can expand to
+	maxPrefixExpansions = 50
+	// minPrefixLength avoids expanding one-letter query terms to most of the vocabulary
+	minPrefixLength = 2
+	maxHighlights   = 5
+	maxSnippetRunes = 160
+
+	HighlightStart = "<em>"
+	HighlightEnd   = "</em>"
+)
+
+// Field is one searchable piece of text; the same name may repeat, e.g. once per cast member
+type Field struct {
+	Name   string
+	Text   string
+	Weight float64
+}
+
+type Document struct {
+	ID     string
+	Fields []Field
+}
+
+type Highlight struct {
+	Field   string
+	Snippet string
+}
+
+type Result struct {
+	ID         string
+	Score      float64
+	Highlights []Highlight
+}
+
+type posting struct {
+	doc   int
+	field int
+	count int
+}
+
+// Index is an immutable in-memory inverted index; build a new one to pick up document changes
+type Index struct {
+	documents []Document
+	postings  map[string][]posting
+	terms     []string
+}
+
+func NewIndex(documents []Document) *Index {
+	index := &Index{
+		documents: documents,
+		postings:  make(map[string][]posting),
+	}
+
+	for docIdx, doc := range documents {
+		for fieldIdx, field := range doc.Fields {
+			counts := make(map[string]int)
+			for _, token := range Tokenise(field.Text) {
+				counts[token.Term]++
+			}
+			for term, count := range counts {
+				index.postings[term] = append(index.postings[term], posting{doc: docIdx, field: fieldIdx, count: count})
+			}
+		}
+	}
+
+	index.terms = make([]string, 0, len(index.postings))
+	for term := range index.postings {
+		index.terms = append(index.terms, term)
+	}
+	sort.Strings(index.terms)
+
+	return index
+}
+
+// Search returns documents containing every query term (or a word it prefixes), best match first,
+// along with the total number of matches before limit was applied
+func (index *Index) Search(query string, limit int) ([]Result, int) {
+	queryTokens := Tokenise(query)
+	if len(queryTokens) == 0 || len(index.documents) == 0 {
+		return nil, 0
+	}
+
+	var docScores map[int]float64
+	matchedTerms := make(map[int]map[string]bool)
+
+	for _, queryToken := range queryTokens {
+		expanded := index.expand(queryToken.Term)
+		// Weight by how rare the query term is, not each expansion, so rare prefix matches cannot outrank exact ones
+		idf := math.Log(1 + float64(len(index.documents))/float64(max(index.documentFrequency(expanded), 1)))
+
+		termScores := make(map[int]float64)
+		for _, term := range expanded {
+			matchWeight := 1.0
+			if term != queryToken.Term {
+				matchWeight = prefixMatchWeight
+			}
+
+			perDoc := make(map[int]float64)
+			for _, p := range index.postings[term] {
+				field := index.documents[p.doc].Fields[p.field]
+				perDoc[p.doc] += field.Weight * (1 + math.Log(float64(p.count)))
+			}
+			for doc, fieldScore := range perDoc {
+				score := matchWeight * idf * fieldScore
+				if score > termScores[doc] {
+					termScores[doc] = score
+				}
+				if matchedTerms[doc] == nil {
+					matchedTerms[doc] = make(map[string]bool)
+				}
+				matchedTerms[doc][term] = true
+			}
+		}
+
+		// Every query term must match, so intersect with the documents matched so far
+		if docScores == nil {
+			docScores = termScores
+			continue
+		}
+		for doc := range docScores {
+			if termScore, ok := termScores[doc]; ok {
+				docScores[doc] += termScore
+			} else {
+				delete(docScores, doc)
+			}
+		}
+	}
+
+	results := make([]Result, 0, len(docScores))
+	for doc, score := range docScores {
+		results = append(results, Result{ID: index.documents[doc].ID, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+
+	total := len(results)
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	docByID := make(map[string]int, len(results))
+	for doc := range docScores {
+		docByID[index.documents[doc].ID] = doc
+	}
+	for i := range results {
+		doc := docByID[results[i].ID]
+		results[i].Highlights = index.highlight(index.documents[doc], matchedTerms[doc])
+	}
+
+	return results, total
+}
+
+// expand returns the indexed terms equal to or prefixed by the query term
+func (index *Index) expand(queryTerm string) []string {
+	if len(queryTerm) < minPrefixLength {
+		if _, ok := index.postings[queryTerm]; ok {
+			return []string{queryTerm}
+		}
+		return nil
+	}
+
+	var expanded []string
+	for i := sort.SearchStrings(index.terms, queryTerm); i < len(index.terms); i++ {
+		if !strings.HasPrefix(index.terms[i], queryTerm) || len(expanded) == maxPrefixExpansions {
+			break
+		}
+		expanded = append(expanded, index.terms[i])
+	}
+	return expanded
+}
+
+// documentFrequency counts the documents containing at least one of the terms
+func (index *Index) documentFrequency(terms []string) int {
+	docs := make(map[int]bool)
+	for _, term := range terms {
+		for _, p := range index.postings[term] {
+			docs[p.doc] = true
+		}
+	}
+	return len(docs)
+}
+
+func (index *Index) highlight(doc Document, terms map[string]bool) []Highlight {
+	var highlights []Highlight
+	for _, field := range doc.Fields {
+		if len(highlights) == maxHighlights {
+			break
+		}
+		if snippet, ok := Snippet(field.Text, terms); ok {
+			highlights = append(highlights, Highlight{Field: field.Name, Snippet: snippet})
+		}
+	}
+	return highlights
+}
+
+// Snippet HTML-escapes text and wraps every token whose folded term is in terms with highlight
+// markers, cropping long text to a window around the first match. Only the markers are markup, so
+// the snippet is safe to render as HTML.
+func Snippet(text string, terms map[string]bool) (string, bool) {
+	var matches []Token
+	for _, token := range Tokenise(text) {
+		if terms[token.Term] {
+			matches = append(matches, token)
+		}
+	}
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	windowStart, windowEnd := snippetWindow(text, matches[0].Start)
+
+	var snippet strings.Builder
+	if windowStart > 0 {
+		snippet.WriteString("…")
+	}
+	cursor := windowStart
+	for _, match := range matches {
+		if match.Start < windowStart || match.End > windowEnd {
+			continue
+		}
+		snippet.WriteString(html.EscapeString(text[cursor:match.Start]))
+		snippet.WriteString(HighlightStart)
+		snippet.WriteString(html.EscapeString(text[match.Start:match.End]))
+		snippet.WriteString(HighlightEnd)
+		cursor = match.End
+	}
+	snippet.WriteString(html.EscapeString(text[cursor:windowEnd]))
+	if windowEnd < len(text) {
+		snippet.WriteString("…")
+	}
+
+	return snippet.String(), true
+}
+
+// snippetWindow picks byte offsets of at most maxSnippetRunes runes, starting a little before the first match
+func snippetWindow(text string, firstMatch int) (int, int) {
+	runeOffsets := make([]int, 0, len(text))
+	firstMatchRune := 0
+	for i := range text {
+		if i == firstMatch {
+			firstMatchRune = len(runeOffsets)
+		}
+		runeOffsets = append(runeOffsets, i)
+	}
+	if len(runeOffsets) <= maxSnippetRunes {
+		return 0, len(text)
+	}
+
+	startRune := max(firstMatchRune-maxSnippetRunes/4, 0)
+	endRune := min(startRune+maxSnippetRunes, len(runeOffsets))
+	startRune = max(endRune-maxSnippetRunes, 0)
+
+	// Avoid cutting a word in half at the start of the window
+	for startRune > 0 && startRune < firstMatchRune && text[runeOffsets[startRune]-1] != ' ' {
+		startRune++
+	}
+
+	end := len(text)
+	if endRune < len(runeOffsets) {
+		end = runeOffsets[endRune]
+	}
+	return runeOffsets[startRune], end
+}
@@ -0,0 +1,163 @@
+package fulltext
+
+import (
+	"strings"
+	"testing"
+)
+
+func testIndex() *Index {
+	return NewIndex([]Document{
+		{
+			ID: "tt0000001",
+			Fields: []Field{
+				{Name: "title", Text: "Lunar Glow", Weight: 3},
+				{Name: "genre", Text: "Sci-Fi", Weight: 2},
+				{Name: "genre", Text: "Time Travel", Weight: 2},
+				{Name: "plot_summary", Text: "Two souls separated by centuries", Weight: 1},
+			},
+		},
+		{
+			ID: "tt0000002",
+			Fields: []Field{
+				{Name: "title", Text: "The Grand Adventure", Weight: 3},
+				{Name: "cast.actor_name", Text: "Zoe Saldaña", Weight: 1.5},
+				{Name: "plot_summary", Text: "A crew must travel across time to save Earth", Weight: 1},
+			},
+		},
+		{
+			ID: "tt0000003",
+			Fields: []Field{
+				{Name: "title", Text: "Travelling Light", Weight: 3},
+				{Name: "plot_summary", Text: "A musician on tour", Weight: 1},
+			},
+		},
+	})
+}
+
+func resultIDs(results []Result) []string {
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		limit         int
+		expectedIDs   []string
+		expectedTotal int
+	}{
+		{"phrase across genre field ranks weighted field first", "time travel", 0, []string{"tt0000001", "tt0000002"}, 2},
+		{"diacritics folded in query", "SALDAÑA", 0, []string{"tt0000002"}, 1},
+		{"diacritics folded in document", "saldana", 0, []string{"tt0000002"}, 1},
+		{"prefix match", "lun", 0, []string{"tt0000001"}, 1},
+		{"exact genre match beats title prefix beats plot match", "travel", 0, []string{"tt0000001", "tt0000003", "tt0000002"}, 3},
+		{"all terms must match", "lunar musician", 0, []string{}, 0},
+		{"limit keeps total", "travel", 1, []string{"tt0000001"}, 3},
+		{"single letter does not expand", "t", 0, []string{}, 0},
+		{"no words", "!!!", 0, []string{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			index := testIndex()
+
+			// When
+			results, total := index.Search(tt.query, tt.limit)
+
+			// Then
+			ids := resultIDs(results)
+			if total != tt.expectedTotal {
+				t.Errorf("Given query %q, When searched, Then expected total %d, got %d", tt.query, tt.expectedTotal, total)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.expectedIDs, ",") {
+				t.Errorf("Given query %q, When searched, Then expected %v, got %v", tt.query, tt.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestIndexSearchHighlights(t *testing.T) {
+	// Given
+	index := testIndex()
+
+	// When
+	results, _ := index.Search("time travel", 1)
+
+	// Then
+	if len(results) != 1 {
+		t.Fatalf("Given query %q, When searched, Then expected 1 result, got %d", "time travel", len(results))
+	}
+	highlights := results[0].Highlights
+	if len(highlights) != 1 || highlights[0].Field != "genre" || highlights[0].Snippet != "<em>Time</em> <em>Travel</em>" {
+		t.Errorf("Given query %q, When searched, Then expected highlighted genre, got %+v", "time travel", highlights)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("filler words here ", 20) + "the Saldaña match" + strings.Repeat(" trailing words", 20)
+
+	tests := []struct {
+		name      string
+		text      string
+		terms     map[string]bool
+		expected  string
+		expectOK  bool
+		checkFunc func(string) bool
+	}{
+		{
+			name:     "no match",
+			text:     "Lunar Glow",
+			terms:    map[string]bool{"travel": true},
+			expectOK: false,
+		},
+		{
+			name:     "preserves original casing and accents",
+			text:     "Zoe Saldaña",
+			terms:    map[string]bool{"saldana": true},
+			expected: "Zoe <em>Saldaña</em>",
+			expectOK: true,
+		},
+		{
+			name:     "escapes HTML in the text",
+			text:     "Tom & Jerry <script>chase</script> at <em>dawn</em>",
+			terms:    map[string]bool{"chase": true, "jerry": true},
+			expected: "Tom &amp; <em>Jerry</em> &lt;script&gt;<em>chase</em>&lt;/script&gt; at &lt;em&gt;dawn&lt;/em&gt;",
+			expectOK: true,
+		},
+		{
+			name:     "long text cropped around match",
+			text:     long,
+			terms:    map[string]bool{"saldana": true},
+			expectOK: true,
+			checkFunc: func(s string) bool {
+				return strings.HasPrefix(s, "…") && strings.HasSuffix(s, "…") && strings.Contains(s, "<em>Saldaña</em>") && len([]rune(s)) < len([]rune(long))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			snippet, ok := Snippet(tt.text, tt.terms)
+
+			// Then
+			if ok != tt.expectOK {
+				t.Fatalf("Given text %q, When snippeted, Then expected ok %v, got %v", tt.text, tt.expectOK, ok)
+			}
+			if tt.checkFunc != nil {
+				if !tt.checkFunc(snippet) {
+					t.Errorf("Given text %q, When snippeted, Then got unexpected snippet %q", tt.text, snippet)
+				}
+				return
+			}
+			if snippet != tt.expected {
+				t.Errorf("Given text %q, When snippeted, Then expected %q, got %q", tt.text, tt.expected, snippet)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package fulltext
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Token is a folded term and the byte span it came from in the original text
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Fold lower-cases text and strips diacritics so "Saldaña" and "saldana" compare equal
+func Fold(text string) string {
+	folder := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(folder, text)
+	if err != nil {
+		folded = text
+	}
+	return strings.ToLower(folded)
+}
+
+// Tokenise splits text on anything that is not a letter or digit and folds each word
+func Tokenise(text string) []Token {
+	var tokens []Token
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+		if isWordRune && start < 0 {
+			start = i
+		}
+		if !isWordRune && start >= 0 {
+			tokens = append(tokens, Token{Term: Fold(text[start:i]), Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, Token{Term: Fold(text[start:]), Start: start, End: len(text)})
+	}
+	return tokens
+}
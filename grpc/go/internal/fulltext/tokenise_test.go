@@ -0,0 +1,76 @@
+package fulltext
+
+import (
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"lower case", "Lunar", "lunar"},
+		{"diacritics", "Zoe Saldaña", "zoe saldana"},
+		{"accented vowels", "Amélie Poulain", "amelie poulain"},
+		{"already folded", "time travel", "time travel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			result := Fold(tt.input)
+
+			// Then
+			if result != tt.expected {
+				t.Errorf("Given input %q, When folded, Then expected %q, got %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTokenise(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Token
+	}{
+		{"empty", "", nil},
+		{"punctuation only", "--- !!", nil},
+		{
+			name:  "words and punctuation",
+			input: "Sci-Fi, Time Travel!",
+			expected: []Token{
+				{Term: "sci", Start: 0, End: 3},
+				{Term: "fi", Start: 4, End: 6},
+				{Term: "time", Start: 8, End: 12},
+				{Term: "travel", Start: 13, End: 19},
+			},
+		},
+		{
+			name:  "multi-byte runes keep byte offsets",
+			input: "Zoe Saldaña",
+			expected: []Token{
+				{Term: "zoe", Start: 0, End: 3},
+				{Term: "saldana", Start: 4, End: 12},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			result := Tokenise(tt.input)
+
+			// Then
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Given input %q, When tokenised, Then expected %v, got %v", tt.input, tt.expected, result)
+			}
+			for i := range tt.expected {
+				if result[i] != tt.expected[i] {
+					t.Errorf("Given input %q, When tokenised, Then expected token %v, got %v", tt.input, tt.expected[i], result[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the collectors a server exposes on /metrics. A nil *Metrics records nothing,
+// so servers running with metrics disabled need no checks at each call site.
+type Metrics struct {
+	registry       *prometheus.Registry
+	requests       *prometheus.CounterVec
+	latency        *prometheus.HistogramVec
+	inFlight       *prometheus.GaugeVec
+	moviesLoaded   prometheus.Gauge
+	apiKeyRequests *prometheus.CounterVec
+	certExpiry     *prometheus.GaugeVec
+}
+
+// New registers the request metrics for a server; subsystem ("grpc" or "http") prefixes
+// their names, e.g. grpc_server_requests_total
+func New(subsystem string) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "server_requests_total",
+			Help:      "Requests completed, by method and status code.",
+		}, []string{"method", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: subsystem,
+			Name:      "server_request_duration_seconds",
+			Help:      "Time to complete a request (the whole lifetime for streams), by method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "server_requests_in_flight",
+			Help:      "Requests currently being handled, by method.",
+		}, []string{"method"}),
+		moviesLoaded: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "movie_catalogue_movies",
+			Help: "Movies in the catalogue after the last load.",
+		}),
+		apiKeyRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "movie_api_key_requests_total",
+			Help: "Authenticated requests, by API key name.",
+		}, []string{"api_key"}),
+		certExpiry: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "tls_certificate_expiry_days",
+			Help: "Days until each loaded TLS certificate expires, by file and subject; negative once expired.",
+		}, []string{"file", "subject"}),
+	}
+	m.registry.MustRegister(
+		m.requests, m.latency, m.inFlight, m.moviesLoaded, m.apiKeyRequests, m.certExpiry,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// StartRequest marks a request to method as in flight; call the returned function with its
+// status code when it completes
+func (m *Metrics) StartRequest(method string) func(code string) {
+	if m == nil {
+		return func(string) {}
+	}
+	start := time.Now()
+	inFlight := m.inFlight.WithLabelValues(method)
+	inFlight.Inc()
+	return func(code string) {
+		inFlight.Dec()
+		m.requests.WithLabelValues(method, code).Inc()
+		m.latency.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// SetMoviesLoaded records the size of the catalogue
+func (m *Metrics) SetMoviesLoaded(count int) {
+	if m == nil {
+		return
+	}
+	m.moviesLoaded.Set(float64(count))
+}
+
+// CountAPIKeyRequest counts a request authenticated with the named API key
+func (m *Metrics) CountAPIKeyRequest(name string) {
+	if m == nil {
+		return
+	}
+	m.apiKeyRequests.WithLabelValues(name).Inc()
+}
+
+// SetCertificateExpiry records days until expiry for the certificates in file, keyed by subject,
+// replacing what was recorded for that file before so rotated certificates do not linger
+func (m *Metrics) SetCertificateExpiry(file string, days map[string]float64) {
+	if m == nil {
+		return
+	}
+	m.certExpiry.DeletePartialMatch(prometheus.Labels{"file": file})
+	for subject, d := range days {
+		m.certExpiry.WithLabelValues(file, subject).Set(d)
+	}
+}
+
+// Handler serves the registered metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// InstrumentHandler records requests to an HTTP handler under method, labelled with the
+// response status code
+func (m *Metrics) InstrumentHandler(method string, next http.Handler) http.Handler {
+	if m == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		done := m.StartRequest(method)
+		defer func() { done(fmt.Sprint(recorder.status)) }()
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// ListenAndServe serves /metrics on its own port until ctx is done, so scrapes stay off the
+// API listener and do not need its client certificates. Other paths go to probes when it is
+// not nil, such as health endpoints that share the listener.
+func (m *Metrics) ListenAndServe(ctx context.Context, port int, probes http.Handler) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	if probes != nil {
+		mux.Handle("/", probes)
+	}
+	server := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	stop := context.AfterFunc(ctx, func() { server.Shutdown(context.Background()) })
+	defer stop()
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scrape returns the exposition text served by m
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+	return string(body)
+}
+
+func TestMetricsRecording(t *testing.T) {
+	// Given
+	m := New("grpc")
+
+	// When
+	done := m.StartRequest("/movie.Getter/GetMovie")
+	inFlight := scrape(t, m)
+	done("OK")
+	m.StartRequest("/movie.Getter/GetMovie")("NotFound")
+	m.SetMoviesLoaded(42)
+	m.CountAPIKeyRequest("test-key")
+	m.CountAPIKeyRequest("test-key")
+	output := scrape(t, m)
+
+	// Then
+	expectedInFlight := `grpc_server_requests_in_flight{method="/movie.Getter/GetMovie"} 1`
+	if !strings.Contains(inFlight, expectedInFlight) {
+		t.Errorf("Given a started request, When scraping, Then expected %q, got:\n%s", expectedInFlight, inFlight)
+	}
+	for _, expected := range []string{
+		`grpc_server_requests_total{code="OK",method="/movie.Getter/GetMovie"} 1`,
+		`grpc_server_requests_total{code="NotFound",method="/movie.Getter/GetMovie"} 1`,
+		`grpc_server_request_duration_seconds_count{code="OK",method="/movie.Getter/GetMovie"} 1`,
+		`grpc_server_requests_in_flight{method="/movie.Getter/GetMovie"} 0`,
+		`movie_catalogue_movies 42`,
+		`movie_api_key_requests_total{api_key="test-key"} 2`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Given recorded requests, When scraping, Then expected %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestInstrumentHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      http.HandlerFunc
+		expectedLine string
+	}{
+		{
+			name:         "implicit OK",
+			handler:      func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("[]")) },
+			expectedLine: `http_server_requests_total{code="200",method="GET /movies"} 1`,
+		},
+		{
+			name: "explicit error status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "Invalid min_rating", http.StatusBadRequest)
+			},
+			expectedLine: `http_server_requests_total{code="400",method="GET /movies"} 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			m := New("http")
+			handler := m.InstrumentHandler("GET /movies", tt.handler)
+
+			// When
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/movies", nil))
+
+			// Then
+			if output := scrape(t, m); !strings.Contains(output, tt.expectedLine) {
+				t.Errorf("Given a handler with %s, When it is called, Then expected %q, got:\n%s", tt.name, tt.expectedLine, output)
+			}
+		})
+	}
+}
+
+func TestSetCertificateExpiry(t *testing.T) {
+	// Given
+	m := New("grpc")
+	m.SetCertificateExpiry("tls/server-public.key", map[string]float64{"CN=old": 2})
+
+	// When
+	m.SetCertificateExpiry("tls/server-public.key", map[string]float64{"CN=new": 365})
+	output := scrape(t, m)
+
+	// Then
+	expected := `tls_certificate_expiry_days{file="tls/server-public.key",subject="CN=new"} 365`
+	if !strings.Contains(output, expected) {
+		t.Errorf("Given a rotated certificate, When scraping, Then expected %q, got:\n%s", expected, output)
+	}
+	if strings.Contains(output, `subject="CN=old"`) {
+		t.Errorf("Given a rotated certificate, When scraping, Then expected the replaced certificate to be gone, got:\n%s", output)
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	// Given
+	var m *Metrics
+	called := false
+
+	// When
+	m.StartRequest("/movie.Getter/GetMovie")("OK")
+	m.SetMoviesLoaded(1)
+	m.CountAPIKeyRequest("test-key")
+	m.SetCertificateExpiry("tls/server-public.key", map[string]float64{"CN=localhost": 30})
+	m.InstrumentHandler("GET /movies", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true })).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/movies", nil))
+
+	// Then
+	if !called {
+		t.Errorf("Given disabled metrics, When instrumenting a handler, Then expected the handler to still be called")
+	}
+}
+
+func TestListenAndServeStopsWithContext(t *testing.T) {
+	// Given
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not find a free port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- New("grpc").ListenAndServe(ctx, port, nil) }()
+	time.Sleep(100 * time.Millisecond)
+
+	// When
+	cancel()
+
+	// Then
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Given a cancelled context, When serving metrics, Then expected no error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Given a cancelled context, When serving metrics, Then expected the listener to stop")
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"case-studies/grpc/internal/observability"
+)
+
+// RequestIDHandler is the HTTP counterpart of RequestIDInterceptor: it attaches the caller's
+// X-Request-Id, or a new one, to the request context and echoes it in the response headers
+func RequestIDHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(observability.RequestIDHeader)
+		if !ValidRequestID(id) {
+			id = observability.NewRequestID()
+		}
+		w.Header().Set(observability.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(observability.ContextWithRequestID(r.Context(), id)))
+	})
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"case-studies/grpc/internal/observability"
+)
+
+func TestRequestIDHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		expectedID string
+	}{
+		{name: "caller request ID", header: "abc-123", expectedID: "abc-123"},
+		{name: "no request ID"},
+		{name: "request ID with spaces", header: "abc 123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			var handlerID string
+			handler := RequestIDHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerID, _ = observability.RequestIDFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/movies", nil)
+			if tt.header != "" {
+				req.Header.Set(observability.RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			// When
+			handler.ServeHTTP(rec, req)
+
+			// Then
+			if !ValidRequestID(handlerID) {
+				t.Fatalf("Given %s, When served, Then expected a usable request ID in the context, got %q", tt.name, handlerID)
+			}
+			if tt.expectedID != "" && handlerID != tt.expectedID {
+				t.Errorf("Given %s, When served, Then expected request ID %q, got %q", tt.name, tt.expectedID, handlerID)
+			}
+			if echoed := rec.Header().Get(observability.RequestIDHeader); echoed != handlerID {
+				t.Errorf("Given %s, When served, Then expected %q echoed in the response header, got %q", tt.name, handlerID, echoed)
+			}
+		})
+	}
+}
@@ -2,17 +2,75 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"case-studies/grpc/internal/apikey"
+	"case-studies/grpc/internal/auth"
+	"case-studies/grpc/internal/metrics"
 	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/ratelimit"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
+// maxRequestIDLength bounds a caller-supplied request ID before it is copied into every log line
+const maxRequestIDLength = 128
+
+// RequestIDInterceptor attaches the caller's x-request-id, or a new one if it is absent or
+// unusable, to the context and echoes it in the response headers. It must run first so
+// every log line of the call carries the ID.
+func RequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id := incomingRequestID(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(observability.RequestIDHeader, id))
+		return handler(observability.ContextWithRequestID(ctx, id), req)
+	}
+}
+
+// RequestIDStreamInterceptor is the streaming counterpart of RequestIDInterceptor
+func RequestIDStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := incomingRequestID(ss.Context())
+		ss.SetHeader(metadata.Pairs(observability.RequestIDHeader, id))
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: observability.ContextWithRequestID(ss.Context(), id)})
+	}
+}
+
+func incomingRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get(observability.RequestIDHeader); len(ids) > 0 && ValidRequestID(ids[0]) {
+		return ids[0]
+	}
+	return observability.NewRequestID()
+}
+
+// ValidRequestID reports whether a caller-supplied request ID is short and printable ASCII,
+// so it cannot forge or bloat log lines
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
 // LoggingInterceptor provides structured logging for gRPC requests
 func LoggingInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -29,10 +87,12 @@ func LoggingInterceptor() grpc.UnaryServerInterceptor {
 			peer = p[0]
 		}
 
-		observability.LogInfrastructureInput("gRPC request started", map[string]interface{}{
-			"method":     info.FullMethod,
-			"user_agent": userAgent,
-			"peer":       peer,
+		observability.LogInfrastructureInputContext(ctx, "gRPC request started", map[string]interface{}{
+			"method":      info.FullMethod,
+			"user_agent":  userAgent,
+			"peer":        peer,
+			"client_cert": clientCertName(ctx),
+			"principal":   principalName(ctx),
 		})
 
 		resp, err := handler(ctx, req)
@@ -47,10 +107,11 @@ func LoggingInterceptor() grpc.UnaryServerInterceptor {
 			}
 		}
 
-		observability.LogInfrastructureOutput("gRPC request completed", map[string]interface{}{
+		observability.LogInfrastructureOutputContext(ctx, "gRPC request completed", map[string]interface{}{
 			"method":      info.FullMethod,
 			"duration":    duration,
 			"status_code": code.String(),
+			"principal":   principalName(ctx),
 			"error":       err,
 		})
 
@@ -58,13 +119,125 @@ func LoggingInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// LoggingStreamInterceptor provides structured logging for gRPC streams and each message on them
+func LoggingStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		userAgent := "unknown"
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			userAgent = ua[0]
+		}
+
+		peer := "unknown"
+		if p := md.Get("x-forwarded-for"); len(p) > 0 {
+			peer = p[0]
+		}
+
+		observability.LogInfrastructureInputContext(ss.Context(), "gRPC stream started", map[string]interface{}{
+			"method":        info.FullMethod,
+			"user_agent":    userAgent,
+			"peer":          peer,
+			"client_stream": info.IsClientStream,
+			"server_stream": info.IsServerStream,
+			"client_cert":   clientCertName(ss.Context()),
+			"principal":     principalName(ss.Context()),
+		})
+
+		wrapped := &loggingServerStream{ServerStream: ss, method: info.FullMethod}
+		err := handler(srv, wrapped)
+
+		duration := time.Since(start)
+		code := codes.OK
+		if err != nil {
+			if st, ok := status.FromError(err); ok {
+				code = st.Code()
+			} else {
+				code = codes.Unknown
+			}
+		}
+
+		observability.LogInfrastructureOutputContext(ss.Context(), "gRPC stream completed", map[string]interface{}{
+			"method":            info.FullMethod,
+			"duration":          duration,
+			"status_code":       code.String(),
+			"messages_received": wrapped.received.Load(),
+			"messages_sent":     wrapped.sent.Load(),
+			"principal":         principalName(ss.Context()),
+			"error":             err,
+		})
+
+		return err
+	}
+}
+
+// principalName returns the authenticated API key name or token subject for logging
+func principalName(ctx context.Context) string {
+	if principal, ok := auth.FromContext(ctx); ok {
+		return principal.Name
+	}
+	return "unknown"
+}
+
+// clientCertName returns the verified client certificate's URI SAN or common name for logging
+func clientCertName(ctx context.Context) string {
+	if identity, ok := auth.PeerIdentityFromContext(ctx); ok {
+		return identity.String()
+	}
+	return "unknown"
+}
+
+// loggingServerStream wraps a grpc.ServerStream to log every message received and sent
+type loggingServerStream struct {
+	grpc.ServerStream
+	method   string
+	received atomic.Int64
+	sent     atomic.Int64
+}
+
+func (s *loggingServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if errors.Is(err, io.EOF) {
+		return err
+	}
+	if err != nil {
+		observability.LogInfrastructureErrorContext(s.Context(), "gRPC stream receive failed", err, map[string]interface{}{
+			"method": s.method,
+		})
+		return err
+	}
+
+	observability.LogInfrastructureInputContext(s.Context(), "gRPC stream message received", map[string]interface{}{
+		"method":        s.method,
+		"message_index": s.received.Add(1),
+	})
+	return nil
+}
+
+func (s *loggingServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err != nil {
+		observability.LogInfrastructureErrorContext(s.Context(), "gRPC stream send failed", err, map[string]interface{}{
+			"method": s.method,
+		})
+		return err
+	}
+
+	observability.LogInfrastructureOutputContext(s.Context(), "gRPC stream message sent", map[string]interface{}{
+		"method":        s.method,
+		"message_index": s.sent.Add(1),
+	})
+	return nil
+}
+
 // ErrorInterceptor provides consistent error handling
 func ErrorInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		resp, err := handler(ctx, req)
 
 		if err != nil {
-			observability.LogInfrastructureError("gRPC error occurred", err, map[string]interface{}{
+			observability.LogInfrastructureErrorContext(ctx, "gRPC error occurred", err, map[string]interface{}{
 				"method": info.FullMethod,
 			})
 
@@ -77,12 +250,31 @@ func ErrorInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// ErrorStreamInterceptor provides consistent error handling for streams
+func ErrorStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+
+		if err != nil {
+			observability.LogInfrastructureErrorContext(ss.Context(), "gRPC stream error occurred", err, map[string]interface{}{
+				"method": info.FullMethod,
+			})
+
+			if _, ok := status.FromError(err); !ok {
+				err = status.Errorf(codes.Internal, "internal server error: %v", err)
+			}
+		}
+
+		return err
+	}
+}
+
 // RecoveryInterceptor provides panic recovery
 func RecoveryInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				observability.LogInfrastructureError("panic recovered in gRPC handler", fmt.Errorf("panic: %v", r), map[string]interface{}{
+				observability.LogInfrastructureErrorContext(ctx, "panic recovered in gRPC handler", fmt.Errorf("panic: %v", r), map[string]interface{}{
 					"method": info.FullMethod,
 					"panic":  r,
 				})
@@ -94,12 +286,30 @@ func RecoveryInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// RecoveryStreamInterceptor provides panic recovery for streams
+func RecoveryStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				observability.LogInfrastructureErrorContext(ss.Context(), "panic recovered in gRPC stream handler", fmt.Errorf("panic: %v", r), map[string]interface{}{
+					"method": info.FullMethod,
+					"panic":  r,
+				})
+				err = status.Errorf(codes.Internal, "internal server error")
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
 // ClientLoggingInterceptor provides logging for client requests
 func ClientLoggingInterceptor() grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 		start := time.Now()
+		ctx = outgoingRequestID(ctx)
 
-		observability.LogInfrastructureInput("gRPC client request started", map[string]interface{}{
+		observability.LogInfrastructureInputContext(ctx, "gRPC client request started", map[string]interface{}{
 			"method": method,
 		})
 
@@ -115,7 +325,7 @@ func ClientLoggingInterceptor() grpc.UnaryClientInterceptor {
 			}
 		}
 
-		observability.LogInfrastructureOutput("gRPC client request completed", map[string]interface{}{
+		observability.LogInfrastructureOutputContext(ctx, "gRPC client request completed", map[string]interface{}{
 			"method":      method,
 			"duration":    duration,
 			"status_code": code.String(),
@@ -126,23 +336,401 @@ func ClientLoggingInterceptor() grpc.UnaryClientInterceptor {
 	}
 }
 
-// APIKeyAuthInterceptor checks for a valid x-api-key in the gRPC metadata
-func APIKeyAuthInterceptor(validAPIKeys []string) grpc.UnaryServerInterceptor {
+// ClientRequestIDStreamInterceptor sends an x-request-id on every stream the client opens
+func ClientRequestIDStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingRequestID(ctx), desc, cc, method, opts...)
+	}
+}
+
+// outgoingRequestID sends the request ID in ctx, or a new one, as x-request-id unless the
+// caller already set the header, and keeps it in ctx for the client's own log lines
+func outgoingRequestID(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if ids := md.Get(observability.RequestIDHeader); len(ids) > 0 {
+		return observability.ContextWithRequestID(ctx, ids[0])
+	}
+	id, ok := observability.RequestIDFromContext(ctx)
+	if !ok {
+		id = observability.NewRequestID()
+		ctx = observability.ContextWithRequestID(ctx, id)
+	}
+	return metadata.AppendToOutgoingContext(ctx, observability.RequestIDHeader, id)
+}
+
+// Credential authenticates one kind of credential carried in the gRPC metadata
+type Credential interface {
+	// Description names the credential in error messages, e.g. "API key"
+	Description() string
+	// Present reports whether md carries this kind of credential
+	Present(md metadata.MD) bool
+	// Authenticate resolves the credential in md to a principal
+	Authenticate(md metadata.MD) (auth.Principal, error)
+}
+
+// AuthInterceptor authenticates each call with the first of credentials present in its metadata
+// and attaches the principal to the context. Calls carrying none of them are rejected.
+func AuthInterceptor(credentials ...Credential) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "missing metadata")
-		}
-		apiKeys := md.Get("x-api-key")
-		if len(apiKeys) == 0 {
-			return nil, status.Error(codes.Unauthenticated, "invalid or missing API key")
-		}
-		incomingKey := apiKeys[0]
-		for _, valid := range validAPIKeys {
-			if incomingKey == valid {
-				return handler(ctx, req)
-			}
+		principal, err := authenticate(ctx, info.FullMethod, credentials)
+		if err != nil {
+			return nil, err
+		}
+		return handler(auth.NewContext(ctx, principal), req)
+	}
+}
+
+// AuthStreamInterceptor authenticates a stream before it is opened, like AuthInterceptor
+func AuthStreamInterceptor(credentials ...Credential) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		principal, err := authenticate(ss.Context(), info.FullMethod, credentials)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: auth.NewContext(ss.Context(), principal)})
+	}
+}
+
+// ExemptMethods skips interceptor for the methods in exempt, given as full method names or
+// service prefixes ("/grpc.health.v1.Health/") as in APIKeyScopeInterceptor
+func ExemptMethods(interceptor grpc.UnaryServerInterceptor, exempt ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if isExempt(info.FullMethod, exempt) {
+			return handler(ctx, req)
+		}
+		return interceptor(ctx, req, info, handler)
+	}
+}
+
+// ExemptStreamMethods is the streaming counterpart of ExemptMethods
+func ExemptStreamMethods(interceptor grpc.StreamServerInterceptor, exempt ...string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isExempt(info.FullMethod, exempt) {
+			return handler(srv, ss)
+		}
+		return interceptor(srv, ss, info, handler)
+	}
+}
+
+func isExempt(fullMethod string, exempt []string) bool {
+	for _, method := range exempt {
+		if fullMethod == method || (strings.HasSuffix(method, "/") && strings.HasPrefix(fullMethod, method)) {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyAuthInterceptor checks for a valid x-api-key in the gRPC metadata and attaches
+// the authenticated principal to the context
+func APIKeyAuthInterceptor(authenticator *apikey.Authenticator) grpc.UnaryServerInterceptor {
+	return AuthInterceptor(APIKeyCredential(authenticator))
+}
+
+// APIKeyAuthStreamInterceptor checks for a valid x-api-key in the gRPC metadata before a stream is opened
+func APIKeyAuthStreamInterceptor(authenticator *apikey.Authenticator) grpc.StreamServerInterceptor {
+	return AuthStreamInterceptor(APIKeyCredential(authenticator))
+}
+
+// contextServerStream overrides the context of a grpc.ServerStream
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func authenticate(ctx context.Context, fullMethod string, credentials []Credential) (auth.Principal, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return auth.Principal{}, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+	descriptions := make([]string, 0, len(credentials))
+	for _, credential := range credentials {
+		descriptions = append(descriptions, credential.Description())
+		if !credential.Present(md) {
+			continue
+		}
+		principal, err := credential.Authenticate(md)
+		if err != nil {
+			// The reason is only logged so callers cannot probe which credentials exist
+			observability.LogErrorContext(ctx, "authentication", "authenticate", err, map[string]interface{}{
+				"method":     fullMethod,
+				"credential": credential.Description(),
+				"principal":  principal.Name,
+			})
+			return auth.Principal{}, status.Errorf(codes.Unauthenticated, "invalid or missing %s", credential.Description())
+		}
+		return principal, nil
+	}
+	return auth.Principal{}, status.Errorf(codes.Unauthenticated, "invalid or missing %s", strings.Join(descriptions, " or "))
+}
+
+// APIKeyCredential authenticates the x-api-key header
+func APIKeyCredential(authenticator *apikey.Authenticator) Credential {
+	return apiKeyCredential{authenticator: authenticator}
+}
+
+type apiKeyCredential struct {
+	authenticator *apikey.Authenticator
+}
+
+func (c apiKeyCredential) Description() string {
+	return "API key"
+}
+
+func (c apiKeyCredential) Present(md metadata.MD) bool {
+	_, ok := incomingAPIKey(md)
+	return ok
+}
+
+func (c apiKeyCredential) Authenticate(md metadata.MD) (auth.Principal, error) {
+	incomingKey, _ := incomingAPIKey(md)
+	return c.authenticator.Authenticate(incomingKey)
+}
+
+func incomingAPIKey(md metadata.MD) (string, bool) {
+	apiKeys := md.Get("x-api-key")
+	if len(apiKeys) == 0 {
+		return "", false
+	}
+	return apiKeys[0], true
+}
+
+// BearerTokenCredential authenticates an "authorization: Bearer <JWT>" header
+func BearerTokenCredential(verifier *auth.JWTVerifier) Credential {
+	return bearerTokenCredential{verifier: verifier}
+}
+
+type bearerTokenCredential struct {
+	verifier *auth.JWTVerifier
+}
+
+func (c bearerTokenCredential) Description() string {
+	return "bearer token"
+}
+
+func (c bearerTokenCredential) Present(md metadata.MD) bool {
+	_, ok := incomingBearerToken(md)
+	return ok
+}
+
+func (c bearerTokenCredential) Authenticate(md metadata.MD) (auth.Principal, error) {
+	token, _ := incomingBearerToken(md)
+	return c.verifier.Verify(token)
+}
+
+func incomingBearerToken(md metadata.MD) (string, bool) {
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", false
+	}
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// APIKeyScopeInterceptor rejects calls whose API key lacks the scope the method requires.
+// methodScopes maps full method names ("/movie.MovieAdmin/CreateMovie") or service prefixes
+// ("/movie.MovieAdmin/") to a scope; methods without an entry require none.
+// It must run after the authentication interceptor.
+func APIKeyScopeInterceptor(methodScopes map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authoriseAPIKeyScope(ctx, info.FullMethod, methodScopes); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// APIKeyScopeStreamInterceptor is the streaming counterpart of APIKeyScopeInterceptor
+func APIKeyScopeStreamInterceptor(methodScopes map[string]string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authoriseAPIKeyScope(ss.Context(), info.FullMethod, methodScopes); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func authoriseAPIKeyScope(ctx context.Context, fullMethod string, methodScopes map[string]string) error {
+	scope := requiredScope(fullMethod, methodScopes)
+	if scope == "" {
+		return nil
+	}
+
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "invalid or missing API key")
+	}
+	if slices.Contains(principal.Scopes, scope) {
+		return nil
+	}
+
+	observability.LogErrorContext(ctx, "authorisation", "authoriseAPIKeyScope", fmt.Errorf("missing scope %s", scope), map[string]interface{}{
+		"method":    fullMethod,
+		"principal": principal.Name,
+	})
+	return status.Errorf(codes.PermissionDenied, "API key lacks the %s scope", scope)
+}
+
+func requiredScope(fullMethod string, methodScopes map[string]string) string {
+	if scope, ok := methodScopes[fullMethod]; ok {
+		return scope
+	}
+	// "/package.Service/Method" -> "/package.Service/"
+	if i := strings.LastIndex(fullMethod, "/"); i > 0 {
+		return methodScopes[fullMethod[:i+1]]
+	}
+	return ""
+}
+
+// MetricsInterceptor records request counts, latency and in-flight requests by method and
+// status code. It should run first so calls refused by later interceptors are counted too.
+func MetricsInterceptor(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		done := m.StartRequest(info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(status.Code(err).String())
+		return resp, err
+	}
+}
+
+// MetricsStreamInterceptor records each stream as one request lasting until it closes
+func MetricsStreamInterceptor(m *metrics.Metrics) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		done := m.StartRequest(info.FullMethod)
+		err := handler(srv, ss)
+		done(status.Code(err).String())
+		return err
+	}
+}
+
+// APIKeyUsageInterceptor counts calls per API key name, including those later refused for
+// scope or rate limits. It must run after the authentication interceptor.
+func APIKeyUsageInterceptor(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		countAPIKeyRequest(ctx, m)
+		return handler(ctx, req)
+	}
+}
+
+// APIKeyUsageStreamInterceptor counts each stream opened with an API key as one call
+func APIKeyUsageStreamInterceptor(m *metrics.Metrics) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		countAPIKeyRequest(ss.Context(), m)
+		return handler(srv, ss)
+	}
+}
+
+// countAPIKeyRequest skips token subjects, which are not bounded by the configuration
+func countAPIKeyRequest(ctx context.Context, m *metrics.Metrics) {
+	if principal, ok := auth.FromContext(ctx); ok && principal.Method == auth.MethodAPIKey {
+		m.CountAPIKeyRequest(principal.Name)
+	}
+}
+
+// PeerPolicyInterceptor refuses calls the connection's verified client certificate is not
+// allowed to make under policy, with PERMISSION_DENIED and an audit log entry
+func PeerPolicyInterceptor(policy *auth.PeerPolicy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorisePeer(ctx, info.FullMethod, policy); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// PeerPolicyStreamInterceptor is the streaming counterpart of PeerPolicyInterceptor
+func PeerPolicyStreamInterceptor(policy *auth.PeerPolicy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorisePeer(ss.Context(), info.FullMethod, policy); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func authorisePeer(ctx context.Context, fullMethod string, policy *auth.PeerPolicy) error {
+	identity, ok := auth.PeerIdentityFromContext(ctx)
+	if ok && policy.Allows(identity, fullMethod) {
+		return nil
+	}
+
+	observability.LogAudit(ctx, "deny", map[string]interface{}{
+		"method":           fullMethod,
+		"peer_common_name": identity.CommonName,
+		"peer_uris":        identity.URIs,
+		"principal":        principalName(ctx),
+	})
+	if !ok {
+		return status.Error(codes.PermissionDenied, "a verified client certificate is required")
+	}
+	return status.Errorf(codes.PermissionDenied, "client certificate %s may not call %s", identity, fullMethod)
+}
+
+// RateLimitInterceptor charges each call to the token bucket and daily quota of the authenticated
+// API key name, failing with RESOURCE_EXHAUSTED and a retry-after trailer once either runs out.
+// It must run after the authentication interceptor.
+func RateLimitInterceptor(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if trailer, err := chargeAPIKey(ctx, info.FullMethod, limiter); err != nil {
+			grpc.SetTrailer(ctx, trailer)
+			return nil, err
 		}
-		return nil, status.Error(codes.Unauthenticated, "invalid or missing API key")
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitStreamInterceptor charges every message a client sends on a stream, so a long-lived
+// bidi stream is limited the same way as the equivalent unary calls
+func RateLimitStreamInterceptor(limiter *ratelimit.Limiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &rateLimitedServerStream{ServerStream: ss, method: info.FullMethod, limiter: limiter})
+	}
+}
+
+type rateLimitedServerStream struct {
+	grpc.ServerStream
+	method  string
+	limiter *ratelimit.Limiter
+}
+
+func (s *rateLimitedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if trailer, err := chargeAPIKey(s.Context(), s.method, s.limiter); err != nil {
+		s.SetTrailer(trailer)
+		return err
+	}
+	return nil
+}
+
+// chargeAPIKey skips token subjects, which share no bucket or quota with a key of the same name
+func chargeAPIKey(ctx context.Context, fullMethod string, limiter *ratelimit.Limiter) (metadata.MD, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || principal.Method != auth.MethodAPIKey {
+		return nil, nil
+	}
+	retryAfter, err := limiter.Allow(principal.Name)
+	if err == nil {
+		return nil, nil
+	}
+
+	retryAfterSeconds := int64(math.Ceil(retryAfter.Seconds()))
+	observability.LogErrorContext(ctx, "rate-limit", "chargeAPIKey", err, map[string]interface{}{
+		"method":              fullMethod,
+		"principal":           principal.Name,
+		"retry_after_seconds": retryAfterSeconds,
+	})
+	st := status.New(codes.ResourceExhausted, err.Error())
+	if detailed, detailErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); detailErr == nil {
+		st = detailed
 	}
+	return metadata.Pairs("retry-after", strconv.FormatInt(retryAfterSeconds, 10)), st.Err()
 }
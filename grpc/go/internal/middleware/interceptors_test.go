@@ -2,15 +2,37 @@ package middleware
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"case-studies/grpc/internal/apikey"
+	"case-studies/grpc/internal/auth"
+	"case-studies/grpc/internal/config"
+	"case-studies/grpc/internal/metrics"
 	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/ratelimit"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -286,6 +308,66 @@ func TestClientLoggingInterceptor(t *testing.T) {
 	}
 }
 
+func TestClientLoggingInterceptorRequestID(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		expectedID string
+	}{
+		{
+			name: "no request ID",
+			ctx:  context.Background(),
+		},
+		{
+			name:       "request ID in the context",
+			ctx:        observability.ContextWithRequestID(context.Background(), "client-request"),
+			expectedID: "client-request",
+		},
+		{
+			name:       "x-request-id already in the metadata",
+			ctx:        metadata.AppendToOutgoingContext(context.Background(), observability.RequestIDHeader, "caller-request"),
+			expectedID: "caller-request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			var sent []string
+			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				sent = md.Get(observability.RequestIDHeader)
+				return nil
+			}
+
+			// When
+			ClientLoggingInterceptor()(tt.ctx, "/test.Service/Method", nil, nil, nil, invoker)
+
+			// Then
+			if len(sent) != 1 || sent[0] == "" {
+				t.Fatalf("Given %s, When calling, Then expected exactly one x-request-id, got %v", tt.name, sent)
+			}
+			if tt.expectedID != "" && sent[0] != tt.expectedID {
+				t.Errorf("Given %s, When calling, Then expected x-request-id %q, got %q", tt.name, tt.expectedID, sent[0])
+			}
+		})
+	}
+}
+
+// newTestAuthenticator configures each plaintext key under its own name
+func newTestAuthenticator(t *testing.T, keys ...string) *apikey.Authenticator {
+	t.Helper()
+	configs := make([]config.APIKeyConfig, 0, len(keys))
+	for _, k := range keys {
+		configs = append(configs, config.APIKeyConfig{Name: k, Key: k})
+	}
+	authenticator, err := apikey.NewAuthenticator(configs, "", nil)
+	if err != nil {
+		t.Fatalf("could not create authenticator: %v", err)
+	}
+	return authenticator
+}
+
 func TestAPIKeyAuthInterceptor(t *testing.T) {
 	observability.SetupLogger("info")
 
@@ -313,7 +395,7 @@ func TestAPIKeyAuthInterceptor(t *testing.T) {
 				err:      nil,
 			}
 
-			interceptor := APIKeyAuthInterceptor([]string{tt.apiKey})
+			interceptor := APIKeyAuthInterceptor(newTestAuthenticator(t, tt.apiKey))
 			info := &grpc.UnaryServerInfo{
 				FullMethod: tt.expectedMethod,
 			}
@@ -337,7 +419,7 @@ func TestAPIKeyAuthInterceptor(t *testing.T) {
 }
 
 func TestAPIKeyAuthInterceptorCaseSensitivity(t *testing.T) {
-	validAPIKeys := []string{"Key1", "key2", "KEY3"}
+	authenticator := newTestAuthenticator(t, "Key1", "key2", "KEY3")
 
 	tests := []struct {
 		name          string
@@ -364,7 +446,7 @@ func TestAPIKeyAuthInterceptorCaseSensitivity(t *testing.T) {
 				err:      nil,
 			}
 
-			interceptor := APIKeyAuthInterceptor(validAPIKeys)
+			interceptor := APIKeyAuthInterceptor(authenticator)
 			info := &grpc.UnaryServerInfo{
 				FullMethod: "/test.Service/Method",
 			}
@@ -383,3 +465,1124 @@ func TestAPIKeyAuthInterceptorCaseSensitivity(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIKeyAuthInterceptorPrincipal(t *testing.T) {
+	observability.SetupLogger("info")
+
+	hash, err := apikey.Hash("hashed-secret", "pepper")
+	if err != nil {
+		t.Fatalf("could not hash key: %v", err)
+	}
+	authenticator, err := apikey.NewAuthenticator([]config.APIKeyConfig{
+		{Name: "hashed", Hash: hash, Scopes: []string{"movies:write"}},
+		{Name: "expired", Key: "expired-secret", ExpiresAt: time.Now().Add(-time.Hour)},
+		{Name: "disabled", Key: "disabled-secret", Disabled: true},
+	}, "pepper", nil)
+	if err != nil {
+		t.Fatalf("could not create authenticator: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		apiKey        string
+		expectedErr   error
+		expectedName  string
+		expectedScope string
+	}{
+		{
+			name:          "hashed key",
+			apiKey:        "hashed-secret",
+			expectedName:  "hashed",
+			expectedScope: "movies:write",
+		},
+		{
+			name:        "expired key",
+			apiKey:      "expired-secret",
+			expectedErr: status.Error(codes.Unauthenticated, "invalid or missing API key"),
+		},
+		{
+			name:        "disabled key",
+			apiKey:      "disabled-secret",
+			expectedErr: status.Error(codes.Unauthenticated, "invalid or missing API key"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", tt.apiKey))
+			var principal auth.Principal
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				principal, _ = auth.FromContext(ctx)
+				return "test response", nil
+			}
+			interceptor := APIKeyAuthInterceptor(authenticator)
+
+			// When
+			_, err := interceptor(ctx, "test request", &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}, handler)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, "request with "+tt.name)
+			if principal.Name != tt.expectedName {
+				t.Errorf("Given %s, When intercepted, Then expected principal %q in the context, got %q", tt.name, tt.expectedName, principal.Name)
+			}
+			if tt.expectedScope != "" && !slices.Contains(principal.Scopes, tt.expectedScope) {
+				t.Errorf("Given %s, When intercepted, Then expected scope %s, got %v", tt.name, tt.expectedScope, principal.Scopes)
+			}
+		})
+	}
+}
+
+// newTestBearerToken returns a verifier trusting a fresh ES256 key and a token it signed for sub
+func newTestBearerToken(t *testing.T, sub string) (*auth.JWTVerifier, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks := fmt.Sprintf(`{"keys": [{"kty": "EC", "kid": "test", "crv": "P-256", "x": %q, "y": %q}]}`,
+		b64(key.X.FillBytes(make([]byte, 32))), b64(key.Y.FillBytes(make([]byte, 32))))
+	keys, err := auth.ParseJWKS([]byte(jwks))
+	if err != nil {
+		t.Fatalf("could not parse JWKS: %v", err)
+	}
+	verifier, err := auth.NewJWTVerifier(keys, auth.VerifierOptions{Issuer: "test-issuer", Audience: "movie-api"})
+	if err != nil {
+		t.Fatalf("could not create verifier: %v", err)
+	}
+
+	header := b64([]byte(`{"alg":"ES256","kid":"test"}`))
+	claims := b64([]byte(fmt.Sprintf(`{"iss":"test-issuer","aud":"movie-api","sub":%q,"exp":%d,"scope":"movies:read"}`, sub, time.Now().Add(time.Hour).Unix())))
+	digest := sha256.Sum256([]byte(header + "." + claims))
+	r, sig, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("could not sign token: %v", err)
+	}
+	signature := append(r.FillBytes(make([]byte, 32)), sig.FillBytes(make([]byte, 32))...)
+	return verifier, header + "." + claims + "." + b64(signature)
+}
+
+func TestAuthInterceptorCredentials(t *testing.T) {
+	observability.SetupLogger("info")
+
+	verifier, token := newTestBearerToken(t, "catalogue-service")
+	apiKeys := newTestAuthenticator(t, "abcd-efgh-1234-5678")
+
+	tests := []struct {
+		name           string
+		credentials    []Credential
+		metadata       metadata.MD
+		expectedErr    error
+		expectedName   string
+		expectedMethod string
+	}{
+		{
+			name:           "API key with both credentials enabled",
+			credentials:    []Credential{APIKeyCredential(apiKeys), BearerTokenCredential(verifier)},
+			metadata:       metadata.Pairs("x-api-key", "abcd-efgh-1234-5678"),
+			expectedName:   "abcd-efgh-1234-5678",
+			expectedMethod: auth.MethodAPIKey,
+		},
+		{
+			name:           "bearer token with both credentials enabled",
+			credentials:    []Credential{APIKeyCredential(apiKeys), BearerTokenCredential(verifier)},
+			metadata:       metadata.Pairs("authorization", "Bearer "+token),
+			expectedName:   "catalogue-service",
+			expectedMethod: auth.MethodJWT,
+		},
+		{
+			name:           "lowercase bearer scheme",
+			credentials:    []Credential{BearerTokenCredential(verifier)},
+			metadata:       metadata.Pairs("authorization", "bearer "+token),
+			expectedName:   "catalogue-service",
+			expectedMethod: auth.MethodJWT,
+		},
+		{
+			name:        "invalid bearer token",
+			credentials: []Credential{APIKeyCredential(apiKeys), BearerTokenCredential(verifier)},
+			metadata:    metadata.Pairs("authorization", "Bearer "+token+"x"),
+			expectedErr: status.Error(codes.Unauthenticated, "invalid or missing bearer token"),
+		},
+		{
+			name:        "API key when only bearer tokens are accepted",
+			credentials: []Credential{BearerTokenCredential(verifier)},
+			metadata:    metadata.Pairs("x-api-key", "abcd-efgh-1234-5678"),
+			expectedErr: status.Error(codes.Unauthenticated, "invalid or missing bearer token"),
+		},
+		{
+			name:        "basic authorization is not a bearer token",
+			credentials: []Credential{APIKeyCredential(apiKeys), BearerTokenCredential(verifier)},
+			metadata:    metadata.Pairs("authorization", "Basic dXNlcjpwYXNz"),
+			expectedErr: status.Error(codes.Unauthenticated, "invalid or missing API key or bearer token"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := metadata.NewIncomingContext(context.Background(), tt.metadata)
+			var principal auth.Principal
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				principal, _ = auth.FromContext(ctx)
+				return "test response", nil
+			}
+			interceptor := AuthInterceptor(tt.credentials...)
+
+			// When
+			_, err := interceptor(ctx, "test request", &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}, handler)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, tt.name)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Given %s, When intercepted, Then expected %v, got %v", tt.name, tt.expectedErr, err)
+			}
+			if principal.Name != tt.expectedName || principal.Method != tt.expectedMethod {
+				t.Errorf("Given %s, When intercepted, Then expected principal %s via %s, got %+v", tt.name, tt.expectedName, tt.expectedMethod, principal)
+			}
+		})
+	}
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	incoming []string
+	sent     []string
+	recvErr  error
+	header   metadata.MD
+	trailer  metadata.MD
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockServerStream) RecvMsg(m interface{}) error {
+	if s.recvErr != nil {
+		return s.recvErr
+	}
+	if len(s.incoming) == 0 {
+		return io.EOF
+	}
+	*(m.(*string)) = s.incoming[0]
+	s.incoming = s.incoming[1:]
+	return nil
+}
+
+func (s *mockServerStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m.(string))
+	return nil
+}
+
+func (s *mockServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *mockServerStream) SetTrailer(md metadata.MD) {
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+// echoStreamHandler replies to every received message until the client closes its side, like a bidi RPC
+func echoStreamHandler(srv interface{}, ss grpc.ServerStream) error {
+	for {
+		var in string
+		err := ss.RecvMsg(&in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := ss.SendMsg("echo " + in); err != nil {
+			return err
+		}
+	}
+}
+
+var bidiStreamInfo = &grpc.StreamServerInfo{
+	FullMethod:     "/test.Service/Stream",
+	IsClientStream: true,
+	IsServerStream: true,
+}
+
+func TestLoggingStreamInterceptor(t *testing.T) {
+	observability.SetupLogger("debug")
+
+	tests := []struct {
+		name         string
+		incoming     []string
+		recvErr      error
+		expectedSent []string
+		expectedErr  error
+	}{
+		{
+			name:         "bidi stream with messages",
+			incoming:     []string{"a", "b", "c"},
+			expectedSent: []string{"echo a", "echo b", "echo c"},
+			expectedErr:  nil,
+		},
+		{
+			name:         "bidi stream closed immediately",
+			incoming:     nil,
+			expectedSent: nil,
+			expectedErr:  nil,
+		},
+		{
+			name:         "bidi stream receive failure",
+			recvErr:      status.Error(codes.Canceled, "client went away"),
+			expectedSent: nil,
+			expectedErr:  status.Error(codes.Canceled, "client went away"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{"user-agent": "test-agent"}))
+			stream := &mockServerStream{ctx: ctx, incoming: tt.incoming, recvErr: tt.recvErr}
+			interceptor := LoggingStreamInterceptor()
+
+			// When
+			err := interceptor(nil, stream, bidiStreamInfo, echoStreamHandler)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, "stream "+tt.name)
+			if len(stream.sent) != len(tt.expectedSent) {
+				t.Fatalf("Given stream %v, When intercepted, Then expected %d sent messages, got %d", tt.incoming, len(tt.expectedSent), len(stream.sent))
+			}
+			for i := range tt.expectedSent {
+				if stream.sent[i] != tt.expectedSent[i] {
+					t.Errorf("Given stream %v, When intercepted, Then expected message %q, got %q", tt.incoming, tt.expectedSent[i], stream.sent[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoggingServerStreamCounts(t *testing.T) {
+	observability.SetupLogger("debug")
+
+	// Given
+	stream := &mockServerStream{ctx: context.Background(), incoming: []string{"a", "b"}}
+	wrapped := &loggingServerStream{ServerStream: stream, method: bidiStreamInfo.FullMethod}
+
+	// When
+	err := echoStreamHandler(nil, wrapped)
+
+	// Then
+	if err != nil {
+		t.Fatalf("Given a bidi stream, When handled, Then expected no error, got %v", err)
+	}
+	if got := wrapped.received.Load(); got != 2 {
+		t.Errorf("Given a bidi stream, When handled, Then expected 2 received messages, got %d", got)
+	}
+	if got := wrapped.sent.Load(); got != 2 {
+		t.Errorf("Given a bidi stream, When handled, Then expected 2 sent messages, got %d", got)
+	}
+}
+
+func TestErrorStreamInterceptor(t *testing.T) {
+	observability.SetupLogger("info")
+
+	tests := []struct {
+		name        string
+		err         error
+		expectedErr error
+	}{
+		{
+			name:        "successful stream",
+			err:         nil,
+			expectedErr: nil,
+		},
+		{
+			name:        "gRPC status error",
+			err:         status.Error(codes.InvalidArgument, "bad input"),
+			expectedErr: status.Error(codes.InvalidArgument, "bad input"),
+		},
+		{
+			name:        "non-gRPC error",
+			err:         errors.New("some error"),
+			expectedErr: status.Error(codes.Internal, "internal server error: some error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			stream := &mockServerStream{ctx: context.Background()}
+			handler := func(srv interface{}, ss grpc.ServerStream) error {
+				return tt.err
+			}
+			interceptor := ErrorStreamInterceptor()
+
+			// When
+			err := interceptor(nil, stream, bidiStreamInfo, handler)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, "stream "+tt.name)
+		})
+	}
+}
+
+func TestRecoveryStreamInterceptor(t *testing.T) {
+	observability.SetupLogger("info")
+
+	tests := []struct {
+		name        string
+		panic       bool
+		expectedErr error
+	}{
+		{
+			name:        "successful stream",
+			panic:       false,
+			expectedErr: nil,
+		},
+		{
+			name:        "handler panic mid-stream",
+			panic:       true,
+			expectedErr: status.Error(codes.Internal, "internal server error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			stream := &mockServerStream{ctx: context.Background(), incoming: []string{"a"}}
+			handler := func(srv interface{}, ss grpc.ServerStream) error {
+				var in string
+				if err := ss.RecvMsg(&in); err != nil {
+					return err
+				}
+				if tt.panic {
+					panic("test panic")
+				}
+				return ss.SendMsg("echo " + in)
+			}
+			interceptor := RecoveryStreamInterceptor()
+
+			// When
+			err := interceptor(nil, stream, bidiStreamInfo, handler)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, "stream "+tt.name)
+		})
+	}
+}
+
+func TestAPIKeyAuthStreamInterceptor(t *testing.T) {
+	observability.SetupLogger("info")
+
+	tests := []struct {
+		name           string
+		metadata       metadata.MD
+		expectedErr    error
+		expectedCalled bool
+	}{
+		{
+			name:           "valid API key",
+			metadata:       metadata.New(map[string]string{"x-api-key": "abcd-efgh-1234-5678"}),
+			expectedErr:    nil,
+			expectedCalled: true,
+		},
+		{
+			name:           "invalid API key",
+			metadata:       metadata.New(map[string]string{"x-api-key": "wrong-key"}),
+			expectedErr:    status.Error(codes.Unauthenticated, "invalid or missing API key"),
+			expectedCalled: false,
+		},
+		{
+			name:           "missing API key",
+			metadata:       metadata.New(map[string]string{}),
+			expectedErr:    status.Error(codes.Unauthenticated, "invalid or missing API key"),
+			expectedCalled: false,
+		},
+		{
+			name:           "missing metadata",
+			metadata:       nil,
+			expectedErr:    status.Error(codes.Unauthenticated, "missing metadata"),
+			expectedCalled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			if tt.metadata != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.metadata)
+			}
+			stream := &mockServerStream{ctx: ctx, incoming: []string{"a"}}
+			called := false
+			handler := func(srv interface{}, ss grpc.ServerStream) error {
+				called = true
+				return echoStreamHandler(srv, ss)
+			}
+			interceptor := APIKeyAuthStreamInterceptor(newTestAuthenticator(t, "abcd-efgh-1234-5678"))
+
+			// When
+			err := interceptor(nil, stream, bidiStreamInfo, handler)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, "stream "+tt.name)
+			if called != tt.expectedCalled {
+				t.Errorf("Given stream %s, When intercepted, Then expected handler called %v, got %v", tt.name, tt.expectedCalled, called)
+			}
+		})
+	}
+}
+
+func TestExemptMethods(t *testing.T) {
+	observability.SetupLogger("info")
+
+	tests := []struct {
+		name        string
+		fullMethod  string
+		expectedErr error
+	}{
+		{
+			name:       "method of an exempt service",
+			fullMethod: "/grpc.health.v1.Health/Check",
+		},
+		{
+			name:       "exempt method",
+			fullMethod: "/movie.Getter/GetMovie",
+		},
+		{
+			name:        "other method of the same service",
+			fullMethod:  "/movie.Getter/GetMovies",
+			expectedErr: status.Error(codes.Unauthenticated, "invalid or missing API key"),
+		},
+		{
+			name:        "service sharing the exempt prefix",
+			fullMethod:  "/grpc.health.v1.HealthAdmin/Check",
+			expectedErr: status.Error(codes.Unauthenticated, "invalid or missing API key"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+			authenticator := newTestAuthenticator(t, "abcd-efgh-1234-5678")
+			exempt := []string{"/grpc.health.v1.Health/", "/movie.Getter/GetMovie"}
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return "ok", nil
+			}
+			interceptor := ExemptMethods(APIKeyAuthInterceptor(authenticator), exempt...)
+			streamInterceptor := ExemptStreamMethods(APIKeyAuthStreamInterceptor(authenticator), exempt...)
+
+			// When
+			_, err := interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: tt.fullMethod}, handler)
+			streamErr := streamInterceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.fullMethod}, func(srv interface{}, ss grpc.ServerStream) error {
+				return nil
+			})
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, tt.name)
+			assertGRPCError(t, streamErr, tt.expectedErr, "stream "+tt.name)
+			if called != (tt.expectedErr == nil) {
+				t.Errorf("Given %s, When intercepted, Then expected handler called %v, got %v", tt.name, tt.expectedErr == nil, called)
+			}
+		})
+	}
+}
+
+func TestAPIKeyScopeInterceptor(t *testing.T) {
+	observability.SetupLogger("info")
+
+	keyScopes := map[string][]string{
+		"reader-key": {"movies:read"},
+		"editor-key": {"movies:read", "movies:write"},
+	}
+	methodScopes := map[string]string{
+		"/movie.Getter/":                 "movies:read",
+		"/movie.MovieAdmin/":             "movies:write",
+		"/movie.MovieAdmin/GetVersioned": "movies:read",
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		apiKey         string
+		expectedErr    error
+		expectedCalled bool
+	}{
+		{
+			name:           "read method with read scope",
+			method:         "/movie.Getter/GetMovie",
+			apiKey:         "reader-key",
+			expectedCalled: true,
+		},
+		{
+			name:           "write method without write scope",
+			method:         "/movie.MovieAdmin/CreateMovie",
+			apiKey:         "reader-key",
+			expectedErr:    status.Error(codes.PermissionDenied, "API key lacks the movies:write scope"),
+			expectedCalled: false,
+		},
+		{
+			name:           "write method with write scope",
+			method:         "/movie.MovieAdmin/CreateMovie",
+			apiKey:         "editor-key",
+			expectedCalled: true,
+		},
+		{
+			name:           "exact method overrides service scope",
+			method:         "/movie.MovieAdmin/GetVersioned",
+			apiKey:         "reader-key",
+			expectedCalled: true,
+		},
+		{
+			name:           "method without required scope",
+			method:         "/grpc.health.v1.Health/Check",
+			apiKey:         "",
+			expectedCalled: true,
+		},
+		{
+			name:           "scoped method without API key",
+			method:         "/movie.Getter/GetMovie",
+			apiKey:         "",
+			expectedErr:    status.Error(codes.Unauthenticated, "invalid or missing API key"),
+			expectedCalled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			if tt.apiKey != "" {
+				ctx = auth.NewContext(ctx, auth.Principal{Name: tt.apiKey, Scopes: keyScopes[tt.apiKey]})
+			}
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return "test response", nil
+			}
+			interceptor := APIKeyScopeInterceptor(methodScopes)
+
+			// When
+			_, err := interceptor(ctx, "test request", &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, "method "+tt.name)
+			if called != tt.expectedCalled {
+				t.Errorf("Given %s, When intercepted, Then expected handler called %v, got %v", tt.name, tt.expectedCalled, called)
+			}
+		})
+	}
+}
+
+func TestAPIKeyScopeStreamInterceptor(t *testing.T) {
+	observability.SetupLogger("info")
+
+	tests := []struct {
+		name           string
+		scopes         []string
+		expectedErr    error
+		expectedCalled bool
+	}{
+		{
+			name:           "key with scope",
+			scopes:         []string{"movies:read"},
+			expectedCalled: true,
+		},
+		{
+			name:           "key without scope",
+			scopes:         []string{},
+			expectedErr:    status.Error(codes.PermissionDenied, "API key lacks the movies:read scope"),
+			expectedCalled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := auth.NewContext(context.Background(), auth.Principal{Name: "test-key", Scopes: tt.scopes})
+			stream := &mockServerStream{ctx: ctx, incoming: []string{"a"}}
+			called := false
+			handler := func(srv interface{}, ss grpc.ServerStream) error {
+				called = true
+				return echoStreamHandler(srv, ss)
+			}
+			interceptor := APIKeyScopeStreamInterceptor(map[string]string{"/test.Service/": "movies:read"})
+
+			// When
+			err := interceptor(nil, stream, bidiStreamInfo, handler)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, "stream "+tt.name)
+			if called != tt.expectedCalled {
+				t.Errorf("Given stream %s, When intercepted, Then expected handler called %v, got %v", tt.name, tt.expectedCalled, called)
+			}
+		})
+	}
+}
+
+// newTestPeerContext returns a context for a connection whose client certificate was verified with commonName
+func newTestPeerContext(commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	tlsInfo := credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: tlsInfo})
+}
+
+func TestPeerPolicyInterceptor(t *testing.T) {
+	observability.SetupLogger("info")
+
+	policy, err := auth.NewPeerPolicy([]auth.PeerPolicyRule{
+		{Identity: "cn:movie-admin", Methods: []string{"/movie.MovieAdmin/"}},
+		{Identity: "*", Methods: []string{"/movie.Getter/"}},
+	})
+	if err != nil {
+		t.Fatalf("could not create policy: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		method         string
+		expectedErr    error
+		expectedCalled bool
+	}{
+		{
+			name:           "admin certificate calling a write method",
+			ctx:            newTestPeerContext("movie-admin"),
+			method:         "/movie.MovieAdmin/CreateMovie",
+			expectedCalled: true,
+		},
+		{
+			name:           "client certificate calling a read method",
+			ctx:            newTestPeerContext("grpc-client"),
+			method:         "/movie.Getter/GetMovie",
+			expectedCalled: true,
+		},
+		{
+			name:        "client certificate calling a write method",
+			ctx:         newTestPeerContext("grpc-client"),
+			method:      "/movie.MovieAdmin/CreateMovie",
+			expectedErr: status.Error(codes.PermissionDenied, "client certificate grpc-client may not call /movie.MovieAdmin/CreateMovie"),
+		},
+		{
+			name:        "connection without a verified certificate",
+			ctx:         context.Background(),
+			method:      "/movie.Getter/GetMovie",
+			expectedErr: status.Error(codes.PermissionDenied, "a verified client certificate is required"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return "test response", nil
+			}
+			interceptor := PeerPolicyInterceptor(policy)
+
+			// When
+			_, err := interceptor(tt.ctx, "test request", &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, tt.name)
+			if tt.expectedErr != nil && status.Convert(err).Message() != status.Convert(tt.expectedErr).Message() {
+				t.Errorf("Given %s, When intercepted, Then expected message %q, got %q", tt.name, status.Convert(tt.expectedErr).Message(), status.Convert(err).Message())
+			}
+			if called != tt.expectedCalled {
+				t.Errorf("Given %s, When intercepted, Then expected handler called %v, got %v", tt.name, tt.expectedCalled, called)
+			}
+		})
+	}
+}
+
+func TestPeerPolicyStreamInterceptor(t *testing.T) {
+	observability.SetupLogger("info")
+
+	policy, err := auth.NewPeerPolicy([]auth.PeerPolicyRule{{Identity: "cn:movie-admin", Methods: []string{"/test.Service/Stream"}}})
+	if err != nil {
+		t.Fatalf("could not create policy: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		commonName     string
+		expectedErr    error
+		expectedCalled bool
+	}{
+		{
+			name:           "allowed certificate",
+			commonName:     "movie-admin",
+			expectedCalled: true,
+		},
+		{
+			name:        "refused certificate",
+			commonName:  "grpc-client",
+			expectedErr: status.Error(codes.PermissionDenied, "client certificate grpc-client may not call /test.Service/Stream"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			stream := &mockServerStream{ctx: newTestPeerContext(tt.commonName), incoming: []string{"a"}}
+			called := false
+			handler := func(srv interface{}, ss grpc.ServerStream) error {
+				called = true
+				return echoStreamHandler(srv, ss)
+			}
+			interceptor := PeerPolicyStreamInterceptor(policy)
+
+			// When
+			err := interceptor(nil, stream, bidiStreamInfo, handler)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, "stream "+tt.name)
+			if called != tt.expectedCalled {
+				t.Errorf("Given stream %s, When intercepted, Then expected handler called %v, got %v", tt.name, tt.expectedCalled, called)
+			}
+		})
+	}
+}
+
+// scrapeMetrics returns the exposition text served by m
+func scrapeMetrics(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	return recorder.Body.String()
+}
+
+func TestMetricsInterceptor(t *testing.T) {
+	// Given
+	m := metrics.New("grpc")
+	interceptor := MetricsInterceptor(m)
+	info := &grpc.UnaryServerInfo{FullMethod: "/movie.Getter/GetMovie"}
+	found := &mockHandler{response: "test response"}
+	missing := &mockHandler{err: status.Error(codes.NotFound, "movie not found")}
+
+	// When
+	interceptor(context.Background(), "test request", info, found.handle)
+	interceptor(context.Background(), "test request", info, missing.handle)
+	interceptor(context.Background(), "test request", info, missing.handle)
+	output := scrapeMetrics(t, m)
+
+	// Then
+	for _, expected := range []string{
+		`grpc_server_requests_total{code="OK",method="/movie.Getter/GetMovie"} 1`,
+		`grpc_server_requests_total{code="NotFound",method="/movie.Getter/GetMovie"} 2`,
+		`grpc_server_request_duration_seconds_count{code="NotFound",method="/movie.Getter/GetMovie"} 2`,
+		`grpc_server_requests_in_flight{method="/movie.Getter/GetMovie"} 0`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Given three calls, When scraping, Then expected %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestMetricsStreamInterceptor(t *testing.T) {
+	// Given
+	m := metrics.New("grpc")
+	stream := &mockServerStream{ctx: context.Background(), incoming: []string{"a", "b"}}
+
+	// When
+	err := MetricsStreamInterceptor(m)(nil, stream, bidiStreamInfo, echoStreamHandler)
+
+	// Then
+	assertGRPCError(t, err, nil, "a metered stream")
+	expected := `grpc_server_requests_total{code="OK",method="/test.Service/Stream"} 1`
+	if output := scrapeMetrics(t, m); !strings.Contains(output, expected) {
+		t.Errorf("Given a stream with two messages, When scraping, Then expected %q, got:\n%s", expected, output)
+	}
+}
+
+func TestAPIKeyUsageInterceptor(t *testing.T) {
+	// Given
+	m := metrics.New("grpc")
+	interceptor := APIKeyUsageInterceptor(m)
+	info := &grpc.UnaryServerInfo{FullMethod: "/movie.Getter/GetMovie"}
+	handler := &mockHandler{response: "test response"}
+	apiKeyCtx := auth.NewContext(context.Background(), auth.Principal{Name: "test-key", Method: auth.MethodAPIKey})
+	tokenCtx := auth.NewContext(context.Background(), auth.Principal{Name: "catalogue-service", Method: auth.MethodJWT})
+
+	// When
+	interceptor(apiKeyCtx, "test request", info, handler.handle)
+	interceptor(apiKeyCtx, "test request", info, handler.handle)
+	interceptor(tokenCtx, "test request", info, handler.handle)
+	APIKeyUsageStreamInterceptor(m)(nil, &mockServerStream{ctx: apiKeyCtx}, bidiStreamInfo, echoStreamHandler)
+	output := scrapeMetrics(t, m)
+
+	// Then
+	if expected := `movie_api_key_requests_total{api_key="test-key"} 3`; !strings.Contains(output, expected) {
+		t.Errorf("Given two calls and a stream with test-key, When scraping, Then expected %q, got:\n%s", expected, output)
+	}
+	if strings.Contains(output, "catalogue-service") {
+		t.Errorf("Given a call with a bearer token, When scraping, Then expected no per-subject series, got:\n%s", output)
+	}
+}
+
+func TestChainedStreamInterceptors(t *testing.T) {
+	observability.SetupLogger("debug")
+
+	// Given
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{"x-api-key": "abcd-efgh-1234-5678"}))
+	stream := &mockServerStream{ctx: ctx, incoming: []string{"a", "b"}}
+	interceptors := []grpc.StreamServerInterceptor{
+		APIKeyAuthStreamInterceptor(newTestAuthenticator(t, "abcd-efgh-1234-5678")),
+		LoggingStreamInterceptor(),
+		ErrorStreamInterceptor(),
+		RecoveryStreamInterceptor(),
+	}
+	handler := grpc.StreamHandler(func(srv interface{}, ss grpc.ServerStream) error {
+		if err := echoStreamHandler(srv, ss); err != nil {
+			return err
+		}
+		panic("test panic after echo")
+	})
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(srv interface{}, ss grpc.ServerStream) error {
+			return interceptor(srv, ss, bidiStreamInfo, next)
+		}
+	}
+
+	// When
+	err := handler(nil, stream)
+
+	// Then
+	assertGRPCError(t, err, status.Error(codes.Internal, "internal server error"), "chained bidi stream")
+	if len(stream.sent) != 2 {
+		t.Errorf("Given a chained bidi stream, When handled, Then expected 2 sent messages, got %d", len(stream.sent))
+	}
+}
+
+// mockTransportStream captures the header and trailer a unary interceptor sets through grpc.SetHeader and grpc.SetTrailer
+type mockTransportStream struct {
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *mockTransportStream) Method() string { return "/test.Service/Method" }
+func (s *mockTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+func (s *mockTransportStream) SendHeader(md metadata.MD) error { return nil }
+func (s *mockTransportStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	observability.SetupLogger("info")
+
+	tests := []struct {
+		name               string
+		apiKey             string
+		method             string
+		calls              int
+		expectedErr        error
+		expectedRetryAfter string
+	}{
+		{
+			name:   "within the limit",
+			apiKey: "limited-key",
+			calls:  2,
+		},
+		{
+			name:               "over the rate limit",
+			apiKey:             "limited-key",
+			calls:              3,
+			expectedErr:        status.Error(codes.ResourceExhausted, "rate limit exceeded"),
+			expectedRetryAfter: "2",
+		},
+		{
+			name:   "key without limits",
+			apiKey: "unlimited-key",
+			calls:  10,
+		},
+		{
+			name:   "token subject named like a limited key",
+			apiKey: "limited-key",
+			method: auth.MethodJWT,
+			calls:  10,
+		},
+		{
+			name:  "unauthenticated call is left to authentication",
+			calls: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			limiter := ratelimit.NewLimiter(map[string]ratelimit.Limits{
+				"limited-key": {RequestsPerSecond: 0.5, Burst: 2},
+			}, func() time.Time { return time.Unix(0, 0) })
+			interceptor := RateLimitInterceptor(limiter)
+			info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+			handler := &mockHandler{response: "test response"}
+			method := tt.method
+			if method == "" {
+				method = auth.MethodAPIKey
+			}
+			for i := 0; i < tt.calls-1; i++ {
+				ctx := context.Background()
+				if tt.apiKey != "" {
+					ctx = auth.NewContext(ctx, auth.Principal{Name: tt.apiKey, Method: method})
+				}
+				if _, err := interceptor(ctx, "test request", info, handler.handle); err != nil {
+					t.Fatalf("Given %s, When sending call %d, Then expected it to be allowed, got %v", tt.name, i+1, err)
+				}
+			}
+			transport := &mockTransportStream{}
+			ctx := grpc.NewContextWithServerTransportStream(context.Background(), transport)
+			if tt.apiKey != "" {
+				ctx = auth.NewContext(ctx, auth.Principal{Name: tt.apiKey, Method: method})
+			}
+
+			// When
+			_, err := interceptor(ctx, "test request", info, handler.handle)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, "call "+tt.name)
+			if got := transport.trailer.Get("retry-after"); tt.expectedRetryAfter != "" && (len(got) != 1 || got[0] != tt.expectedRetryAfter) {
+				t.Errorf("Given %s, When intercepted, Then expected retry-after %s, got %v", tt.name, tt.expectedRetryAfter, got)
+			}
+			if tt.expectedErr != nil {
+				assertRetryInfo(t, err, 2*time.Second, tt.name)
+			}
+		})
+	}
+}
+
+func assertRetryInfo(t *testing.T, err error, expected time.Duration, context string) {
+	t.Helper()
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			if got := info.GetRetryDelay().AsDuration(); got != expected {
+				t.Errorf("Given %s, When intercepted, Then expected a retry delay of %v, got %v", context, expected, got)
+			}
+			return
+		}
+	}
+	t.Errorf("Given %s, When intercepted, Then expected a RetryInfo detail, got none", context)
+}
+
+func TestRateLimitStreamInterceptor(t *testing.T) {
+	observability.SetupLogger("info")
+
+	tests := []struct {
+		name          string
+		incoming      []string
+		expectedErr   error
+		expectedSent  int
+		expectTrailer bool
+	}{
+		{
+			name:         "messages within the limit",
+			incoming:     []string{"a", "b"},
+			expectedSent: 2,
+		},
+		{
+			name:          "messages over the limit",
+			incoming:      []string{"a", "b", "c", "d"},
+			expectedErr:   status.Error(codes.ResourceExhausted, "rate limit exceeded"),
+			expectedSent:  2,
+			expectTrailer: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			limiter := ratelimit.NewLimiter(map[string]ratelimit.Limits{
+				"test-key": {RequestsPerSecond: 0.5, Burst: 2},
+			}, func() time.Time { return time.Unix(0, 0) })
+			ctx := auth.NewContext(context.Background(), auth.Principal{Name: "test-key", Method: auth.MethodAPIKey})
+			stream := &mockServerStream{ctx: ctx, incoming: tt.incoming}
+			interceptor := RateLimitStreamInterceptor(limiter)
+
+			// When
+			err := interceptor(nil, stream, bidiStreamInfo, echoStreamHandler)
+
+			// Then
+			assertGRPCError(t, err, tt.expectedErr, "stream "+tt.name)
+			if len(stream.sent) != tt.expectedSent {
+				t.Errorf("Given stream %s, When intercepted, Then expected %d sent messages, got %d", tt.name, tt.expectedSent, len(stream.sent))
+			}
+			if got := len(stream.trailer.Get("retry-after")) > 0; got != tt.expectTrailer {
+				t.Errorf("Given stream %s, When intercepted, Then expected retry-after trailer %v, got %v", tt.name, tt.expectTrailer, got)
+			}
+		})
+	}
+}
+
+func TestRequestIDInterceptor(t *testing.T) {
+	tests := []struct {
+		name       string
+		incoming   []string
+		expectedID string
+	}{
+		{name: "caller request ID", incoming: []string{"abc-123"}, expectedID: "abc-123"},
+		{name: "no request ID"},
+		{name: "request ID with a newline", incoming: []string{"abc\n{\"level\":\"ERROR\"}"}},
+		{name: "oversized request ID", incoming: []string{strings.Repeat("a", maxRequestIDLength+1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			md := metadata.MD{}
+			if tt.incoming != nil {
+				md.Set(observability.RequestIDHeader, tt.incoming...)
+			}
+			transport := &mockTransportStream{}
+			ctx := grpc.NewContextWithServerTransportStream(metadata.NewIncomingContext(context.Background(), md), transport)
+			var handlerID string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handlerID, _ = observability.RequestIDFromContext(ctx)
+				return nil, nil
+			}
+
+			// When
+			RequestIDInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}, handler)
+
+			// Then
+			if !ValidRequestID(handlerID) {
+				t.Fatalf("Given %s, When intercepted, Then expected a usable request ID in the context, got %q", tt.name, handlerID)
+			}
+			if tt.expectedID != "" && handlerID != tt.expectedID {
+				t.Errorf("Given %s, When intercepted, Then expected request ID %q, got %q", tt.name, tt.expectedID, handlerID)
+			}
+			if echoed := transport.header.Get(observability.RequestIDHeader); len(echoed) != 1 || echoed[0] != handlerID {
+				t.Errorf("Given %s, When intercepted, Then expected %q echoed in the response header, got %v", tt.name, handlerID, echoed)
+			}
+		})
+	}
+}
+
+func TestRequestIDStreamInterceptor(t *testing.T) {
+	// Given
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(observability.RequestIDHeader, "stream-123"))
+	stream := &mockServerStream{ctx: ctx}
+	var handlerID string
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		handlerID, _ = observability.RequestIDFromContext(ss.Context())
+		return nil
+	}
+
+	// When
+	RequestIDStreamInterceptor()(nil, stream, bidiStreamInfo, handler)
+
+	// Then
+	if handlerID != "stream-123" {
+		t.Errorf("Given a stream with x-request-id, When intercepted, Then expected the handler to see it, got %q", handlerID)
+	}
+	if echoed := stream.header.Get(observability.RequestIDHeader); len(echoed) != 1 || echoed[0] != "stream-123" {
+		t.Errorf("Given a stream with x-request-id, When intercepted, Then expected it echoed in the response header, got %v", echoed)
+	}
+}
@@ -0,0 +1,152 @@
+package movie
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	moviepb "case-studies/grpc/cmd/movie"
+)
+
+type ChangeType int
+
+const (
+	ChangeAdded ChangeType = iota + 1
+	ChangeModified
+	ChangeDeleted
+)
+
+var ErrRevisionUnavailable = errors.New("revision is not available in the change history")
+
+// Change is one movie's transition between two versions of the catalogue.
+// Previous is nil for added movies and Current is nil for deleted ones.
+type Change struct {
+	Revision int64
+	Type     ChangeType
+	Previous *moviepb.Movie
+	Current  *moviepb.Movie
+}
+
+// Movie returns the movie as of the change, or as it was before deletion
+func (c Change) Movie() *moviepb.Movie {
+	if c.Current != nil {
+		return c.Current
+	}
+	return c.Previous
+}
+
+// ForMinimumRating rewrites the change as seen by a watcher of movies rated at least minRating.
+// Movies crossing the threshold appear added or deleted; changes entirely below it are dropped.
+func (c Change) ForMinimumRating(minRating float32) (Change, bool) {
+	wasVisible := c.Previous != nil && c.Previous.GetRatingsScore() >= minRating
+	isVisible := c.Current != nil && c.Current.GetRatingsScore() >= minRating
+
+	switch {
+	case wasVisible && isVisible:
+		c.Type = ChangeModified
+	case isVisible:
+		c.Type = ChangeAdded
+	case wasVisible:
+		c.Type = ChangeDeleted
+		c.Current = nil
+	default:
+		return Change{}, false
+	}
+	return c, true
+}
+
+// ChangeFeed numbers catalogue changes with a monotonically increasing revision and keeps
+// the most recent ones so watchers can resume after reconnecting. Revisions restart at
+// zero with the process, so each feed has a random epoch telling its revisions apart.
+type ChangeFeed struct {
+	epoch       string
+	mu          sync.Mutex
+	revision    int64
+	history     []Change
+	historySize int
+	// Closed and replaced on every publish to wake waiting watchers
+	published chan struct{}
+}
+
+func NewChangeFeed(historySize int) (*ChangeFeed, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("could not generate change feed epoch: %w", err)
+	}
+	return &ChangeFeed{epoch: hex.EncodeToString(b), historySize: historySize, published: make(chan struct{})}, nil
+}
+
+// Epoch identifies this feed's revisions, which mean nothing to a feed with another epoch
+func (f *ChangeFeed) Epoch() string {
+	return f.epoch
+}
+
+// Publish records the differences between two versions of the catalogue, returning the latest revision
+func (f *ChangeFeed) Publish(previous, next []*moviepb.Movie) int64 {
+	previousByID := make(map[string]*moviepb.Movie, len(previous))
+	for _, m := range previous {
+		previousByID[m.GetMovieId()] = m
+	}
+	nextByID := make(map[string]*moviepb.Movie, len(next))
+	for _, m := range next {
+		nextByID[m.GetMovieId()] = m
+	}
+	diff := DiffMovies(previous, next)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if diff.IsEmpty() {
+		return f.revision
+	}
+
+	record := func(changeType ChangeType, before, after *moviepb.Movie) {
+		f.revision++
+		f.history = append(f.history, Change{Revision: f.revision, Type: changeType, Previous: before, Current: after})
+	}
+	for _, id := range diff.Added {
+		record(ChangeAdded, nil, nextByID[id])
+	}
+	for _, id := range diff.Changed {
+		record(ChangeModified, previousByID[id], nextByID[id])
+	}
+	for _, id := range diff.Removed {
+		record(ChangeDeleted, previousByID[id], nil)
+	}
+
+	if len(f.history) > f.historySize {
+		f.history = append([]Change(nil), f.history[len(f.history)-f.historySize:]...)
+	}
+
+	close(f.published)
+	f.published = make(chan struct{})
+	return f.revision
+}
+
+func (f *ChangeFeed) Revision() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.revision
+}
+
+// Since returns the changes after revision and a channel that is closed when more are published.
+// It returns ErrRevisionUnavailable if the revision is newer than the feed or has been compacted.
+func (f *ChangeFeed) Since(revision int64) ([]Change, <-chan struct{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if revision > f.revision || revision < 0 {
+		return nil, nil, ErrRevisionUnavailable
+	}
+	if revision == f.revision {
+		return nil, f.published, nil
+	}
+	if len(f.history) == 0 || f.history[0].Revision > revision+1 {
+		return nil, nil, ErrRevisionUnavailable
+	}
+
+	start := int(revision + 1 - f.history[0].Revision)
+	return append([]Change(nil), f.history[start:]...), f.published, nil
+}
@@ -0,0 +1,183 @@
+package movie
+
+import (
+	"errors"
+	"testing"
+
+	moviepb "case-studies/grpc/cmd/movie"
+)
+
+func changeRevisions(changes []Change) []int64 {
+	revisions := make([]int64, 0, len(changes))
+	for _, c := range changes {
+		revisions = append(revisions, c.Revision)
+	}
+	return revisions
+}
+
+func newTestChangeFeed(t *testing.T, historySize int) *ChangeFeed {
+	t.Helper()
+	feed, err := NewChangeFeed(historySize)
+	if err != nil {
+		t.Fatalf("could not create change feed: %v", err)
+	}
+	return feed
+}
+
+func TestChangeFeedPublish(t *testing.T) {
+	// Given
+	feed := newTestChangeFeed(t, 10)
+	previous := testMovies()
+	next := testMovies()[1:]
+	next[0].Title = "Lunar Glow Redux"
+	next = append(next, &moviepb.Movie{MovieId: "tt0000004", RatingsScore: 7})
+
+	// When
+	revision := feed.Publish(previous, next)
+
+	// Then
+	if revision != 3 {
+		t.Fatalf("Given one added, one modified and one deleted movie, When publishing, Then expected revision 3, got %d", revision)
+	}
+	changes, _, err := feed.Since(0)
+	if err != nil {
+		t.Fatalf("Given a published feed, When reading from 0, Then expected no error, got %v", err)
+	}
+	expected := []struct {
+		changeType ChangeType
+		movieID    string
+	}{
+		{ChangeAdded, "tt0000004"},
+		{ChangeModified, "tt0000002"},
+		{ChangeDeleted, "tt0000001"},
+	}
+	for i, e := range expected {
+		if changes[i].Type != e.changeType || changes[i].Movie().GetMovieId() != e.movieID {
+			t.Errorf("Given change %d, When reading, Then expected %v %s, got %v %s", i, e.changeType, e.movieID, changes[i].Type, changes[i].Movie().GetMovieId())
+		}
+	}
+	if feed.Publish(next, next) != 3 {
+		t.Errorf("Given an unchanged catalogue, When publishing, Then expected the revision to stay at 3")
+	}
+}
+
+func TestChangeFeedSince(t *testing.T) {
+	// Given a feed of 5 single-movie additions that keeps the last 3
+	feed := newTestChangeFeed(t, 3)
+	var movies []*moviepb.Movie
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		next := append(append([]*moviepb.Movie(nil), movies...), &moviepb.Movie{MovieId: id})
+		feed.Publish(movies, next)
+		movies = next
+	}
+
+	tests := []struct {
+		name     string
+		revision int64
+		expected []int64
+		wantErr  bool
+	}{
+		{"latest revision", 5, []int64{}, false},
+		{"within history", 3, []int64{4, 5}, false},
+		{"oldest resumable revision", 2, []int64{3, 4, 5}, false},
+		{"compacted revision", 1, nil, true},
+		{"future revision", 6, nil, true},
+		{"negative revision", -1, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			changes, _, err := feed.Since(tt.revision)
+
+			// Then
+			if tt.wantErr {
+				if !errors.Is(err, ErrRevisionUnavailable) {
+					t.Errorf("Given revision %d, When reading, Then expected ErrRevisionUnavailable, got %v", tt.revision, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Given revision %d, When reading, Then expected no error, got %v", tt.revision, err)
+			}
+			got := changeRevisions(changes)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Given revision %d, When reading, Then expected %v, got %v", tt.revision, tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Given revision %d, When reading, Then expected %v, got %v", tt.revision, tt.expected, got)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestChangeFeedNotifiesWatchers(t *testing.T) {
+	// Given
+	feed := newTestChangeFeed(t, 10)
+	_, published, err := feed.Since(0)
+	if err != nil {
+		t.Fatalf("could not read feed: %v", err)
+	}
+
+	// When
+	feed.Publish(nil, testMovies())
+
+	// Then
+	select {
+	case <-published:
+	default:
+		t.Errorf("Given a waiting watcher, When publishing, Then expected the published channel to be closed")
+	}
+}
+
+func TestChangeFeedEpoch(t *testing.T) {
+	// Given
+	feed := newTestChangeFeed(t, 10)
+
+	// When
+	restarted := newTestChangeFeed(t, 10)
+
+	// Then
+	if feed.Epoch() == "" || feed.Epoch() == restarted.Epoch() {
+		t.Errorf("Given two feeds, When comparing epochs, Then expected distinct non-empty epochs, got %q and %q", feed.Epoch(), restarted.Epoch())
+	}
+}
+
+func TestChangeForMinimumRating(t *testing.T) {
+	low := &moviepb.Movie{MovieId: "tt0000001", RatingsScore: 5}
+	high := &moviepb.Movie{MovieId: "tt0000001", RatingsScore: 9}
+
+	tests := []struct {
+		name         string
+		change       Change
+		expectedType ChangeType
+		expectedOK   bool
+	}{
+		{"added above threshold", Change{Type: ChangeAdded, Current: high}, ChangeAdded, true},
+		{"added below threshold", Change{Type: ChangeAdded, Current: low}, 0, false},
+		{"rising across threshold", Change{Type: ChangeModified, Previous: low, Current: high}, ChangeAdded, true},
+		{"falling across threshold", Change{Type: ChangeModified, Previous: high, Current: low}, ChangeDeleted, true},
+		{"modified above threshold", Change{Type: ChangeModified, Previous: high, Current: high}, ChangeModified, true},
+		{"modified below threshold", Change{Type: ChangeModified, Previous: low, Current: low}, 0, false},
+		{"deleted above threshold", Change{Type: ChangeDeleted, Previous: high}, ChangeDeleted, true},
+		{"deleted below threshold", Change{Type: ChangeDeleted, Previous: low}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			visible, ok := tt.change.ForMinimumRating(8)
+
+			// Then
+			if ok != tt.expectedOK || visible.Type != tt.expectedType {
+				t.Errorf("Given %s, When filtering at 8, Then expected (%v, %v), got (%v, %v)", tt.name, tt.expectedType, tt.expectedOK, visible.Type, ok)
+			}
+			if ok && visible.Type == ChangeDeleted && visible.Movie() != high {
+				t.Errorf("Given %s, When filtering at 8, Then expected the deleted event to carry the last visible movie", tt.name)
+			}
+		})
+	}
+}
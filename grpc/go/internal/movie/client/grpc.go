@@ -4,16 +4,17 @@ import (
 	"case-studies/grpc/internal/config"
 	"case-studies/grpc/internal/middleware"
 	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/tlsconfig"
 	"case-studies/grpc/internal/validation"
 	"crypto/tls"
-	"crypto/x509"
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func LoadConfig() *config.MovieClientConfig {
@@ -23,6 +24,13 @@ func LoadConfig() *config.MovieClientConfig {
 	flagAPIKey := flag.String("api-key", "", "API key for authentication (overrides X_API_KEY env var)")
 	flagLogLevel := flag.String("log-level", config.DefaultLogLevel, "Log level (debug, info, warn, error)")
 	flagEnvironment := flag.String("environment", "", "Environment (development, staging, production)")
+	flagTLSMode := flag.String("tls-mode", "", "How to secure the connection (mtls, tls, insecure); defaults to TLS_MODE or mtls")
+	flagTLSCertFile := flag.String("tls-cert-file", "", "Client certificate, relative to the assets file path (defaults to tls/client-public.key)")
+	flagTLSKeyFile := flag.String("tls-key-file", "", "Client private key, relative to the assets file path (defaults to tls/client-private.key)")
+	flagTLSCAFile := flag.String("tls-ca-file", "", "CA certificates verifying the server, relative to the assets file path (defaults to tls/ca-public.key)")
+	flagTLSServerName := flag.String("tls-server-name", "", "Name to verify the server certificate against (defaults to the host)")
+	flagTLSMinVersion := flag.String("tls-min-version", "", "Minimum TLS version (1.2, 1.3); defaults to TLS_MIN_VERSION or 1.2")
+	flagTLSCipherSuites := flag.String("tls-cipher-suites", "", "Comma-separated TLS 1.2 cipher suites (defaults to Go's)")
 
 	flag.Parse()
 
@@ -53,6 +61,30 @@ func LoadConfig() *config.MovieClientConfig {
 		}
 	}
 
+	// Read client_tls from the assets file path the flags settled on
+	baseConfig.TLS = config.LoadClientTLSConfig(baseConfig.AssetsFilePath)
+	if *flagTLSMode != "" {
+		baseConfig.TLS.Mode = *flagTLSMode
+	}
+	if *flagTLSCertFile != "" {
+		baseConfig.TLS.CertFile = *flagTLSCertFile
+	}
+	if *flagTLSKeyFile != "" {
+		baseConfig.TLS.KeyFile = *flagTLSKeyFile
+	}
+	if *flagTLSCAFile != "" {
+		baseConfig.TLS.CAFile = *flagTLSCAFile
+	}
+	if *flagTLSServerName != "" {
+		baseConfig.TLS.ServerName = *flagTLSServerName
+	}
+	if *flagTLSMinVersion != "" {
+		baseConfig.TLS.MinVersion = *flagTLSMinVersion
+	}
+	if *flagTLSCipherSuites != "" {
+		baseConfig.TLS.CipherSuites = config.SplitList(*flagTLSCipherSuites)
+	}
+
 	if err := validation.ValidateHost(baseConfig.Host); err != nil {
 		observability.LogError("config-validation", "LoadConfig", err, map[string]interface{}{
 			"field": "host",
@@ -85,54 +117,108 @@ func LoadConfig() *config.MovieClientConfig {
 		})
 		os.Exit(1)
 	}
+	if err := validation.ValidateTracesExporter(baseConfig.Tracing.TracesExporter); err != nil {
+		observability.LogError("config-validation", "LoadConfig", err, map[string]interface{}{
+			"field": "traces_exporter",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateTLSMode(baseConfig.TLS.Mode); err != nil {
+		observability.LogError("config-validation", "LoadConfig", err, map[string]interface{}{
+			"field": "tls_mode",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateTLSProtocol(baseConfig.TLS.MinVersion, baseConfig.TLS.CipherSuites); err != nil {
+		observability.LogError("config-validation", "LoadConfig", err, map[string]interface{}{
+			"field": "tls",
+		})
+		os.Exit(1)
+	}
+	if err := validation.ValidateTLSReload(baseConfig.TLS.ReloadInterval, baseConfig.TLS.ExpiryWarning); err != nil {
+		observability.LogError("config-validation", "LoadConfig", err, map[string]interface{}{
+			"field": "tls",
+		})
+		os.Exit(1)
+	}
 
 	return baseConfig
 }
 
-func CreateGRPCConnection(cfg *config.MovieClientConfig) (*grpc.ClientConn, error) {
-	serverURL := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
-
-	observability.LogSuccess("grpc-connect", "CreateGRPCConnection", map[string]interface{}{
-		"server": serverURL,
-	})
-
-	certFilePath := filepath.Join(cfg.AssetsFilePath, "tls", "client-public.key")
-	keyFilePath := filepath.Join(cfg.AssetsFilePath, "tls", "client-private.key")
+// LoadTLSConfig reads the CA that signs the server's certificate and, in mtls mode, the client
+// certificate, from tlsCfg or the tls directory under assetsFilePath. The config keeps the CA pool
+// read now; TransportCredentials also picks up a rotated CA on reconnection.
+func LoadTLSConfig(assetsFilePath string, tlsCfg config.TLSConfig) (*tls.Config, error) {
+	certificates, err := loadCertificates(assetsFilePath, tlsCfg)
+	if err != nil {
+		return nil, err
+	}
+	return certificates.ClientConfig(tlsCfg.ServerName), nil
+}
 
-	cert, err := tls.LoadX509KeyPair(certFilePath, keyFilePath)
+// TransportCredentials secures the connection as tlsCfg.Mode asks, sending plaintext in insecure
+// mode. Reconnections pick up rotated files once tlsCfg.ReloadInterval has passed.
+func TransportCredentials(assetsFilePath string, tlsCfg config.TLSConfig) (credentials.TransportCredentials, error) {
+	if tlsCfg.Mode == config.TLSModeInsecure {
+		return insecure.NewCredentials(), nil
+	}
+	certificates, err := loadCertificates(assetsFilePath, tlsCfg)
 	if err != nil {
-		observability.LogError("tls-load", "CreateGRPCConnection", err, map[string]interface{}{
-			"cert_file": certFilePath,
-			"key_file":  keyFilePath,
-		})
 		return nil, err
 	}
-	// Load the CA certificate for server verification
-	caCertPath := filepath.Join(cfg.AssetsFilePath, "tls", "ca-public.key")
-	caCert, err := os.ReadFile(caCertPath)
+	return certificates.ClientCredentials(tlsCfg.ServerName), nil
+}
+
+func loadCertificates(assetsFilePath string, tlsCfg config.TLSConfig) (*tlsconfig.Reloader, error) {
+	var tlsFiles tlsconfig.Files
+	tlsFiles.CertFile, tlsFiles.KeyFile, tlsFiles.CAFile = tlsCfg.Paths(assetsFilePath, "client")
+	if tlsCfg.Mode == config.TLSModeTLS {
+		tlsFiles.CertFile, tlsFiles.KeyFile = "", ""
+	}
+	minVersion, err := tlsconfig.ParseVersion(tlsCfg.MinVersion)
 	if err != nil {
-		observability.LogError("ca-cert-read", "CreateGRPCConnection", err, map[string]interface{}{
-			"cert_file": caCertPath,
-		})
 		return nil, err
 	}
-	caPool := x509.NewCertPool()
-	if !caPool.AppendCertsFromPEM(caCert) {
-		observability.LogError("ca-cert-append", "CreateGRPCConnection", fmt.Errorf("failed to append CA certificate to pool"), nil)
+	cipherSuites, err := tlsconfig.ParseCipherSuites(tlsCfg.CipherSuites)
+	if err != nil {
 		return nil, err
 	}
 
-	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		RootCAs:      caPool,
-		ServerName:   "localhost",
+	certificates, err := tlsconfig.NewReloader(tlsFiles, tlsconfig.Options{
+		CheckInterval: tlsCfg.ReloadInterval,
+		ExpiryWarning: tlsCfg.ExpiryWarning,
+		MinVersion:    minVersion,
+		CipherSuites:  cipherSuites,
+	})
+	if err != nil {
+		observability.LogError("tls-load", "loadCertificates", err, map[string]interface{}{
+			"cert_file": tlsFiles.CertFile,
+			"key_file":  tlsFiles.KeyFile,
+			"ca_file":   tlsFiles.CAFile,
+		})
+		return nil, err
 	}
+	return certificates, nil
+}
+
+func CreateGRPCConnection(cfg *config.MovieClientConfig) (*grpc.ClientConn, error) {
+	serverURL := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 
-	creds := credentials.NewTLS(tlsConfig)
+	observability.LogSuccess("grpc-connect", "CreateGRPCConnection", map[string]interface{}{
+		"server": serverURL,
+	})
+
+	creds, err := TransportCredentials(cfg.AssetsFilePath, cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
 
 	conn, err := grpc.NewClient(serverURL,
 		grpc.WithTransportCredentials(creds),
+		// Starts a client span per call and injects its W3C trace context into the metadata
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 		grpc.WithUnaryInterceptor(middleware.ClientLoggingInterceptor()),
+		grpc.WithStreamInterceptor(middleware.ClientRequestIDStreamInterceptor()),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to %s: %w", serverURL, err)
@@ -2,10 +2,19 @@ package client
 
 import (
 	"case-studies/grpc/internal/config"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"flag"
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func withEnvAndFlags(t *testing.T, envVars map[string]string, flagArgs []string, testFn func()) {
@@ -309,3 +318,134 @@ func TestLoadConfigFlagPrecedence(t *testing.T) {
 		}
 	})
 }
+
+// writeTestCertificate writes a self-signed certificate and its key as PEM files
+func writeTestCertificate(t *testing.T, certPath, keyPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "movie-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not encode key: %v", err)
+	}
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		t.Fatalf("could not write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("could not write key: %v", err)
+	}
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+	tests := []struct {
+		name               string
+		mode               string
+		withoutClientCert  bool
+		caContent          string
+		expectError        bool
+		expectedCommonName string
+	}{
+		{
+			name:               "client certificate and CA",
+			mode:               config.TLSModeMTLS,
+			expectedCommonName: "movie-client",
+		},
+		{
+			name:        "CA file without certificates",
+			mode:        config.TLSModeMTLS,
+			caContent:   "invalid CA content",
+			expectError: true,
+		},
+		{
+			name:              "mtls without a client certificate",
+			mode:              config.TLSModeMTLS,
+			withoutClientCert: true,
+			expectError:       true,
+		},
+		{
+			name:              "tls without a client certificate",
+			mode:              config.TLSModeTLS,
+			withoutClientCert: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			tempDir := t.TempDir()
+			tlsDir := filepath.Join(tempDir, "tls")
+			if err := os.MkdirAll(tlsDir, 0755); err != nil {
+				t.Fatalf("Failed to create TLS directory: %v", err)
+			}
+			writeTestCertificate(t, filepath.Join(tlsDir, "client-public.key"), filepath.Join(tlsDir, "client-private.key"))
+			caContent, _ := os.ReadFile(filepath.Join(tlsDir, "client-public.key"))
+			if tt.caContent != "" {
+				caContent = []byte(tt.caContent)
+			}
+			if err := os.WriteFile(filepath.Join(tlsDir, "ca-public.key"), caContent, 0644); err != nil {
+				t.Fatalf("Failed to create CA cert: %v", err)
+			}
+			if tt.withoutClientCert {
+				os.Remove(filepath.Join(tlsDir, "client-private.key"))
+			}
+			tlsCfg := config.DefaultTLSConfig()
+			tlsCfg.Mode = tt.mode
+			tlsCfg.ServerName = "grpc-movie-server"
+
+			// When
+			tlsConfig, err := LoadTLSConfig(tempDir, tlsCfg)
+
+			// Then
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Given %s, When loading the TLS config, Then expected error, got nil", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Given %s, When loading the TLS config, Then expected no error, got %v", tt.name, err)
+			}
+			if tlsConfig.ServerName != "grpc-movie-server" || tlsConfig.MinVersion != tls.VersionTLS12 {
+				t.Errorf("Given %s, When loading the TLS config, Then expected server name grpc-movie-server and TLS 1.2, got %q and %x", tt.name, tlsConfig.ServerName, tlsConfig.MinVersion)
+			}
+			cert, err := tlsConfig.GetClientCertificate(nil)
+			if err != nil {
+				t.Fatalf("Given %s, When loading the TLS config, Then expected a client certificate callback, got %v", tt.name, err)
+			}
+			var commonName string
+			if cert.Leaf != nil {
+				commonName = cert.Leaf.Subject.CommonName
+			}
+			if commonName != tt.expectedCommonName {
+				t.Errorf("Given %s, When loading the TLS config, Then expected client certificate %q, got %q", tt.name, tt.expectedCommonName, commonName)
+			}
+		})
+	}
+}
+
+func TestTransportCredentials(t *testing.T) {
+	// Given
+	tlsCfg := config.DefaultTLSConfig()
+	tlsCfg.Mode = config.TLSModeInsecure
+
+	// When
+	creds, err := TransportCredentials(t.TempDir(), tlsCfg)
+
+	// Then
+	if err != nil || creds.Info().SecurityProtocol != "insecure" {
+		t.Errorf("Given insecure mode, When creating transport credentials, Then expected plaintext without reading certificates, got %v, %v", creds, err)
+	}
+}
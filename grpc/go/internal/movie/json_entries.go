@@ -0,0 +1,240 @@
+package movie
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	moviepb "case-studies/grpc/cmd/movie"
+)
+
+// jsonEntry is one element of the movie data array as written in the file, so fields Movie does
+// not model, such as a director's nationality, survive a write
+type jsonEntry struct {
+	raw   json.RawMessage
+	movie *moviepb.Movie
+}
+
+// jsonField is one member of a JSON object, in the order it appears
+type jsonField struct {
+	key   string
+	value json.RawMessage
+}
+
+func decodeJSONEntries(content []byte) ([]jsonEntry, error) {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(content, &raws); err != nil {
+		return nil, fmt.Errorf("could not decode movies: %w", err)
+	}
+	entries := make([]jsonEntry, len(raws))
+	for i, raw := range raws {
+		var m *moviepb.Movie
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, fmt.Errorf("could not decode movies: %w", err)
+		}
+		entries[i] = jsonEntry{raw: raw, movie: m}
+	}
+	return entries, nil
+}
+
+// updateJSONEntries rewrites entries to hold movies. Entries whose movie is unchanged keep their
+// text, replaced movies are merged into the entry they replace, and new movies are appended, so
+// the file keeps its order and formatting apart from the movies that changed.
+func updateJSONEntries(entries []jsonEntry, movies []*moviepb.Movie) ([]jsonEntry, []byte, error) {
+	existing := make(map[*moviepb.Movie]bool, len(entries))
+	for _, e := range entries {
+		existing[e.movie] = true
+	}
+	kept := make(map[*moviepb.Movie]bool, len(movies))
+	replacements := map[string]*moviepb.Movie{}
+	for _, m := range movies {
+		if existing[m] {
+			kept[m] = true
+		} else {
+			replacements[m.GetMovieId()] = m
+		}
+	}
+
+	next := make([]jsonEntry, 0, len(movies))
+	placed := map[*moviepb.Movie]bool{}
+	for _, e := range entries {
+		if kept[e.movie] {
+			next = append(next, e)
+			continue
+		}
+		replacement, ok := replacements[e.movie.GetMovieId()]
+		if !ok || placed[replacement] {
+			continue
+		}
+		raw, err := mergeMovieJSON(e.raw, e.movie, replacement)
+		if err != nil {
+			return nil, nil, err
+		}
+		next = append(next, jsonEntry{raw: raw, movie: replacement})
+		placed[replacement] = true
+	}
+	for _, m := range movies {
+		if kept[m] || placed[m] {
+			continue
+		}
+		raw, err := json.MarshalIndent(m, "  ", "  ")
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not encode movie %q: %w", m.GetMovieId(), err)
+		}
+		next = append(next, jsonEntry{raw: raw, movie: m})
+	}
+
+	var content bytes.Buffer
+	content.WriteString("[")
+	for i, e := range next {
+		if i > 0 {
+			content.WriteString(",")
+		}
+		content.WriteString("\n  ")
+		content.Write(e.raw)
+	}
+	if len(next) > 0 {
+		content.WriteString("\n")
+	}
+	content.WriteString("]\n")
+	return next, content.Bytes(), nil
+}
+
+// mergeMovieJSON applies the change from before to after onto raw, the entry before was read from
+func mergeMovieJSON(raw json.RawMessage, before, after *moviepb.Movie) (json.RawMessage, error) {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode movie %q: %w", before.GetMovieId(), err)
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode movie %q: %w", after.GetMovieId(), err)
+	}
+
+	var merged bytes.Buffer
+	if err := json.Indent(&merged, mergeJSON(raw, beforeJSON, afterJSON), "  ", "  "); err != nil {
+		return nil, fmt.Errorf("could not encode movie %q: %w", after.GetMovieId(), err)
+	}
+	return merged.Bytes(), nil
+}
+
+// mergeJSON keeps raw where before and after agree. In objects, members missing from before are
+// not modelled and stay as they are, while members missing from after were cleared and are dropped.
+func mergeJSON(raw, before, after json.RawMessage) json.RawMessage {
+	if bytes.Equal(before, after) {
+		return raw
+	}
+
+	rawFields, rawOK := jsonObjectFields(raw)
+	beforeFields, beforeOK := jsonObjectFields(before)
+	afterFields, afterOK := jsonObjectFields(after)
+	if rawOK && beforeOK && afterOK {
+		beforeValues := jsonFieldMap(beforeFields)
+		afterValues := jsonFieldMap(afterFields)
+		rawValues := jsonFieldMap(rawFields)
+		var merged []jsonField
+		for _, f := range rawFields {
+			afterValue, inAfter := afterValues[f.key]
+			beforeValue, inBefore := beforeValues[f.key]
+			switch {
+			case inAfter:
+				merged = append(merged, jsonField{key: f.key, value: mergeJSON(f.value, beforeValue, afterValue)})
+			case !inBefore:
+				merged = append(merged, f)
+			}
+		}
+		for _, f := range afterFields {
+			if _, inRaw := rawValues[f.key]; !inRaw {
+				merged = append(merged, f)
+			}
+		}
+		return encodeJSONObject(merged)
+	}
+
+	rawItems, rawOK := jsonArrayItems(raw)
+	beforeItems, beforeOK := jsonArrayItems(before)
+	afterItems, afterOK := jsonArrayItems(after)
+	if rawOK && beforeOK && afterOK && len(rawItems) == len(beforeItems) && len(beforeItems) == len(afterItems) {
+		merged := make([]json.RawMessage, len(rawItems))
+		for i := range rawItems {
+			merged[i] = mergeJSON(rawItems[i], beforeItems[i], afterItems[i])
+		}
+		return encodeJSONArray(merged)
+	}
+
+	return after
+}
+
+func jsonObjectFields(data json.RawMessage) ([]jsonField, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+	var fields []jsonField
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false
+		}
+		fields = append(fields, jsonField{key: key, value: value})
+	}
+	return fields, true
+}
+
+func jsonArrayItems(data json.RawMessage) ([]json.RawMessage, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, false
+	}
+	var items []json.RawMessage
+	for decoder.More() {
+		var item json.RawMessage
+		if err := decoder.Decode(&item); err != nil {
+			return nil, false
+		}
+		items = append(items, item)
+	}
+	return items, true
+}
+
+func jsonFieldMap(fields []jsonField) map[string]json.RawMessage {
+	values := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		values[f.key] = f.value
+	}
+	return values
+}
+
+func encodeJSONObject(fields []jsonField) json.RawMessage {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(f.value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+func encodeJSONArray(items []json.RawMessage) json.RawMessage {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(item)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
@@ -0,0 +1,356 @@
+package movie
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	moviepb "case-studies/grpc/cmd/movie"
+	"case-studies/grpc/internal/atomicfile"
+	"case-studies/grpc/internal/observability"
+)
+
+// maxLoggedDiffIDs bounds the movie IDs listed per category in reload logs
+const maxLoggedDiffIDs = 20
+
+// JSONFileRepository serves movies from a JSON file loaded fully into memory.
+// Reload swaps in a new snapshot atomically, so readers never block and never see a partial catalogue.
+type JSONFileRepository struct {
+	filePath string
+	snapshot atomic.Pointer[jsonSnapshot]
+
+	// Serialises reloads and writes, and guards rejectedChecksum, which stops a broken file being re-parsed every poll
+	writeMu          sync.Mutex
+	rejectedChecksum [sha256.Size]byte
+	// reloadErr is why the file on disk is not being served, until it is fixed; writes are refused meanwhile
+	reloadErr error
+}
+
+// jsonSnapshot is an immutable version of the catalogue
+type jsonSnapshot struct {
+	movies   []*moviepb.Movie
+	byID     map[string]*moviepb.Movie
+	entries  []jsonEntry
+	checksum [sha256.Size]byte
+}
+
+func NewJSONFileRepository(filePath string) (*JSONFileRepository, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		observability.LogError("file-open", "NewJSONFileRepository", err, map[string]interface{}{
+			"file_path": filePath,
+		})
+		return nil, err
+	}
+
+	snapshot, err := parseJSONSnapshot(content)
+	if err != nil {
+		observability.LogError("json-decode", "NewJSONFileRepository", err, map[string]interface{}{
+			"file_path": filePath,
+		})
+		return nil, err
+	}
+
+	r := &JSONFileRepository{filePath: filePath}
+	r.snapshot.Store(snapshot)
+	return r, nil
+}
+
+func (r *JSONFileRepository) List(ctx context.Context) ([]*moviepb.Movie, error) {
+	return r.snapshot.Load().List(ctx)
+}
+
+func (r *JSONFileRepository) Get(ctx context.Context, movieID string) (*moviepb.Movie, error) {
+	return r.snapshot.Load().Get(ctx, movieID)
+}
+
+func (r *JSONFileRepository) Query(ctx context.Context, query Query) ([]*moviepb.Movie, error) {
+	return r.snapshot.Load().Query(ctx, query)
+}
+
+func (r *JSONFileRepository) Count(ctx context.Context) (int, error) {
+	return r.snapshot.Load().Count(ctx)
+}
+
+// Snapshot returns the current catalogue, unaffected by later reloads
+func (r *JSONFileRepository) Snapshot() MovieRepository {
+	return r.snapshot.Load()
+}
+
+// Reload re-reads the file and swaps in the new catalogue if its content changed.
+// An unreadable or invalid file leaves the current catalogue in place.
+func (r *JSONFileRepository) Reload(ctx context.Context) (MovieDiff, error) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	return r.reloadLocked()
+}
+
+// ReloadErr returns why the last reload failed, including a rejected file that has not changed
+// since, or nil once the file is served again
+func (r *JSONFileRepository) ReloadErr() error {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	return r.reloadErr
+}
+
+func (r *JSONFileRepository) reloadLocked() (MovieDiff, error) {
+	content, err := os.ReadFile(r.filePath)
+	if err != nil {
+		r.reloadErr = err
+		observability.LogError("movie-data-reload", "reloadLocked", err, map[string]interface{}{
+			"file_path": r.filePath,
+		})
+		return MovieDiff{}, err
+	}
+
+	current := r.snapshot.Load()
+	checksum := sha256.Sum256(content)
+	if checksum == current.checksum {
+		r.reloadErr = nil
+		return MovieDiff{}, nil
+	}
+	if checksum == r.rejectedChecksum {
+		return MovieDiff{}, nil
+	}
+
+	next, err := parseJSONSnapshot(content)
+	if err != nil {
+		r.rejectedChecksum = checksum
+		r.reloadErr = err
+		observability.LogError("movie-data-reload", "reloadLocked", err, map[string]interface{}{
+			"file_path": r.filePath,
+		})
+		return MovieDiff{}, err
+	}
+
+	diff := DiffMovies(current.movies, next.movies)
+	r.snapshot.Store(next)
+	r.reloadErr = nil
+
+	observability.LogSuccess("movie-data-reload", "reloadLocked", map[string]interface{}{
+		"file_path":      r.filePath,
+		"total_movies":   len(next.movies),
+		"added_movies":   len(diff.Added),
+		"removed_movies": len(diff.Removed),
+		"changed_movies": len(diff.Changed),
+		"added_ids":      truncateIDs(diff.Added),
+		"removed_ids":    truncateIDs(diff.Removed),
+		"changed_ids":    truncateIDs(diff.Changed),
+	})
+	return diff, nil
+}
+
+func (r *JSONFileRepository) Create(ctx context.Context, m *moviepb.Movie) (*moviepb.Movie, error) {
+	stored := proto.Clone(m).(*moviepb.Movie)
+	err := r.write(func(movies []*moviepb.Movie, byID map[string]*moviepb.Movie) ([]*moviepb.Movie, error) {
+		if _, exists := byID[stored.GetMovieId()]; exists {
+			return nil, ErrMovieExists
+		}
+		return append(movies, stored), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+func (r *JSONFileRepository) Update(ctx context.Context, m *moviepb.Movie, etag string) (*moviepb.Movie, error) {
+	stored := proto.Clone(m).(*moviepb.Movie)
+	err := r.write(func(movies []*moviepb.Movie, byID map[string]*moviepb.Movie) ([]*moviepb.Movie, error) {
+		current, err := matchETag(byID, stored.GetMovieId(), etag)
+		if err != nil {
+			return nil, err
+		}
+		movies[slices.Index(movies, current)] = stored
+		return movies, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+func (r *JSONFileRepository) Delete(ctx context.Context, movieID string, etag string) error {
+	return r.write(func(movies []*moviepb.Movie, byID map[string]*moviepb.Movie) ([]*moviepb.Movie, error) {
+		current, err := matchETag(byID, movieID, etag)
+		if err != nil {
+			return nil, err
+		}
+		return slices.DeleteFunc(movies, func(m *moviepb.Movie) bool { return m == current }), nil
+	})
+}
+
+func matchETag(byID map[string]*moviepb.Movie, movieID, etag string) (*moviepb.Movie, error) {
+	current, ok := byID[movieID]
+	if !ok {
+		return nil, ErrMovieNotFound
+	}
+	currentETag, err := MovieETag(current)
+	if err != nil {
+		return nil, err
+	}
+	if currentETag != etag {
+		return nil, ErrETagMismatch
+	}
+	return current, nil
+}
+
+// write applies mutate to a copy of the catalogue, persists the result and swaps it in.
+// Changes made to the file since the last reload are picked up first so they are not overwritten,
+// and a file that fails to reload is left for the operator to fix rather than replaced. Only the
+// entries of changed movies are rewritten.
+func (r *JSONFileRepository) write(mutate func(movies []*moviepb.Movie, byID map[string]*moviepb.Movie) ([]*moviepb.Movie, error)) error {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	r.reloadLocked()
+	if r.reloadErr != nil {
+		return fmt.Errorf("%w: %v", ErrDataFileInvalid, r.reloadErr)
+	}
+
+	current := r.snapshot.Load()
+	movies, err := mutate(slices.Clone(current.movies), current.byID)
+	if err != nil {
+		return err
+	}
+	if err := ValidateMovies(movies); err != nil {
+		return err
+	}
+	entries, content, err := updateJSONEntries(current.entries, movies)
+	if err != nil {
+		return err
+	}
+	SortMovies(movies, SortByRating, SortAscending)
+
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(r.filePath); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := atomicfile.WriteFile(r.filePath, content, perm); err != nil {
+		observability.LogError("movie-data-write", "write", err, map[string]interface{}{
+			"file_path": r.filePath,
+		})
+		return err
+	}
+
+	r.snapshot.Store(&jsonSnapshot{
+		movies:   movies,
+		byID:     IndexMoviesByID(movies),
+		entries:  entries,
+		checksum: sha256.Sum256(content),
+	})
+
+	observability.LogSuccess("movie-data-write", "write", map[string]interface{}{
+		"file_path":    r.filePath,
+		"total_movies": len(movies),
+	})
+	return nil
+}
+
+// Watch polls the file every interval until ctx is done, calling onChange after each reload that changed movies
+func (r *JSONFileRepository) Watch(ctx context.Context, interval time.Duration, onChange func(MovieDiff)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			diff, err := r.Reload(ctx)
+			if err == nil && !diff.IsEmpty() && onChange != nil {
+				onChange(diff)
+			}
+		}
+	}
+}
+
+func parseJSONSnapshot(content []byte) (*jsonSnapshot, error) {
+	entries, err := decodeJSONEntries(content)
+	if err != nil {
+		return nil, err
+	}
+	movies := make([]*moviepb.Movie, len(entries))
+	for i, e := range entries {
+		movies[i] = e.movie
+	}
+	SortMovies(movies, SortByRating, SortAscending)
+	if err := ValidateMovies(movies); err != nil {
+		return nil, fmt.Errorf("invalid movie data: %w", err)
+	}
+
+	return &jsonSnapshot{
+		movies:   movies,
+		byID:     IndexMoviesByID(movies),
+		entries:  entries,
+		checksum: sha256.Sum256(content),
+	}, nil
+}
+
+func truncateIDs(ids []string) []string {
+	if len(ids) > maxLoggedDiffIDs {
+		return ids[:maxLoggedDiffIDs]
+	}
+	return ids
+}
+
+func (s *jsonSnapshot) List(ctx context.Context) ([]*moviepb.Movie, error) {
+	return slices.Clone(s.movies), nil
+}
+
+func (s *jsonSnapshot) Get(ctx context.Context, movieID string) (*moviepb.Movie, error) {
+	m, ok := s.byID[movieID]
+	if !ok {
+		return nil, ErrMovieNotFound
+	}
+	return m, nil
+}
+
+func (s *jsonSnapshot) Query(ctx context.Context, query Query) ([]*moviepb.Movie, error) {
+	return FilterMovies(s.movies, query), nil
+}
+
+func (s *jsonSnapshot) Count(ctx context.Context) (int, error) {
+	return len(s.movies), nil
+}
+
+// DecodeMovies reads a JSON array of movies and sorts it by rating ascending, then movie ID
+func DecodeMovies(reader io.Reader) ([]*moviepb.Movie, error) {
+	var movies []*moviepb.Movie
+	if err := json.NewDecoder(reader).Decode(&movies); err != nil {
+		return nil, fmt.Errorf("could not decode movies: %w", err)
+	}
+
+	SortMovies(movies, SortByRating, SortAscending)
+	return movies, nil
+}
+
+// IndexMoviesByID maps each movie ID to its movie, keeping the first entry when an ID is duplicated
+func IndexMoviesByID(movies []*moviepb.Movie) map[string]*moviepb.Movie {
+	index := make(map[string]*moviepb.Movie, len(movies))
+	duplicates := 0
+	for _, m := range movies {
+		if _, exists := index[m.GetMovieId()]; exists {
+			duplicates++
+			continue
+		}
+		index[m.GetMovieId()] = m
+	}
+
+	observability.LogSuccess("movie-index", "IndexMoviesByID", map[string]interface{}{
+		"total_movies":  len(index),
+		"duplicate_ids": duplicates,
+	})
+	return index
+}
@@ -0,0 +1,317 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testMovieData = `[
+  {"movie_id": "tt0000003", "title": "Gamma", "release_date": "2019-07-20", "genre": ["Drama"], "director": {"name": "Jane Doe"}, "ratings_score": 8.5},
+  {"movie_id": "tt0000001", "title": "Alpha", "release_date": "2024-12-15", "genre": ["Action"], "director": {"name": "Jane Doe"}, "ratings_score": 8.5},
+  {"movie_id": "tt0000002", "title": "Beta", "release_date": "2021-03-01", "genre": ["Sci-Fi"], "director": {"name": "John Roe"}, "ratings_score": 6.2}
+]`
+
+func writeMovieData(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, DefaultMovieDataFileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("could not write movie data: %v", err)
+	}
+	return dir
+}
+
+func TestJSONFileRepository(t *testing.T) {
+	ctx := context.Background()
+	repository, err := NewJSONFileRepository(filepath.Join(writeMovieData(t, testMovieData), DefaultMovieDataFileName))
+	if err != nil {
+		t.Fatalf("Given valid movie data, When opening the repository, Then expected no error, got %v", err)
+	}
+
+	t.Run("list sorted by rating then ID", func(t *testing.T) {
+		// When
+		movies, err := repository.List(ctx)
+
+		// Then
+		if err != nil {
+			t.Fatalf("Given a repository, When listing, Then expected no error, got %v", err)
+		}
+		assertMovieIDs(t, movies, []string{"tt0000002", "tt0000001", "tt0000003"}, "a listed repository")
+	})
+
+	t.Run("list returns a copy", func(t *testing.T) {
+		// Given
+		movies, _ := repository.List(ctx)
+
+		// When
+		movies[0] = nil
+
+		// Then
+		again, _ := repository.List(ctx)
+		if again[0] == nil {
+			t.Errorf("Given a listed slice, When modified, Then expected the repository to be unaffected")
+		}
+	})
+
+	t.Run("get existing movie", func(t *testing.T) {
+		// When
+		m, err := repository.Get(ctx, "tt0000002")
+
+		// Then
+		if err != nil || m.GetTitle() != "Beta" {
+			t.Errorf("Given ID tt0000002, When getting, Then expected Beta, got %v (err %v)", m.GetTitle(), err)
+		}
+	})
+
+	t.Run("get missing movie", func(t *testing.T) {
+		// When
+		_, err := repository.Get(ctx, "tt9999999")
+
+		// Then
+		if !errors.Is(err, ErrMovieNotFound) {
+			t.Errorf("Given an unknown ID, When getting, Then expected ErrMovieNotFound, got %v", err)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		// When
+		movies, err := repository.Query(ctx, Query{DirectorName: "jane", SortBy: SortByTitle, SortOrder: SortAscending})
+
+		// Then
+		if err != nil {
+			t.Fatalf("Given a query, When querying, Then expected no error, got %v", err)
+		}
+		assertMovieIDs(t, movies, []string{"tt0000001", "tt0000003"}, "a director query")
+	})
+
+	t.Run("count", func(t *testing.T) {
+		// When
+		count, err := repository.Count(ctx)
+
+		// Then
+		if err != nil || count != 3 {
+			t.Errorf("Given three movies, When counting, Then expected 3, got %d (err %v)", count, err)
+		}
+	})
+}
+
+func TestNewJSONFileRepositoryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content *string
+	}{
+		{"missing file", nil},
+		{"invalid JSON", func() *string { s := "{not json"; return &s }()},
+		{"invalid movie", func() *string { s := `[{"movie_id": "", "ratings_score": 5}]`; return &s }()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			dir := t.TempDir()
+			if tt.content != nil {
+				dir = writeMovieData(t, *tt.content)
+			}
+
+			// When
+			_, err := NewJSONFileRepository(filepath.Join(dir, DefaultMovieDataFileName))
+
+			// Then
+			if err == nil {
+				t.Errorf("Given %s, When opening the repository, Then expected an error, got nil", tt.name)
+			}
+		})
+	}
+}
+
+func TestJSONFileRepositoryReload(t *testing.T) {
+	const updatedMovieData = `[
+  {"movie_id": "tt0000003", "title": "Gamma", "release_date": "2019-07-20", "genre": ["Drama"], "director": {"name": "Jane Doe"}, "ratings_score": 9.1},
+  {"movie_id": "tt0000001", "title": "Alpha", "release_date": "2024-12-15", "genre": ["Action"], "director": {"name": "Jane Doe"}, "ratings_score": 8.5},
+  {"movie_id": "tt0000004", "title": "Delta", "release_date": "2022-01-01", "genre": ["Comedy"], "director": {"name": "Ann Lee"}, "ratings_score": 7.0}
+]`
+
+	tests := []struct {
+		name          string
+		content       string
+		wantErr       bool
+		expectedDiff  MovieDiff
+		expectedCount int
+	}{
+		{
+			name:          "unchanged file",
+			content:       testMovieData,
+			expectedCount: 3,
+		},
+		{
+			name:          "updated file",
+			content:       updatedMovieData,
+			expectedDiff:  MovieDiff{Added: []string{"tt0000004"}, Removed: []string{"tt0000002"}, Changed: []string{"tt0000003"}},
+			expectedCount: 3,
+		},
+		{
+			name:          "invalid JSON keeps current movies",
+			content:       "[{",
+			wantErr:       true,
+			expectedCount: 3,
+		},
+		{
+			name:          "empty file keeps current movies",
+			content:       "",
+			wantErr:       true,
+			expectedCount: 3,
+		},
+		{
+			name:          "invalid rating keeps current movies",
+			content:       `[{"movie_id": "tt0000009", "ratings_score": 11}]`,
+			wantErr:       true,
+			expectedCount: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			dir := writeMovieData(t, testMovieData)
+			filePath := filepath.Join(dir, DefaultMovieDataFileName)
+			repository, err := NewJSONFileRepository(filePath)
+			if err != nil {
+				t.Fatalf("could not open repository: %v", err)
+			}
+			if err := os.WriteFile(filePath, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("could not update movie data: %v", err)
+			}
+
+			// When
+			diff, err := repository.Reload(ctx)
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Given %s, When reloading, Then expected error = %v, got %v", tt.name, tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(diff, tt.expectedDiff) {
+				t.Errorf("Given %s, When reloading, Then expected diff %+v, got %+v", tt.name, tt.expectedDiff, diff)
+			}
+			if count, _ := repository.Count(ctx); count != tt.expectedCount {
+				t.Errorf("Given %s, When reloading, Then expected %d movies, got %d", tt.name, tt.expectedCount, count)
+			}
+		})
+	}
+}
+
+func TestJSONFileRepositorySnapshotSurvivesReload(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	dir := writeMovieData(t, testMovieData)
+	filePath := filepath.Join(dir, DefaultMovieDataFileName)
+	repository, err := NewJSONFileRepository(filePath)
+	if err != nil {
+		t.Fatalf("could not open repository: %v", err)
+	}
+	snapshot := repository.Snapshot()
+
+	// When
+	if err := os.WriteFile(filePath, []byte(`[{"movie_id": "tt0000004", "ratings_score": 7}]`), 0o644); err != nil {
+		t.Fatalf("could not update movie data: %v", err)
+	}
+	if _, err := repository.Reload(ctx); err != nil {
+		t.Fatalf("could not reload: %v", err)
+	}
+
+	// Then
+	pinned, _ := snapshot.List(ctx)
+	assertMovieIDs(t, pinned, []string{"tt0000002", "tt0000001", "tt0000003"}, "a snapshot taken before reload")
+	current, _ := repository.List(ctx)
+	assertMovieIDs(t, current, []string{"tt0000004"}, "the repository after reload")
+}
+
+func TestJSONFileRepositoryReloadSkipsRejectedFile(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	dir := writeMovieData(t, testMovieData)
+	filePath := filepath.Join(dir, DefaultMovieDataFileName)
+	repository, err := NewJSONFileRepository(filePath)
+	if err != nil {
+		t.Fatalf("could not open repository: %v", err)
+	}
+	if err := os.WriteFile(filePath, []byte("[{"), 0o644); err != nil {
+		t.Fatalf("could not update movie data: %v", err)
+	}
+	if _, err := repository.Reload(ctx); err == nil {
+		t.Fatalf("Given invalid movie data, When reloading, Then expected an error, got nil")
+	}
+
+	// When
+	_, err = repository.Reload(ctx)
+
+	// Then
+	if err != nil {
+		t.Errorf("Given an already rejected file, When reloading again, Then expected it to be skipped, got %v", err)
+	}
+	if repository.ReloadErr() == nil {
+		t.Errorf("Given an already rejected file, When reloading again, Then expected the reload to still be reported as failing")
+	}
+}
+
+func TestJSONFileRepositoryReloadErrClearsWhenFixed(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	dir := writeMovieData(t, testMovieData)
+	filePath := filepath.Join(dir, DefaultMovieDataFileName)
+	repository, err := NewJSONFileRepository(filePath)
+	if err != nil {
+		t.Fatalf("could not open repository: %v", err)
+	}
+	if err := os.Remove(filePath); err != nil {
+		t.Fatalf("could not remove movie data: %v", err)
+	}
+	repository.Reload(ctx)
+	if repository.ReloadErr() == nil {
+		t.Fatalf("Given missing movie data, When reloading, Then expected the reload to be reported as failing")
+	}
+
+	// When
+	if err := os.WriteFile(filePath, []byte(testMovieData), 0o644); err != nil {
+		t.Fatalf("could not restore movie data: %v", err)
+	}
+	repository.Reload(ctx)
+
+	// Then
+	if err := repository.ReloadErr(); err != nil {
+		t.Errorf("Given restored movie data, When reloading, Then expected no reload error, got %v", err)
+	}
+}
+
+func TestOpenMovieRepository(t *testing.T) {
+	dir := writeMovieData(t, testMovieData)
+
+	tests := []struct {
+		name    string
+		backend string
+		wantErr bool
+	}{
+		{"json backend", RepositoryBackendJSON, false},
+		{"default backend", "", false},
+		{"sqlite backend", RepositoryBackendSQLite, false},
+		{"unknown backend", "mongodb", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			repository, err := OpenMovieRepository(context.Background(), RepositoryOptions{Backend: tt.backend, AssetsFilePath: dir})
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Given backend %q, When opening, Then expected error = %v, got %v", tt.backend, tt.wantErr, err)
+			}
+			if !tt.wantErr && repository == nil {
+				t.Errorf("Given backend %q, When opening, Then expected a repository, got nil", tt.backend)
+			}
+		})
+	}
+}
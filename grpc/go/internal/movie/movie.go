@@ -1,5 +1,9 @@
 package movie
 
+import (
+	moviepb "case-studies/grpc/cmd/movie"
+)
+
 type Director struct {
 	Name string `json:"name"`
 }
@@ -37,3 +41,31 @@ type MovieResponse struct {
 	Movies     []Movie `json:"movies"`
 	MovieCount int     `json:"movie_count"`
 }
+
+// MovieFromProto converts a gRPC movie into its REST representation
+func MovieFromProto(m *moviepb.Movie) Movie {
+	result := Movie{
+		MovieID:      m.GetMovieId(),
+		Title:        m.GetTitle(),
+		ReleaseDate:  m.GetReleaseDate(),
+		Genre:        m.GetGenre(),
+		Director:     Director{Name: m.GetDirector().GetName()},
+		PlotSummary:  m.GetPlotSummary(),
+		RatingsScore: m.GetRatingsScore(),
+	}
+	for _, p := range m.GetProducer() {
+		result.Producer = append(result.Producer, Producer{Name: p.GetName()})
+	}
+	for _, c := range m.GetCast() {
+		result.Cast = append(result.Cast, CastMember{
+			ActorName:     c.GetActorName(),
+			CharacterName: c.GetCharacterName(),
+			Role:          c.GetRole(),
+			Biography:     c.GetBiography(),
+		})
+	}
+	for _, c := range m.GetCrew() {
+		result.Crew = append(result.Crew, CrewMember{Name: c.GetName(), Role: c.GetRole()})
+	}
+	return result
+}
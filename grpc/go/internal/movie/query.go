@@ -0,0 +1,163 @@
+package movie
+
+import (
+	"sort"
+	"strings"
+
+	moviepb "case-studies/grpc/cmd/movie"
+)
+
+type GenreMatch int
+
+const (
+	GenreMatchAny GenreMatch = iota
+	GenreMatchAll
+)
+
+type SortField int
+
+const (
+	SortByRating SortField = iota
+	SortByReleaseDate
+	SortByTitle
+)
+
+type SortOrder int
+
+const (
+	SortDescending SortOrder = iota
+	SortAscending
+)
+
+// Query describes a multi-criteria movie search; zero-valued fields do not filter
+type Query struct {
+	Genres     []string
+	GenreMatch GenreMatch
+
+	// Inclusive bounds in YYYY-MM-DD format
+	ReleasedAfter  string
+	ReleasedBefore string
+
+	DirectorName string
+	ActorName    string
+	CrewRole     string
+
+	MinimumRatingsScore *float32
+	MaximumRatingsScore *float32
+	// Exclusive lower bound, as taken by the REST API's min_rating
+	RatingsScoreAbove *float32
+
+	SortBy    SortField
+	SortOrder SortOrder
+}
+
+// Matches reports whether a movie satisfies every criterion in the query
+func (q Query) Matches(m *moviepb.Movie) bool {
+	if q.MinimumRatingsScore != nil && m.GetRatingsScore() < *q.MinimumRatingsScore {
+		return false
+	}
+	if q.MaximumRatingsScore != nil && m.GetRatingsScore() > *q.MaximumRatingsScore {
+		return false
+	}
+	if q.RatingsScoreAbove != nil && m.GetRatingsScore() <= *q.RatingsScoreAbove {
+		return false
+	}
+	// Release dates are ISO 8601 so lexical comparison matches chronological order
+	if q.ReleasedAfter != "" && m.GetReleaseDate() < q.ReleasedAfter {
+		return false
+	}
+	if q.ReleasedBefore != "" && m.GetReleaseDate() > q.ReleasedBefore {
+		return false
+	}
+	if q.DirectorName != "" && !containsFold(m.GetDirector().GetName(), q.DirectorName) {
+		return false
+	}
+	if q.ActorName != "" && !hasActor(m, q.ActorName) {
+		return false
+	}
+	if q.CrewRole != "" && !hasCrewRole(m, q.CrewRole) {
+		return false
+	}
+	if len(q.Genres) > 0 && !q.matchesGenres(m) {
+		return false
+	}
+	return true
+}
+
+func (q Query) matchesGenres(m *moviepb.Movie) bool {
+	matched := 0
+	for _, wanted := range q.Genres {
+		for _, genre := range m.GetGenre() {
+			if strings.EqualFold(genre, wanted) {
+				matched++
+				break
+			}
+		}
+	}
+
+	if q.GenreMatch == GenreMatchAll {
+		return matched == len(q.Genres)
+	}
+	return matched > 0
+}
+
+func hasActor(m *moviepb.Movie, name string) bool {
+	for _, c := range m.GetCast() {
+		if containsFold(c.GetActorName(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCrewRole(m *moviepb.Movie, role string) bool {
+	for _, c := range m.GetCrew() {
+		if strings.EqualFold(c.GetRole(), role) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// FilterMovies returns the movies matching the query, sorted by its sort field and order
+func FilterMovies(movies []*moviepb.Movie, q Query) []*moviepb.Movie {
+	filtered := make([]*moviepb.Movie, 0)
+	for _, m := range movies {
+		if q.Matches(m) {
+			filtered = append(filtered, m)
+		}
+	}
+
+	SortMovies(filtered, q.SortBy, q.SortOrder)
+	return filtered
+}
+
+// SortMovies sorts in place, breaking ties on movie ID so results are deterministic
+func SortMovies(movies []*moviepb.Movie, field SortField, order SortOrder) {
+	sort.SliceStable(movies, func(i, j int) bool {
+		a, b := movies[i], movies[j]
+		if order == SortDescending {
+			a, b = b, a
+		}
+
+		switch field {
+		case SortByReleaseDate:
+			if a.GetReleaseDate() != b.GetReleaseDate() {
+				return a.GetReleaseDate() < b.GetReleaseDate()
+			}
+		case SortByTitle:
+			if !strings.EqualFold(a.GetTitle(), b.GetTitle()) {
+				return strings.ToLower(a.GetTitle()) < strings.ToLower(b.GetTitle())
+			}
+		default:
+			if a.GetRatingsScore() != b.GetRatingsScore() {
+				return a.GetRatingsScore() < b.GetRatingsScore()
+			}
+		}
+		return movies[i].GetMovieId() < movies[j].GetMovieId()
+	})
+}
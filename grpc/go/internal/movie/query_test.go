@@ -0,0 +1,170 @@
+package movie
+
+import (
+	"testing"
+
+	moviepb "case-studies/grpc/cmd/movie"
+)
+
+func float32Ptr(v float32) *float32 {
+	return &v
+}
+
+func testMovies() []*moviepb.Movie {
+	return []*moviepb.Movie{
+		{
+			MovieId:      "tt0000001",
+			Title:        "The Grand Adventure",
+			ReleaseDate:  "2024-12-15",
+			Genre:        []string{"Action", "Adventure", "Sci-Fi"},
+			Director:     &moviepb.Director{Name: "Jane Doe"},
+			Cast:         []*moviepb.CastMember{{ActorName: "Zoe Saldaña"}, {ActorName: "Idris Elba"}},
+			Crew:         []*moviepb.CrewMember{{Name: "Ann Lee", Role: "Editor"}},
+			RatingsScore: 8.5,
+		},
+		{
+			MovieId:      "tt0000002",
+			Title:        "Lunar Glow",
+			ReleaseDate:  "2021-03-01",
+			Genre:        []string{"Sci-Fi", "Time Travel"},
+			Director:     &moviepb.Director{Name: "John Roe"},
+			Cast:         []*moviepb.CastMember{{ActorName: "Margot Robbie"}},
+			Crew:         []*moviepb.CrewMember{{Name: "Bo Chen", Role: "Cinematographer"}},
+			RatingsScore: 6.2,
+		},
+		{
+			MovieId:      "tt0000003",
+			Title:        "after hours",
+			ReleaseDate:  "2019-07-20",
+			Genre:        []string{"Drama"},
+			Director:     &moviepb.Director{Name: "Jane Doe"},
+			Cast:         []*moviepb.CastMember{{ActorName: "Idris Elba"}},
+			Crew:         []*moviepb.CrewMember{{Name: "Cy Park", Role: "Composer"}},
+			RatingsScore: 8.5,
+		},
+	}
+}
+
+func movieIDs(movies []*moviepb.Movie) []string {
+	ids := make([]string, 0, len(movies))
+	for _, m := range movies {
+		ids = append(ids, m.GetMovieId())
+	}
+	return ids
+}
+
+func assertMovieIDs(t *testing.T, got []*moviepb.Movie, expected []string, context string) {
+	ids := movieIDs(got)
+	if len(ids) != len(expected) {
+		t.Fatalf("Given %s, When filtered, Then expected %v, got %v", context, expected, ids)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Errorf("Given %s, When filtered, Then expected %v, got %v", context, expected, ids)
+			return
+		}
+	}
+}
+
+func TestFilterMovies(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{
+			name:     "empty query returns all by rating descending",
+			query:    Query{},
+			expected: []string{"tt0000001", "tt0000003", "tt0000002"},
+		},
+		{
+			name:     "any genre",
+			query:    Query{Genres: []string{"drama", "time travel"}},
+			expected: []string{"tt0000003", "tt0000002"},
+		},
+		{
+			name:     "all genres",
+			query:    Query{Genres: []string{"Sci-Fi", "Action"}, GenreMatch: GenreMatchAll},
+			expected: []string{"tt0000001"},
+		},
+		{
+			name:     "release date range",
+			query:    Query{ReleasedAfter: "2020-01-01", ReleasedBefore: "2021-03-01"},
+			expected: []string{"tt0000002"},
+		},
+		{
+			name:     "director name substring",
+			query:    Query{DirectorName: "jane"},
+			expected: []string{"tt0000001", "tt0000003"},
+		},
+		{
+			name:     "actor name with diacritics",
+			query:    Query{ActorName: "saldaña"},
+			expected: []string{"tt0000001"},
+		},
+		{
+			name:     "crew role exact match",
+			query:    Query{CrewRole: "cinematographer"},
+			expected: []string{"tt0000002"},
+		},
+		{
+			name:     "crew role partial does not match",
+			query:    Query{CrewRole: "Cinema"},
+			expected: []string{},
+		},
+		{
+			name:     "rating range",
+			query:    Query{MinimumRatingsScore: float32Ptr(6), MaximumRatingsScore: float32Ptr(7)},
+			expected: []string{"tt0000002"},
+		},
+		{
+			name:     "exclusive lower rating bound",
+			query:    Query{RatingsScoreAbove: float32Ptr(6.2)},
+			expected: []string{"tt0000001", "tt0000003"},
+		},
+		{
+			name:     "combined criteria",
+			query:    Query{DirectorName: "Jane Doe", ActorName: "Idris", MinimumRatingsScore: float32Ptr(8), ReleasedBefore: "2020-01-01"},
+			expected: []string{"tt0000003"},
+		},
+		{
+			name:     "sort by release date ascending",
+			query:    Query{SortBy: SortByReleaseDate, SortOrder: SortAscending},
+			expected: []string{"tt0000003", "tt0000002", "tt0000001"},
+		},
+		{
+			name:     "sort by title ascending ignores case",
+			query:    Query{SortBy: SortByTitle, SortOrder: SortAscending},
+			expected: []string{"tt0000003", "tt0000002", "tt0000001"},
+		},
+		{
+			name:     "sort by rating ascending breaks ties on ID",
+			query:    Query{SortBy: SortByRating, SortOrder: SortAscending},
+			expected: []string{"tt0000002", "tt0000001", "tt0000003"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			movies := testMovies()
+
+			// When
+			result := FilterMovies(movies, tt.query)
+
+			// Then
+			assertMovieIDs(t, result, tt.expected, "query "+tt.name)
+		})
+	}
+}
+
+func TestFilterMoviesDoesNotReorderInput(t *testing.T) {
+	// Given
+	movies := testMovies()
+
+	// When
+	FilterMovies(movies, Query{SortBy: SortByTitle})
+
+	// Then
+	assertMovieIDs(t, movies, []string{"tt0000001", "tt0000002", "tt0000003"}, "the input slice")
+}
@@ -0,0 +1,79 @@
+package movie
+
+import (
+	"fmt"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+
+	moviepb "case-studies/grpc/cmd/movie"
+	"case-studies/grpc/internal/validation"
+)
+
+// MovieDiff lists the movie IDs that differ between two versions of the catalogue
+type MovieDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+func (d MovieDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffMovies compares two catalogues by movie ID, returning sorted IDs
+func DiffMovies(previous, next []*moviepb.Movie) MovieDiff {
+	previousByID := make(map[string]*moviepb.Movie, len(previous))
+	for _, m := range previous {
+		previousByID[m.GetMovieId()] = m
+	}
+	nextByID := make(map[string]*moviepb.Movie, len(next))
+	for _, m := range next {
+		nextByID[m.GetMovieId()] = m
+	}
+
+	var diff MovieDiff
+	for id, m := range nextByID {
+		old, ok := previousByID[id]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, id)
+		case !proto.Equal(old, m):
+			diff.Changed = append(diff.Changed, id)
+		}
+	}
+	for id := range previousByID {
+		if _, ok := nextByID[id]; !ok {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+
+	slices.Sort(diff.Added)
+	slices.Sort(diff.Removed)
+	slices.Sort(diff.Changed)
+	return diff
+}
+
+// ValidateMovies rejects a catalogue containing movies the API could not serve, including
+// two movies sharing an ID, since only one of them could ever be looked up
+func ValidateMovies(movies []*moviepb.Movie) error {
+	seen := make(map[string]struct{}, len(movies))
+	for i, m := range movies {
+		if err := validation.ValidateMovieID(m.GetMovieId()); err != nil {
+			return fmt.Errorf("movie %d: %w", i, err)
+		}
+		if _, duplicate := seen[m.GetMovieId()]; duplicate {
+			return fmt.Errorf("movie %d: duplicate movie ID %q", i, m.GetMovieId())
+		}
+		seen[m.GetMovieId()] = struct{}{}
+		if err := validation.ValidateMovieRatings(m.GetRatingsScore()); err != nil {
+			return fmt.Errorf("movie %q: %w", m.GetMovieId(), err)
+		}
+		if m.GetReleaseDate() != "" {
+			if err := validation.ValidateReleaseDate(m.GetReleaseDate()); err != nil {
+				return fmt.Errorf("movie %q: %w", m.GetMovieId(), err)
+			}
+		}
+	}
+	return nil
+}
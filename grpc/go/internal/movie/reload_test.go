@@ -0,0 +1,79 @@
+package movie
+
+import (
+	"reflect"
+	"testing"
+
+	moviepb "case-studies/grpc/cmd/movie"
+)
+
+func TestDiffMovies(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []*moviepb.Movie
+		next     []*moviepb.Movie
+		expected MovieDiff
+	}{
+		{
+			name:     "identical catalogues",
+			previous: testMovies(),
+			next:     testMovies(),
+			expected: MovieDiff{},
+		},
+		{
+			name:     "added and removed",
+			previous: testMovies()[:2],
+			next:     testMovies()[1:],
+			expected: MovieDiff{Added: []string{"tt0000003"}, Removed: []string{"tt0000001"}},
+		},
+		{
+			name:     "changed nested field",
+			previous: testMovies(),
+			next: func() []*moviepb.Movie {
+				movies := testMovies()
+				movies[1].Cast[0].ActorName = "Someone Else"
+				return movies
+			}(),
+			expected: MovieDiff{Changed: []string{"tt0000002"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			diff := DiffMovies(tt.previous, tt.next)
+
+			// Then
+			if !reflect.DeepEqual(diff, tt.expected) {
+				t.Errorf("Given %s, When diffing, Then expected %+v, got %+v", tt.name, tt.expected, diff)
+			}
+		})
+	}
+}
+
+func TestValidateMovies(t *testing.T) {
+	tests := []struct {
+		name    string
+		movies  []*moviepb.Movie
+		wantErr bool
+	}{
+		{"valid movies", testMovies(), false},
+		{"empty movie ID", []*moviepb.Movie{{MovieId: "", RatingsScore: 5}}, true},
+		{"rating out of range", []*moviepb.Movie{{MovieId: "tt0000001", RatingsScore: 10.5}}, true},
+		{"malformed release date", []*moviepb.Movie{{MovieId: "tt0000001", ReleaseDate: "15/12/2024"}}, true},
+		{"missing release date", []*moviepb.Movie{{MovieId: "tt0000001"}}, false},
+		{"duplicate movie ID", []*moviepb.Movie{{MovieId: "tt0000001", RatingsScore: 5}, {MovieId: "tt0000002"}, {MovieId: "tt0000001", RatingsScore: 7}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			err := ValidateMovies(tt.movies)
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Given %s, When validating, Then expected error = %v, got %v", tt.name, tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	moviepb "case-studies/grpc/cmd/movie"
+)
+
+const (
+	RepositoryBackendJSON   = "json"
+	RepositoryBackendSQLite = "sqlite"
+
+	DefaultRepositoryBackend     = RepositoryBackendJSON
+	DefaultMovieDataFileName     = "movie-data.json"
+	DefaultMovieDatabaseFileName = "movie-data.db"
+)
+
+var ErrMovieNotFound = errors.New("movie not found")
+
+// MovieRepository is the query layer shared by the gRPC and REST servers.
+// Returned movies are shared with the repository and must not be modified.
+type MovieRepository interface {
+	// List returns every movie sorted by rating ascending, then movie ID
+	List(ctx context.Context) ([]*moviepb.Movie, error)
+	// Get returns ErrMovieNotFound when no movie has the ID
+	Get(ctx context.Context, movieID string) (*moviepb.Movie, error)
+	// Query returns the movies matching the query in the query's sort order
+	Query(ctx context.Context, query Query) ([]*moviepb.Movie, error)
+	Count(ctx context.Context) (int, error)
+}
+
+// SnapshotRepository is implemented by repositories whose data can change while serving.
+// The snapshot stays consistent for as long as the caller holds it.
+type SnapshotRepository interface {
+	Snapshot() MovieRepository
+}
+
+type RepositoryOptions struct {
+	Backend        string
+	AssetsFilePath string
+	// DatabasePath defaults to movie-data.db under AssetsFilePath
+	DatabasePath string
+}
+
+// OpenMovieRepository creates the repository implementation selected by the backend option
+func OpenMovieRepository(ctx context.Context, options RepositoryOptions) (MovieRepository, error) {
+	switch options.Backend {
+	case RepositoryBackendJSON, "":
+		return NewJSONFileRepository(filepath.Join(options.AssetsFilePath, DefaultMovieDataFileName))
+	case RepositoryBackendSQLite:
+		databasePath := options.DatabasePath
+		if databasePath == "" {
+			databasePath = filepath.Join(options.AssetsFilePath, DefaultMovieDatabaseFileName)
+		}
+		return OpenSQLiteRepository(ctx, databasePath)
+	default:
+		return nil, fmt.Errorf("unknown movie repository backend %q", options.Backend)
+	}
+}
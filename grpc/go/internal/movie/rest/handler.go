@@ -0,0 +1,56 @@
+// Package rest serves the movie catalogue over HTTP for the REST server
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	internalMovie "case-studies/grpc/internal/movie"
+	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/validation"
+)
+
+// MoviesHandler serves GET /movies. It returns the movies rated strictly above min_rating
+// (default 0), sorted by rating ascending and then movie ID. Before the repositories, the
+// handler returned movies in the order of movie-data.json; no backend keeps that order now.
+type MoviesHandler struct {
+	Repository internalMovie.MovieRepository
+}
+
+func (h *MoviesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	minRatingStr := r.URL.Query().Get("min_rating")
+	minRating := float32(0.0)
+	if minRatingStr != "" {
+		val, err := strconv.ParseFloat(minRatingStr, 32)
+		if err != nil {
+			http.Error(w, "Invalid min_rating", http.StatusBadRequest)
+			return
+		}
+		minRating = float32(val)
+		if err := validation.ValidateMovieRatings(minRating); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	// The lower bound is exclusive, unlike the gRPC API's minimum_ratings_score
+	movies, err := h.Repository.Query(r.Context(), internalMovie.Query{
+		RatingsScoreAbove: &minRating,
+		SortBy:            internalMovie.SortByRating,
+		SortOrder:         internalMovie.SortAscending,
+	})
+	if err != nil {
+		observability.LogErrorContext(r.Context(), "movie-query", "MoviesHandler", err, map[string]interface{}{
+			"ratings_score": minRating,
+		})
+		http.Error(w, "Failed to load movies", http.StatusInternalServerError)
+		return
+	}
+	filtered := make([]internalMovie.Movie, 0, len(movies))
+	for _, m := range movies {
+		filtered = append(filtered, internalMovie.MovieFromProto(m))
+	}
+	resp := internalMovie.MovieResponse{Movies: filtered, MovieCount: len(filtered)}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
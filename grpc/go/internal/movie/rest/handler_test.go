@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	internalMovie "case-studies/grpc/internal/movie"
+)
+
+const testMovieData = `[
+  {"movie_id": "tt0000003", "title": "Gamma", "ratings_score": 8.5},
+  {"movie_id": "tt0000001", "title": "Alpha", "ratings_score": 6.2},
+  {"movie_id": "tt0000004", "title": "Delta", "ratings_score": 0},
+  {"movie_id": "tt0000002", "title": "Beta", "ratings_score": 7}
+]`
+
+func newTestRepository(t *testing.T, data string) internalMovie.MovieRepository {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), internalMovie.DefaultMovieDataFileName)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("could not write movie data: %v", err)
+	}
+	repository, err := internalMovie.NewJSONFileRepository(path)
+	if err != nil {
+		t.Fatalf("could not open repository: %v", err)
+	}
+	return repository
+}
+
+func TestMoviesHandler(t *testing.T) {
+	repository := newTestRepository(t, testMovieData)
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode int
+		expectedIDs  []string
+	}{
+		{name: "no minimum", query: "", expectedCode: http.StatusOK, expectedIDs: []string{"tt0000001", "tt0000002", "tt0000003"}},
+		{name: "minimum equal to a rating", query: "?min_rating=7", expectedCode: http.StatusOK, expectedIDs: []string{"tt0000003"}},
+		{name: "minimum between ratings", query: "?min_rating=6.5", expectedCode: http.StatusOK, expectedIDs: []string{"tt0000002", "tt0000003"}},
+		{name: "non-numeric minimum", query: "?min_rating=high", expectedCode: http.StatusBadRequest},
+		{name: "minimum out of range", query: "?min_rating=11", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			handler := &MoviesHandler{Repository: repository}
+			recorder := httptest.NewRecorder()
+
+			// When
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/movies"+tt.query, nil))
+
+			// Then
+			if recorder.Code != tt.expectedCode {
+				t.Fatalf("Given %s, When requesting /movies, Then expected status %d, got %d", tt.name, tt.expectedCode, recorder.Code)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+			var resp internalMovie.MovieResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&resp); err != nil {
+				t.Fatalf("Given %s, When requesting /movies, Then expected a JSON body, got %v", tt.name, err)
+			}
+			var ids []string
+			for _, m := range resp.Movies {
+				ids = append(ids, m.MovieID)
+			}
+			if !slices.Equal(ids, tt.expectedIDs) || resp.MovieCount != len(tt.expectedIDs) {
+				t.Errorf("Given %s, When requesting /movies, Then expected %v in rating order, got %v (count %d)", tt.name, tt.expectedIDs, ids, resp.MovieCount)
+			}
+		})
+	}
+}
+
+func TestMoviesHandlerIgnoresFileOrder(t *testing.T) {
+	// Given a file listing movies out of rating order, with a tie listed out of ID order
+	repository := newTestRepository(t, `[
+  {"movie_id": "tt0000005", "title": "Epsilon", "ratings_score": 9},
+  {"movie_id": "tt0000003", "title": "Gamma", "ratings_score": 7},
+  {"movie_id": "tt0000001", "title": "Alpha", "ratings_score": 8},
+  {"movie_id": "tt0000002", "title": "Beta", "ratings_score": 7}
+]`)
+	handler := &MoviesHandler{Repository: repository}
+	recorder := httptest.NewRecorder()
+
+	// When
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/movies", nil))
+
+	// Then
+	var resp internalMovie.MovieResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&resp); err != nil {
+		t.Fatalf("Given movies out of file order, When requesting /movies, Then expected a JSON body, got %v", err)
+	}
+	var ids []string
+	for _, m := range resp.Movies {
+		ids = append(ids, m.MovieID)
+	}
+	expected := []string{"tt0000002", "tt0000003", "tt0000001", "tt0000005"}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("Given movies out of file order, When requesting /movies, Then expected rating then ID order %v, got %v", expected, ids)
+	}
+}
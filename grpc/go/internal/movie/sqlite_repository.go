@@ -0,0 +1,506 @@
+package movie
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	// Registers the pure-Go "sqlite" database/sql driver
+	_ "modernc.org/sqlite"
+
+	moviepb "case-studies/grpc/cmd/movie"
+	"case-studies/grpc/internal/observability"
+)
+
+// SQLiteRepository serves movies from an embedded SQLite database with normalised tables.
+// Rating, release date, genre and crew role filters run as indexed SQL; name filters and
+// final ordering reuse Query so results match the JSON backend exactly.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func OpenSQLiteRepository(ctx context.Context, databasePath string) (*SQLiteRepository, error) {
+	// Immediate transactions make concurrent writers wait out busy_timeout rather than fail
+	// when upgrading a read lock, so etag checks and writes happen atomically
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate", databasePath)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		observability.LogError("sqlite-open", "OpenSQLiteRepository", err, map[string]interface{}{
+			"database_path": databasePath,
+		})
+		return nil, err
+	}
+
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		db.Close()
+		observability.LogError("sqlite-migrate", "OpenSQLiteRepository", err, map[string]interface{}{
+			"database_path": databasePath,
+		})
+		return nil, fmt.Errorf("could not create schema: %w", err)
+	}
+
+	return &SQLiteRepository{db: db}, nil
+}
+
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteRepository) List(ctx context.Context) ([]*moviepb.Movie, error) {
+	return loadSQLiteMovies(ctx, r.db, sqlFilter{})
+}
+
+func (r *SQLiteRepository) Get(ctx context.Context, movieID string) (*moviepb.Movie, error) {
+	return getSQLiteMovie(ctx, r.db, movieID)
+}
+
+func getSQLiteMovie(ctx context.Context, db sqlQueryer, movieID string) (*moviepb.Movie, error) {
+	var filter sqlFilter
+	filter.add("m.movie_id = ?", movieID)
+
+	movies, err := loadSQLiteMovies(ctx, db, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(movies) == 0 {
+		return nil, ErrMovieNotFound
+	}
+	return movies[0], nil
+}
+
+func (r *SQLiteRepository) Query(ctx context.Context, query Query) ([]*moviepb.Movie, error) {
+	movies, err := loadSQLiteMovies(ctx, r.db, sqlFilterFromQuery(query))
+	if err != nil {
+		return nil, err
+	}
+	return FilterMovies(movies, query), nil
+}
+
+func (r *SQLiteRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM movies").Scan(&count); err != nil {
+		return 0, fmt.Errorf("could not count movies: %w", err)
+	}
+	return count, nil
+}
+
+// Import replaces the whole catalogue with the given movies in a single transaction
+func (r *SQLiteRepository) Import(ctx context.Context, movies []*moviepb.Movie) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not begin import: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"movie_crew", "movie_cast", "movie_producers", "movie_genres", "movies", "genres", "people"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+			return fmt.Errorf("could not clear %s: %w", table, err)
+		}
+	}
+
+	importer := &sqliteImporter{tx: tx, people: make(map[string]int64), genres: make(map[string]int64)}
+	for _, m := range movies {
+		if err := importer.insertMovie(ctx, m); err != nil {
+			return fmt.Errorf("could not import movie %q: %w", m.GetMovieId(), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit import: %w", err)
+	}
+
+	observability.LogSuccess("sqlite-import", "Import", map[string]interface{}{
+		"total_movies": len(movies),
+		"total_people": len(importer.people),
+		"total_genres": len(importer.genres),
+	})
+	return nil
+}
+
+func (r *SQLiteRepository) Create(ctx context.Context, m *moviepb.Movie) (*moviepb.Movie, error) {
+	return r.write(ctx, m.GetMovieId(), func(importer *sqliteImporter, current *moviepb.Movie) error {
+		if current != nil {
+			return ErrMovieExists
+		}
+		return importer.insertMovie(ctx, m)
+	})
+}
+
+func (r *SQLiteRepository) Update(ctx context.Context, m *moviepb.Movie, etag string) (*moviepb.Movie, error) {
+	return r.write(ctx, m.GetMovieId(), func(importer *sqliteImporter, current *moviepb.Movie) error {
+		if err := checkETag(current, etag); err != nil {
+			return err
+		}
+		if _, err := importer.tx.ExecContext(ctx, "DELETE FROM movies WHERE movie_id = ?", m.GetMovieId()); err != nil {
+			return err
+		}
+		return importer.insertMovie(ctx, m)
+	})
+}
+
+func (r *SQLiteRepository) Delete(ctx context.Context, movieID string, etag string) error {
+	_, err := r.write(ctx, movieID, func(importer *sqliteImporter, current *moviepb.Movie) error {
+		if err := checkETag(current, etag); err != nil {
+			return err
+		}
+		_, err := importer.tx.ExecContext(ctx, "DELETE FROM movies WHERE movie_id = ?", movieID)
+		return err
+	})
+	return err
+}
+
+func checkETag(current *moviepb.Movie, etag string) error {
+	if current == nil {
+		return ErrMovieNotFound
+	}
+	currentETag, err := MovieETag(current)
+	if err != nil {
+		return err
+	}
+	if currentETag != etag {
+		return ErrETagMismatch
+	}
+	return nil
+}
+
+// write runs change in a transaction with the movie as currently stored (nil if absent),
+// returning the movie as stored afterwards (nil once deleted)
+func (r *SQLiteRepository) write(ctx context.Context, movieID string, change func(importer *sqliteImporter, current *moviepb.Movie) error) (*moviepb.Movie, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not begin write: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := getSQLiteMovie(ctx, tx, movieID)
+	if err != nil && !errors.Is(err, ErrMovieNotFound) {
+		return nil, err
+	}
+
+	importer := &sqliteImporter{tx: tx, people: make(map[string]int64), genres: make(map[string]int64)}
+	if err := change(importer, current); err != nil {
+		return nil, err
+	}
+
+	stored, err := getSQLiteMovie(ctx, tx, movieID)
+	if err != nil && !errors.Is(err, ErrMovieNotFound) {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit write: %w", err)
+	}
+
+	observability.LogSuccess("sqlite-write", "write", map[string]interface{}{
+		"movie_id": movieID,
+		"deleted":  stored == nil,
+	})
+	return stored, nil
+}
+
+type sqliteImporter struct {
+	tx     *sql.Tx
+	people map[string]int64
+	genres map[string]int64
+}
+
+func (i *sqliteImporter) insertMovie(ctx context.Context, m *moviepb.Movie) error {
+	var directorID sql.NullInt64
+	if name := m.GetDirector().GetName(); name != "" {
+		personID, err := i.personID(ctx, name)
+		if err != nil {
+			return err
+		}
+		directorID = sql.NullInt64{Int64: personID, Valid: true}
+	}
+
+	if _, err := i.tx.ExecContext(ctx,
+		"INSERT INTO movies (movie_id, title, release_date, director_id, plot_summary, ratings_score) VALUES (?, ?, ?, ?, ?, ?)",
+		m.GetMovieId(), m.GetTitle(), m.GetReleaseDate(), directorID, m.GetPlotSummary(), m.GetRatingsScore(),
+	); err != nil {
+		return err
+	}
+
+	for position, genre := range m.GetGenre() {
+		genreID, err := i.genreID(ctx, genre)
+		if err != nil {
+			return err
+		}
+		if _, err := i.tx.ExecContext(ctx,
+			"INSERT INTO movie_genres (movie_id, genre_id, position) VALUES (?, ?, ?)",
+			m.GetMovieId(), genreID, position,
+		); err != nil {
+			return err
+		}
+	}
+
+	for position, producer := range m.GetProducer() {
+		personID, err := i.personID(ctx, producer.GetName())
+		if err != nil {
+			return err
+		}
+		if _, err := i.tx.ExecContext(ctx,
+			"INSERT INTO movie_producers (movie_id, person_id, position) VALUES (?, ?, ?)",
+			m.GetMovieId(), personID, position,
+		); err != nil {
+			return err
+		}
+	}
+
+	for position, c := range m.GetCast() {
+		personID, err := i.personID(ctx, c.GetActorName())
+		if err != nil {
+			return err
+		}
+		if _, err := i.tx.ExecContext(ctx,
+			"INSERT INTO movie_cast (movie_id, person_id, character_name, role, biography, position) VALUES (?, ?, ?, ?, ?, ?)",
+			m.GetMovieId(), personID, c.GetCharacterName(), c.GetRole(), c.GetBiography(), position,
+		); err != nil {
+			return err
+		}
+	}
+
+	for position, c := range m.GetCrew() {
+		personID, err := i.personID(ctx, c.GetName())
+		if err != nil {
+			return err
+		}
+		if _, err := i.tx.ExecContext(ctx,
+			"INSERT INTO movie_crew (movie_id, person_id, role, position) VALUES (?, ?, ?, ?)",
+			m.GetMovieId(), personID, c.GetRole(), position,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *sqliteImporter) personID(ctx context.Context, name string) (int64, error) {
+	return lookupOrInsert(ctx, i.tx, i.people, "people", "person_id", name)
+}
+
+func (i *sqliteImporter) genreID(ctx context.Context, name string) (int64, error) {
+	return lookupOrInsert(ctx, i.tx, i.genres, "genres", "genre_id", name)
+}
+
+func lookupOrInsert(ctx context.Context, tx *sql.Tx, cache map[string]int64, table, idColumn, name string) (int64, error) {
+	if id, ok := cache[name]; ok {
+		return id, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO "+table+" (name) VALUES (?)", name); err != nil {
+		return 0, err
+	}
+	var id int64
+	if err := tx.QueryRowContext(ctx, "SELECT "+idColumn+" FROM "+table+" WHERE name = ?", name).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	cache[name] = id
+	return id, nil
+}
+
+// sqlFilter is a conjunction of SQL conditions over the movies table aliased as m
+type sqlFilter struct {
+	clauses []string
+	args    []interface{}
+}
+
+func (f *sqlFilter) add(clause string, args ...interface{}) {
+	f.clauses = append(f.clauses, clause)
+	f.args = append(f.args, args...)
+}
+
+func (f sqlFilter) where() string {
+	if len(f.clauses) == 0 {
+		return "1 = 1"
+	}
+	return strings.Join(f.clauses, " AND ")
+}
+
+func sqlFilterFromQuery(query Query) sqlFilter {
+	var filter sqlFilter
+
+	if query.MinimumRatingsScore != nil {
+		filter.add("m.ratings_score >= ?", *query.MinimumRatingsScore)
+	}
+	if query.MaximumRatingsScore != nil {
+		filter.add("m.ratings_score <= ?", *query.MaximumRatingsScore)
+	}
+	if query.RatingsScoreAbove != nil {
+		filter.add("m.ratings_score > ?", *query.RatingsScoreAbove)
+	}
+	if query.ReleasedAfter != "" {
+		filter.add("m.release_date >= ?", query.ReleasedAfter)
+	}
+	if query.ReleasedBefore != "" {
+		filter.add("m.release_date <= ?", query.ReleasedBefore)
+	}
+
+	if len(query.Genres) > 0 {
+		genres := uniqueFold(query.Genres)
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(genres)), ", ")
+		args := make([]interface{}, 0, len(genres)+1)
+		for _, genre := range genres {
+			args = append(args, genre)
+		}
+
+		subquery := "SELECT mg.movie_id FROM movie_genres mg JOIN genres g ON g.genre_id = mg.genre_id WHERE g.name COLLATE NOCASE IN (" + placeholders + ")"
+		if query.GenreMatch == GenreMatchAll {
+			// Genres differing only in case are stored apart but count as one match
+			subquery += " GROUP BY mg.movie_id HAVING COUNT(DISTINCT g.name COLLATE NOCASE) = ?"
+			args = append(args, len(genres))
+		}
+		filter.add("m.movie_id IN ("+subquery+")", args...)
+	}
+
+	if query.CrewRole != "" {
+		filter.add("EXISTS (SELECT 1 FROM movie_crew c WHERE c.movie_id = m.movie_id AND c.role = ? COLLATE NOCASE)", query.CrewRole)
+	}
+
+	return filter
+}
+
+func uniqueFold(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, v := range values {
+		key := strings.ToLower(v)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, v)
+	}
+	return unique
+}
+
+// sqlQueryer is satisfied by both *sql.DB and *sql.Tx
+type sqlQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// loadSQLiteMovies assembles the movies matching the filter from their normalised tables,
+// sorted by rating ascending, then movie ID
+func loadSQLiteMovies(ctx context.Context, db sqlQueryer, filter sqlFilter) ([]*moviepb.Movie, error) {
+	where := filter.where()
+	matching := "SELECT m.movie_id FROM movies m WHERE " + where
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT m.movie_id, m.title, m.release_date, COALESCE(p.name, ''), m.plot_summary, m.ratings_score
+		FROM movies m LEFT JOIN people p ON p.person_id = m.director_id
+		WHERE `+where+`
+		ORDER BY m.ratings_score, m.movie_id`, filter.args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query movies: %w", err)
+	}
+
+	var movies []*moviepb.Movie
+	byID := make(map[string]*moviepb.Movie)
+	for rows.Next() {
+		m := &moviepb.Movie{Director: &moviepb.Director{}}
+		var ratingsScore float64
+		if err := rows.Scan(&m.MovieId, &m.Title, &m.ReleaseDate, &m.Director.Name, &m.PlotSummary, &ratingsScore); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("could not scan movie: %w", err)
+		}
+		m.RatingsScore = float32(ratingsScore)
+		movies = append(movies, m)
+		byID[m.MovieId] = m
+	}
+	if err := closeRows(rows); err != nil {
+		return nil, err
+	}
+	if len(movies) == 0 {
+		return movies, nil
+	}
+
+	if err := scanSQLiteChildren(ctx, db, `
+		SELECT mg.movie_id, g.name FROM movie_genres mg JOIN genres g ON g.genre_id = mg.genre_id
+		WHERE mg.movie_id IN (`+matching+`) ORDER BY mg.movie_id, mg.position`, filter.args,
+		func(rows *sql.Rows) error {
+			var movieID, genre string
+			if err := rows.Scan(&movieID, &genre); err != nil {
+				return err
+			}
+			byID[movieID].Genre = append(byID[movieID].Genre, genre)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+
+	if err := scanSQLiteChildren(ctx, db, `
+		SELECT mp.movie_id, p.name FROM movie_producers mp JOIN people p ON p.person_id = mp.person_id
+		WHERE mp.movie_id IN (`+matching+`) ORDER BY mp.movie_id, mp.position`, filter.args,
+		func(rows *sql.Rows) error {
+			var movieID string
+			producer := &moviepb.Producer{}
+			if err := rows.Scan(&movieID, &producer.Name); err != nil {
+				return err
+			}
+			byID[movieID].Producer = append(byID[movieID].Producer, producer)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+
+	if err := scanSQLiteChildren(ctx, db, `
+		SELECT mc.movie_id, p.name, mc.character_name, mc.role, mc.biography FROM movie_cast mc JOIN people p ON p.person_id = mc.person_id
+		WHERE mc.movie_id IN (`+matching+`) ORDER BY mc.movie_id, mc.position`, filter.args,
+		func(rows *sql.Rows) error {
+			var movieID string
+			c := &moviepb.CastMember{}
+			if err := rows.Scan(&movieID, &c.ActorName, &c.CharacterName, &c.Role, &c.Biography); err != nil {
+				return err
+			}
+			byID[movieID].Cast = append(byID[movieID].Cast, c)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+
+	if err := scanSQLiteChildren(ctx, db, `
+		SELECT mc.movie_id, p.name, mc.role FROM movie_crew mc JOIN people p ON p.person_id = mc.person_id
+		WHERE mc.movie_id IN (`+matching+`) ORDER BY mc.movie_id, mc.position`, filter.args,
+		func(rows *sql.Rows) error {
+			var movieID string
+			c := &moviepb.CrewMember{}
+			if err := rows.Scan(&movieID, &c.Name, &c.Role); err != nil {
+				return err
+			}
+			byID[movieID].Crew = append(byID[movieID].Crew, c)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+func scanSQLiteChildren(ctx context.Context, db sqlQueryer, query string, args []interface{}, scan func(*sql.Rows) error) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("could not query movie details: %w", err)
+	}
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			rows.Close()
+			return fmt.Errorf("could not scan movie details: %w", err)
+		}
+	}
+	return closeRows(rows)
+}
+
+func closeRows(rows *sql.Rows) error {
+	err := rows.Err()
+	if closeErr := rows.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("could not read rows: %w", err)
+	}
+	return nil
+}
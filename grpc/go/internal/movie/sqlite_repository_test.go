@@ -0,0 +1,199 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	moviepb "case-studies/grpc/cmd/movie"
+)
+
+func openTestSQLiteRepository(t *testing.T) *SQLiteRepository {
+	t.Helper()
+	repository, err := OpenSQLiteRepository(context.Background(), filepath.Join(t.TempDir(), DefaultMovieDatabaseFileName))
+	if err != nil {
+		t.Fatalf("could not open sqlite repository: %v", err)
+	}
+	t.Cleanup(func() { repository.Close() })
+
+	if err := repository.Import(context.Background(), testMovies()); err != nil {
+		t.Fatalf("could not import movies: %v", err)
+	}
+	return repository
+}
+
+func TestSQLiteRepository(t *testing.T) {
+	ctx := context.Background()
+	repository := openTestSQLiteRepository(t)
+
+	t.Run("list sorted by rating then ID", func(t *testing.T) {
+		// When
+		movies, err := repository.List(ctx)
+
+		// Then
+		if err != nil {
+			t.Fatalf("Given an imported database, When listing, Then expected no error, got %v", err)
+		}
+		assertMovieIDs(t, movies, []string{"tt0000002", "tt0000001", "tt0000003"}, "a listed database")
+	})
+
+	t.Run("get round-trips every field", func(t *testing.T) {
+		// Given
+		expected := testMovies()[0]
+
+		// When
+		m, err := repository.Get(ctx, expected.GetMovieId())
+
+		// Then
+		if err != nil {
+			t.Fatalf("Given ID %s, When getting, Then expected no error, got %v", expected.GetMovieId(), err)
+		}
+		if !proto.Equal(m, expected) {
+			t.Errorf("Given ID %s, When getting, Then expected %v, got %v", expected.GetMovieId(), expected, m)
+		}
+	})
+
+	t.Run("get missing movie", func(t *testing.T) {
+		// When
+		_, err := repository.Get(ctx, "tt9999999")
+
+		// Then
+		if !errors.Is(err, ErrMovieNotFound) {
+			t.Errorf("Given an unknown ID, When getting, Then expected ErrMovieNotFound, got %v", err)
+		}
+	})
+
+	t.Run("count", func(t *testing.T) {
+		// When
+		count, err := repository.Count(ctx)
+
+		// Then
+		if err != nil || count != 3 {
+			t.Errorf("Given 3 imported movies, When counting, Then expected 3, got %d (err %v)", count, err)
+		}
+	})
+}
+
+func TestSQLiteRepositoryQueryMatchesFilterMovies(t *testing.T) {
+	ctx := context.Background()
+	repository := openTestSQLiteRepository(t)
+
+	tests := []struct {
+		name  string
+		query Query
+	}{
+		{"empty query", Query{}},
+		{"any genre ignores case", Query{Genres: []string{"drama", "time travel"}}},
+		{"all genres", Query{Genres: []string{"Sci-Fi", "Action"}, GenreMatch: GenreMatchAll}},
+		{"all genres with duplicates", Query{Genres: []string{"sci-fi", "Sci-Fi"}, GenreMatch: GenreMatchAll}},
+		{"release date range", Query{ReleasedAfter: "2020-01-01", ReleasedBefore: "2021-03-01"}},
+		{"rating range", Query{MinimumRatingsScore: float32Ptr(6.2), MaximumRatingsScore: float32Ptr(8.5)}},
+		{"exclusive rating bound", Query{MinimumRatingsScore: float32Ptr(8.6)}},
+		{"rating strictly above", Query{RatingsScoreAbove: float32Ptr(6.2), SortBy: SortByRating, SortOrder: SortAscending}},
+		{"crew role ignores case", Query{CrewRole: "cinematographer"}},
+		{"actor name with diacritics", Query{ActorName: "saldaña"}},
+		{"combined criteria", Query{DirectorName: "Jane Doe", ActorName: "Idris", MinimumRatingsScore: float32Ptr(8), ReleasedBefore: "2020-01-01"}},
+		{"sort by title ascending", Query{SortBy: SortByTitle, SortOrder: SortAscending}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			expected := movieIDs(FilterMovies(testMovies(), tt.query))
+
+			// When
+			movies, err := repository.Query(ctx, tt.query)
+
+			// Then
+			if err != nil {
+				t.Fatalf("Given query %s, When querying, Then expected no error, got %v", tt.name, err)
+			}
+			assertMovieIDs(t, movies, expected, "sqlite query "+tt.name)
+		})
+	}
+}
+
+func TestSQLiteRepositoryKeepsRepeatedGenres(t *testing.T) {
+	ctx := context.Background()
+
+	// Given a movie listing a genre twice, as the JSON backend allows
+	repository := openTestSQLiteRepository(t)
+	expected := &moviepb.Movie{MovieId: "tt0000004", Title: "Orbit", Genre: []string{"Drama", "Sci-Fi", "Drama"}, RatingsScore: 7}
+	if err := repository.Import(ctx, append(testMovies(), expected)); err != nil {
+		t.Fatalf("could not import movies: %v", err)
+	}
+
+	// When
+	m, err := repository.Get(ctx, expected.GetMovieId())
+
+	// Then
+	if err != nil {
+		t.Fatalf("Given ID %s, When getting, Then expected no error, got %v", expected.GetMovieId(), err)
+	}
+	if !slices.Equal(m.GetGenre(), expected.GetGenre()) {
+		t.Errorf("Given repeated genres, When getting, Then expected genres %v, got %v", expected.GetGenre(), m.GetGenre())
+	}
+}
+
+func TestSQLiteRepositoryKeepsGenreCasing(t *testing.T) {
+	ctx := context.Background()
+
+	// Given a movie spelling a genre another movie already has in a different case
+	repository := openTestSQLiteRepository(t)
+	movies := append(testMovies(), &moviepb.Movie{MovieId: "tt0000004", Title: "Orbit", Genre: []string{"SCI-FI", "action"}, RatingsScore: 7})
+	if err := repository.Import(ctx, movies); err != nil {
+		t.Fatalf("could not import movies: %v", err)
+	}
+
+	t.Run("get returns each movie's casing", func(t *testing.T) {
+		for _, expected := range movies {
+			// When
+			m, err := repository.Get(ctx, expected.GetMovieId())
+
+			// Then
+			if err != nil {
+				t.Fatalf("Given ID %s, When getting, Then expected no error, got %v", expected.GetMovieId(), err)
+			}
+			if !slices.Equal(m.GetGenre(), expected.GetGenre()) {
+				t.Errorf("Given ID %s, When getting, Then expected genres %v, got %v", expected.GetMovieId(), expected.GetGenre(), m.GetGenre())
+			}
+		}
+	})
+
+	t.Run("genre filters ignore case", func(t *testing.T) {
+		for _, query := range []Query{
+			{Genres: []string{"sci-fi"}},
+			{Genres: []string{"Sci-Fi", "ACTION"}, GenreMatch: GenreMatchAll},
+		} {
+			// When
+			result, err := repository.Query(ctx, query)
+
+			// Then
+			if err != nil {
+				t.Fatalf("Given genres %v, When querying, Then expected no error, got %v", query.Genres, err)
+			}
+			assertMovieIDs(t, result, movieIDs(FilterMovies(movies, query)), fmt.Sprintf("genres %v in mixed case", query.Genres))
+		}
+	})
+}
+
+func TestSQLiteRepositoryImportReplacesCatalogue(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	repository := openTestSQLiteRepository(t)
+
+	// When
+	err := repository.Import(ctx, testMovies()[:1])
+
+	// Then
+	if err != nil {
+		t.Fatalf("Given an imported database, When re-importing, Then expected no error, got %v", err)
+	}
+	movies, _ := repository.List(ctx)
+	assertMovieIDs(t, movies, []string{"tt0000001"}, "a re-imported database")
+}
@@ -0,0 +1,68 @@
+package movie
+
+// sqliteSchema normalises movies into people, genres and the cast/crew/producer links between them.
+// Positions keep the original ordering of repeated fields so movies round-trip unchanged, and genres
+// keep their casing, with genre filters comparing case-insensitively through idx_genres_name_nocase.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS people (
+	person_id INTEGER PRIMARY KEY,
+	name      TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS genres (
+	genre_id INTEGER PRIMARY KEY,
+	name     TEXT NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_genres_name_nocase ON genres (name COLLATE NOCASE);
+
+CREATE TABLE IF NOT EXISTS movies (
+	movie_id      TEXT PRIMARY KEY,
+	title         TEXT NOT NULL,
+	release_date  TEXT NOT NULL,
+	director_id   INTEGER REFERENCES people (person_id),
+	plot_summary  TEXT NOT NULL,
+	ratings_score REAL NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_movies_ratings_score ON movies (ratings_score, movie_id);
+CREATE INDEX IF NOT EXISTS idx_movies_release_date ON movies (release_date);
+
+CREATE TABLE IF NOT EXISTS movie_genres (
+	movie_id TEXT NOT NULL REFERENCES movies (movie_id) ON DELETE CASCADE,
+	genre_id INTEGER NOT NULL REFERENCES genres (genre_id),
+	position INTEGER NOT NULL,
+	PRIMARY KEY (movie_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_genres_genre_id ON movie_genres (genre_id, movie_id);
+
+CREATE TABLE IF NOT EXISTS movie_producers (
+	movie_id  TEXT NOT NULL REFERENCES movies (movie_id) ON DELETE CASCADE,
+	person_id INTEGER NOT NULL REFERENCES people (person_id),
+	position  INTEGER NOT NULL,
+	PRIMARY KEY (movie_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS movie_cast (
+	movie_id       TEXT NOT NULL REFERENCES movies (movie_id) ON DELETE CASCADE,
+	person_id      INTEGER NOT NULL REFERENCES people (person_id),
+	character_name TEXT NOT NULL,
+	role           TEXT NOT NULL,
+	biography      TEXT NOT NULL,
+	position       INTEGER NOT NULL,
+	PRIMARY KEY (movie_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_cast_person_id ON movie_cast (person_id);
+
+CREATE TABLE IF NOT EXISTS movie_crew (
+	movie_id  TEXT NOT NULL REFERENCES movies (movie_id) ON DELETE CASCADE,
+	person_id INTEGER NOT NULL REFERENCES people (person_id),
+	role      TEXT NOT NULL,
+	position  INTEGER NOT NULL,
+	PRIMARY KEY (movie_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_crew_role ON movie_crew (role COLLATE NOCASE);
+`
@@ -0,0 +1,130 @@
+package movie
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	moviepb "case-studies/grpc/cmd/movie"
+	"case-studies/grpc/internal/observability"
+	"case-studies/grpc/internal/validation"
+)
+
+// MovieSnapshot returns the movies rated at least minRating and the change feed revision they correspond to
+type MovieSnapshot func(ctx context.Context, minRating float32) ([]*moviepb.Movie, int64, error)
+
+// Watcher serves Getter.WatchMovies: a snapshot of the matching movies, ended by a SNAPSHOT_COMPLETE
+// event carrying the revision it corresponds to, then the changes published to Changes after it.
+// Snapshot events carry revision 0, so a client cut off mid-snapshot starts over rather than
+// resuming past the movies it missed.
+type Watcher struct {
+	Changes  *ChangeFeed
+	Snapshot MovieSnapshot
+	// Stopping is closed when the server shuts down. Watches then end with UNAVAILABLE and
+	// resume-revision and resume-epoch trailers, so clients reconnect elsewhere and resume.
+	Stopping <-chan struct{}
+}
+
+func (w *Watcher) WatchMovies(input *moviepb.WatchMoviesRequest, stream moviepb.Getter_WatchMoviesServer) error {
+	ctx := stream.Context()
+	observability.LogSuccessContext(ctx, "movie-watch-start", "WatchMovies", map[string]interface{}{
+		"ratings_score":   input.GetMinimumRatingsScore(),
+		"resume_revision": input.GetResumeRevision(),
+		"resume_epoch":    input.GetResumeEpoch(),
+	})
+
+	if err := validation.ValidateMovieRatings(input.GetMinimumRatingsScore()); err != nil {
+		observability.LogErrorContext(ctx, "validation", "WatchMovies", err, map[string]interface{}{
+			"ratings_score": input.GetMinimumRatingsScore(),
+		})
+		return err
+	}
+	if input.GetResumeRevision() < 0 {
+		return status.Error(codes.InvalidArgument, "resume_revision cannot be negative")
+	}
+
+	minRating := input.GetMinimumRatingsScore()
+	revision := input.GetResumeRevision()
+	epoch := w.Changes.Epoch()
+
+	if revision == 0 {
+		movies, snapshotRevision, err := w.Snapshot(ctx, minRating)
+		if err != nil {
+			return err
+		}
+		for _, m := range movies {
+			if err := stream.Send(&moviepb.MovieEvent{Type: moviepb.MovieEventType_MOVIE_EVENT_TYPE_ADDED, Movie: m, Epoch: epoch}); err != nil {
+				observability.LogErrorContext(ctx, "stream-send", "WatchMovies", err, nil)
+				return err
+			}
+		}
+		if err := stream.Send(&moviepb.MovieEvent{Type: moviepb.MovieEventType_MOVIE_EVENT_TYPE_SNAPSHOT_COMPLETE, Revision: snapshotRevision, Epoch: epoch}); err != nil {
+			observability.LogErrorContext(ctx, "stream-send", "WatchMovies", err, nil)
+			return err
+		}
+		revision = snapshotRevision
+	} else if input.GetResumeEpoch() != epoch {
+		// The revision was issued by an earlier process, or another replica, and may name any change
+		observability.LogErrorContext(ctx, "movie-watch", "WatchMovies", ErrRevisionUnavailable, map[string]interface{}{
+			"revision":     revision,
+			"resume_epoch": input.GetResumeEpoch(),
+			"epoch":        epoch,
+		})
+		return status.Errorf(codes.OutOfRange, "epoch %q is not the current change history; restart the watch with resume_revision 0", input.GetResumeEpoch())
+	}
+
+	for {
+		changes, published, err := w.Changes.Since(revision)
+		if err != nil {
+			observability.LogErrorContext(ctx, "movie-watch", "WatchMovies", err, map[string]interface{}{
+				"revision": revision,
+			})
+			return status.Errorf(codes.OutOfRange, "revision %d is not in the change history; restart the watch with resume_revision 0", revision)
+		}
+
+		for _, change := range changes {
+			revision = change.Revision
+			visible, ok := change.ForMinimumRating(minRating)
+			if !ok {
+				continue
+			}
+			if err := stream.Send(&moviepb.MovieEvent{Type: movieEventType(visible.Type), Revision: visible.Revision, Movie: visible.Movie(), Epoch: epoch}); err != nil {
+				observability.LogErrorContext(ctx, "stream-send", "WatchMovies", err, nil)
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			observability.LogSuccessContext(ctx, "movie-watch", "WatchMovies", map[string]interface{}{
+				"ratings_score": minRating,
+				"revision":      revision,
+			})
+			return status.FromContextError(ctx.Err()).Err()
+		case <-w.Stopping:
+			observability.LogSuccessContext(ctx, "movie-watch-stop", "WatchMovies", map[string]interface{}{
+				"ratings_score": minRating,
+				"revision":      revision,
+			})
+			stream.SetTrailer(metadata.Pairs("resume-revision", strconv.FormatInt(revision, 10), "resume-epoch", epoch))
+			return status.Errorf(codes.Unavailable, "server is stopping; resume the watch with resume_revision %d and resume_epoch %q", revision, epoch)
+		case <-published:
+		}
+	}
+}
+
+func movieEventType(changeType ChangeType) moviepb.MovieEventType {
+	switch changeType {
+	case ChangeAdded:
+		return moviepb.MovieEventType_MOVIE_EVENT_TYPE_ADDED
+	case ChangeModified:
+		return moviepb.MovieEventType_MOVIE_EVENT_TYPE_MODIFIED
+	case ChangeDeleted:
+		return moviepb.MovieEventType_MOVIE_EVENT_TYPE_DELETED
+	default:
+		return moviepb.MovieEventType_MOVIE_EVENT_TYPE_UNSPECIFIED
+	}
+}
@@ -0,0 +1,263 @@
+package movie
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	moviepb "case-studies/grpc/cmd/movie"
+	"case-studies/grpc/internal/observability"
+)
+
+type watchGetterServer struct {
+	moviepb.UnimplementedGetterServer
+	watcher *Watcher
+}
+
+func (s *watchGetterServer) WatchMovies(input *moviepb.WatchMoviesRequest, stream moviepb.Getter_WatchMoviesServer) error {
+	return s.watcher.WatchMovies(input, stream)
+}
+
+// newTestWatchClient serves WatchMovies from feed over an in-memory connection until stopping is
+// closed. The snapshot is the feed's movies as last published through publish.
+func newTestWatchClient(t *testing.T, feed *ChangeFeed, stopping <-chan struct{}) (moviepb.GetterClient, func(next []*moviepb.Movie)) {
+	t.Helper()
+	var movies []*moviepb.Movie
+	var revision int64
+	publish := func(next []*moviepb.Movie) {
+		revision = feed.Publish(movies, next)
+		movies = next
+	}
+	watcher := &Watcher{Changes: feed, Stopping: stopping, Snapshot: func(ctx context.Context, minRating float32) ([]*moviepb.Movie, int64, error) {
+		return FilterMovies(movies, Query{MinimumRatingsScore: &minRating}), revision, nil
+	}}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	moviepb.RegisterGetterServer(server, &watchGetterServer{watcher: watcher})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return moviepb.NewGetterClient(conn), publish
+}
+
+func receiveEvents(t *testing.T, stream moviepb.Getter_WatchMoviesClient, count int) []*moviepb.MovieEvent {
+	t.Helper()
+	events := make([]*moviepb.MovieEvent, 0, count)
+	for range count {
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("could not receive event %d: %v", len(events)+1, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestWatchMoviesResumesAfterSnapshot(t *testing.T) {
+	observability.SetupLogger("info")
+
+	// Given a watcher that read the snapshot of movies rated at least 8 and disconnected
+	client, publish := newTestWatchClient(t, newTestChangeFeed(t, 10), nil)
+	publish(testMovies())
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.WatchMovies(ctx, &moviepb.WatchMoviesRequest{MinimumRatingsScore: 8})
+	if err != nil {
+		t.Fatalf("could not watch: %v", err)
+	}
+	snapshot := receiveEvents(t, stream, 3)
+	cancel()
+	last := snapshot[len(snapshot)-1]
+
+	changed := testMovies()
+	changed[0].Title = "The Grand Adventure Returns"
+	changed[1].RatingsScore = 9
+	publish(changed[:2])
+
+	// When
+	resumed, err := client.WatchMovies(context.Background(), &moviepb.WatchMoviesRequest{
+		MinimumRatingsScore: 8,
+		ResumeRevision:      last.GetRevision(),
+		ResumeEpoch:         last.GetEpoch(),
+	})
+	if err != nil {
+		t.Fatalf("could not resume: %v", err)
+	}
+	events := receiveEvents(t, resumed, 3)
+
+	// Then
+	for _, event := range snapshot[:2] {
+		if event.GetType() != moviepb.MovieEventType_MOVIE_EVENT_TYPE_ADDED || event.GetRevision() != 0 || event.GetEpoch() == "" {
+			t.Errorf("Given a new watch, When reading the snapshot, Then expected ADDED at revision 0 with an epoch, got %v", event)
+		}
+	}
+	if last.GetType() != moviepb.MovieEventType_MOVIE_EVENT_TYPE_SNAPSHOT_COMPLETE || last.GetRevision() != 3 || last.GetMovie() != nil || last.GetEpoch() == "" {
+		t.Errorf("Given a new watch, When reading the snapshot, Then expected it to end with SNAPSHOT_COMPLETE at revision 3 with an epoch, got %v", last)
+	}
+	expected := []struct {
+		eventType moviepb.MovieEventType
+		movieID   string
+	}{
+		{moviepb.MovieEventType_MOVIE_EVENT_TYPE_MODIFIED, "tt0000001"},
+		{moviepb.MovieEventType_MOVIE_EVENT_TYPE_ADDED, "tt0000002"},
+		{moviepb.MovieEventType_MOVIE_EVENT_TYPE_DELETED, "tt0000003"},
+	}
+	for i, e := range expected {
+		event := events[i]
+		if event.GetType() != e.eventType || event.GetMovie().GetMovieId() != e.movieID || event.GetEpoch() != last.GetEpoch() {
+			t.Errorf("Given a resumed watch, When reading event %d, Then expected %v %s in the snapshot's epoch, got %v", i, e.eventType, e.movieID, event)
+		}
+		if event.GetRevision() <= last.GetRevision() {
+			t.Errorf("Given a resumed watch, When reading event %d, Then expected a revision after %d, got %d", i, last.GetRevision(), event.GetRevision())
+		}
+	}
+}
+
+func TestWatchMoviesRestartsSnapshotCutOffBeforeCompletion(t *testing.T) {
+	observability.SetupLogger("info")
+
+	// Given a watcher that disconnected after the first snapshot event, and a movie that rose above the threshold since
+	client, publish := newTestWatchClient(t, newTestChangeFeed(t, 10), nil)
+	publish(testMovies())
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.WatchMovies(ctx, &moviepb.WatchMoviesRequest{MinimumRatingsScore: 8})
+	if err != nil {
+		t.Fatalf("could not watch: %v", err)
+	}
+	first := receiveEvents(t, stream, 1)[0]
+	cancel()
+
+	changed := testMovies()
+	changed[1].RatingsScore = 9
+	publish(changed)
+
+	// When resuming from the last event received
+	resumed, err := client.WatchMovies(context.Background(), &moviepb.WatchMoviesRequest{
+		MinimumRatingsScore: 8,
+		ResumeRevision:      first.GetRevision(),
+		ResumeEpoch:         first.GetEpoch(),
+	})
+	if err != nil {
+		t.Fatalf("could not resume: %v", err)
+	}
+	events := receiveEvents(t, resumed, 4)
+
+	// Then the whole snapshot is sent again
+	var added []string
+	for _, event := range events[:3] {
+		if event.GetType() != moviepb.MovieEventType_MOVIE_EVENT_TYPE_ADDED {
+			t.Errorf("Given a watch cut off mid-snapshot, When resuming, Then expected ADDED snapshot events, got %v", event)
+		}
+		added = append(added, event.GetMovie().GetMovieId())
+	}
+	slices.Sort(added)
+	if !slices.Equal(added, []string{"tt0000001", "tt0000002", "tt0000003"}) {
+		t.Errorf("Given a watch cut off mid-snapshot, When resuming, Then expected every matching movie, got %v", added)
+	}
+	if events[3].GetType() != moviepb.MovieEventType_MOVIE_EVENT_TYPE_SNAPSHOT_COMPLETE || events[3].GetRevision() != 4 {
+		t.Errorf("Given a watch cut off mid-snapshot, When resuming, Then expected SNAPSHOT_COMPLETE at revision 4, got %v", events[3])
+	}
+}
+
+func TestWatchMoviesCompletesEmptySnapshot(t *testing.T) {
+	observability.SetupLogger("info")
+
+	// Given a threshold no movie reaches
+	feed := newTestChangeFeed(t, 10)
+	client, publish := newTestWatchClient(t, feed, nil)
+	publish(testMovies())
+
+	// When
+	stream, err := client.WatchMovies(context.Background(), &moviepb.WatchMoviesRequest{MinimumRatingsScore: 9.5})
+	if err != nil {
+		t.Fatalf("could not watch: %v", err)
+	}
+	event := receiveEvents(t, stream, 1)[0]
+
+	// Then
+	if event.GetType() != moviepb.MovieEventType_MOVIE_EVENT_TYPE_SNAPSHOT_COMPLETE || event.GetRevision() != 3 || event.GetEpoch() != feed.Epoch() {
+		t.Errorf("Given no matching movies, When watching, Then expected SNAPSHOT_COMPLETE at revision 3 in the feed's epoch, got %v", event)
+	}
+}
+
+func TestWatchMoviesRejectsUnknownRevisions(t *testing.T) {
+	observability.SetupLogger("info")
+
+	feed := newTestChangeFeed(t, 10)
+	client, publish := newTestWatchClient(t, feed, nil)
+	publish(testMovies())
+
+	tests := []struct {
+		name     string
+		revision int64
+		epoch    string
+	}{
+		{"revision from an earlier process", 2, newTestChangeFeed(t, 10).Epoch()},
+		{"revision without an epoch", 2, ""},
+		{"revision the feed has not reached", 4, feed.Epoch()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			stream, err := client.WatchMovies(context.Background(), &moviepb.WatchMoviesRequest{ResumeRevision: tt.revision, ResumeEpoch: tt.epoch})
+			if err != nil {
+				t.Fatalf("could not watch: %v", err)
+			}
+
+			// When
+			_, err = stream.Recv()
+
+			// Then
+			if status.Code(err) != codes.OutOfRange {
+				t.Errorf("Given a %s, When resuming, Then expected OUT_OF_RANGE, got %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestWatchMoviesEndsWhenServerStops(t *testing.T) {
+	observability.SetupLogger("info")
+
+	// Given a watcher that has read the snapshot
+	feed := newTestChangeFeed(t, 10)
+	stopping := make(chan struct{})
+	client, publish := newTestWatchClient(t, feed, stopping)
+	publish(testMovies())
+	stream, err := client.WatchMovies(context.Background(), &moviepb.WatchMoviesRequest{MinimumRatingsScore: 8})
+	if err != nil {
+		t.Fatalf("could not watch: %v", err)
+	}
+	receiveEvents(t, stream, 3)
+
+	// When
+	close(stopping)
+	_, err = stream.Recv()
+
+	// Then
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Given a stopping server, When watching, Then expected UNAVAILABLE, got %v", err)
+	}
+	trailer := stream.Trailer()
+	if got := trailer.Get("resume-revision"); len(got) != 1 || got[0] != "3" {
+		t.Errorf("Given a stopping server, When watching, Then expected resume-revision 3, got %v", got)
+	}
+	if got := trailer.Get("resume-epoch"); len(got) != 1 || got[0] != feed.Epoch() {
+		t.Errorf("Given a stopping server, When watching, Then expected resume-epoch %q, got %v", feed.Epoch(), got)
+	}
+}
@@ -0,0 +1,84 @@
+package movie
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	moviepb "case-studies/grpc/cmd/movie"
+)
+
+var (
+	ErrMovieExists  = errors.New("movie already exists")
+	ErrETagMismatch = errors.New("movie has been modified since the etag was issued")
+	ErrMovieETag    = errors.New("could not compute movie etag")
+	// ErrDataFileInvalid refuses writes that would replace a data file the repository could not reload
+	ErrDataFileInvalid = errors.New("movie data file could not be reloaded")
+)
+
+// WritableMovieRepository is implemented by repositories that can persist catalogue changes.
+// Update and Delete only apply while the stored movie still has the given etag, and every
+// write returns the movie as stored so callers can compute its new etag.
+type WritableMovieRepository interface {
+	MovieRepository
+	Create(ctx context.Context, m *moviepb.Movie) (*moviepb.Movie, error)
+	Update(ctx context.Context, m *moviepb.Movie, etag string) (*moviepb.Movie, error)
+	Delete(ctx context.Context, movieID string, etag string) error
+}
+
+// MovieETag identifies the content of a movie; any change to the movie changes its etag. It fails
+// with ErrMovieETag for movies that cannot be encoded, such as ones holding invalid UTF-8.
+func MovieETag(m *moviepb.Movie) (string, error) {
+	content, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("%w: movie %q: %v", ErrMovieETag, m.GetMovieId(), err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// ApplyUpdateMask returns a copy of current with the masked fields taken from patch.
+// An empty mask replaces every field. The movie ID cannot be changed.
+func ApplyUpdateMask(current, patch *moviepb.Movie, mask *fieldmaskpb.FieldMask) (*moviepb.Movie, error) {
+	if len(mask.GetPaths()) == 0 {
+		updated := proto.Clone(patch).(*moviepb.Movie)
+		updated.MovieId = current.GetMovieId()
+		return updated, nil
+	}
+
+	if !mask.IsValid(current) {
+		return nil, fmt.Errorf("invalid update mask paths %v", mask.GetPaths())
+	}
+
+	updated := proto.Clone(current).(*moviepb.Movie)
+	for _, path := range mask.GetPaths() {
+		if path == "movie_id" {
+			return nil, errors.New("movie_id cannot be updated")
+		}
+
+		names := strings.Split(path, ".")
+		dst, src := updated.ProtoReflect(), patch.ProtoReflect()
+		for _, name := range names[:len(names)-1] {
+			field := dst.Descriptor().Fields().ByName(protoreflect.Name(name))
+			dst = dst.Mutable(field).Message()
+			src = src.Get(field).Message()
+		}
+
+		field := dst.Descriptor().Fields().ByName(protoreflect.Name(names[len(names)-1]))
+		if src.Has(field) {
+			dst.Set(field, src.Get(field))
+		} else {
+			dst.Clear(field)
+		}
+	}
+
+	// Detach any lists or messages shared with the patch
+	return proto.Clone(updated).(*moviepb.Movie), nil
+}
@@ -0,0 +1,329 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	moviepb "case-studies/grpc/cmd/movie"
+)
+
+func movieETag(t *testing.T, m *moviepb.Movie) string {
+	t.Helper()
+	etag, err := MovieETag(m)
+	if err != nil {
+		t.Fatalf("could not compute etag: %v", err)
+	}
+	return etag
+}
+
+func TestMovieETag(t *testing.T) {
+	// Given
+	original := testMovies()[0]
+	same := testMovies()[0]
+	changed := testMovies()[0]
+	changed.Crew[0].Role = "Producer"
+
+	// When
+	etag := movieETag(t, original)
+
+	// Then
+	if etag != movieETag(t, same) {
+		t.Errorf("Given identical movies, When computing etags, Then expected equal etags")
+	}
+	if etag == movieETag(t, changed) {
+		t.Errorf("Given a changed nested field, When computing etags, Then expected different etags")
+	}
+}
+
+func TestMovieETagInvalidUTF8(t *testing.T) {
+	// Given
+	invalid := testMovies()[0]
+	invalid.Title = "Alpha \xff"
+
+	// When
+	etag, err := MovieETag(invalid)
+
+	// Then
+	if !errors.Is(err, ErrMovieETag) || etag != "" {
+		t.Errorf("Given a title that is not valid UTF-8, When computing the etag, Then expected ErrMovieETag, got %q (err %v)", etag, err)
+	}
+}
+
+func TestApplyUpdateMask(t *testing.T) {
+	patch := &moviepb.Movie{
+		MovieId:      "tt0000009",
+		Title:        "New Title",
+		Director:     &moviepb.Director{Name: "New Director"},
+		Genre:        []string{"Comedy"},
+		RatingsScore: 9.9,
+	}
+
+	tests := []struct {
+		name     string
+		paths    []string
+		wantErr  bool
+		expected func(m *moviepb.Movie)
+	}{
+		{
+			name:  "empty mask replaces everything but the ID",
+			paths: nil,
+			expected: func(m *moviepb.Movie) {
+				proto.Reset(m)
+				proto.Merge(m, patch)
+				m.MovieId = "tt0000001"
+			},
+		},
+		{
+			name:  "top-level fields",
+			paths: []string{"title", "genre"},
+			expected: func(m *moviepb.Movie) {
+				m.Title = "New Title"
+				m.Genre = []string{"Comedy"}
+			},
+		},
+		{
+			name:  "nested field",
+			paths: []string{"director.name"},
+			expected: func(m *moviepb.Movie) {
+				m.Director.Name = "New Director"
+			},
+		},
+		{
+			name:  "field absent from patch is cleared",
+			paths: []string{"cast"},
+			expected: func(m *moviepb.Movie) {
+				m.Cast = nil
+			},
+		},
+		{
+			name:    "movie ID is immutable",
+			paths:   []string{"movie_id"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			paths:   []string{"budget"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			current := testMovies()[0]
+
+			// When
+			updated, err := ApplyUpdateMask(current, patch, &fieldmaskpb.FieldMask{Paths: tt.paths})
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Given paths %v, When applying, Then expected error = %v, got %v", tt.paths, tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			expected := testMovies()[0]
+			tt.expected(expected)
+			if !proto.Equal(updated, expected) {
+				t.Errorf("Given paths %v, When applying, Then expected %v, got %v", tt.paths, expected, updated)
+			}
+			if !proto.Equal(current, testMovies()[0]) {
+				t.Errorf("Given paths %v, When applying, Then expected the current movie to be unchanged", tt.paths)
+			}
+		})
+	}
+}
+
+func TestWritableMovieRepositories(t *testing.T) {
+	ctx := context.Background()
+
+	backends := map[string]func(t *testing.T) WritableMovieRepository{
+		RepositoryBackendJSON: func(t *testing.T) WritableMovieRepository {
+			repository, err := NewJSONFileRepository(filepath.Join(writeMovieData(t, testMovieData), DefaultMovieDataFileName))
+			if err != nil {
+				t.Fatalf("could not open json repository: %v", err)
+			}
+			return repository
+		},
+		RepositoryBackendSQLite: func(t *testing.T) WritableMovieRepository {
+			return openTestSQLiteRepository(t)
+		},
+	}
+
+	for backend, open := range backends {
+		t.Run(backend, func(t *testing.T) {
+			// Given
+			repository := open(t)
+			created := &moviepb.Movie{MovieId: "tt0000010", Title: "Epsilon", Genre: []string{"Drama"}, RatingsScore: 7.5}
+
+			// When
+			stored, err := repository.Create(ctx, created)
+
+			// Then
+			if err != nil {
+				t.Fatalf("Given a new movie, When creating on %s, Then expected no error, got %v", backend, err)
+			}
+			etag := movieETag(t, stored)
+
+			if _, err := repository.Create(ctx, created); !errors.Is(err, ErrMovieExists) {
+				t.Errorf("Given an existing ID, When creating on %s, Then expected ErrMovieExists, got %v", backend, err)
+			}
+
+			update := proto.Clone(stored).(*moviepb.Movie)
+			update.Title = "Epsilon Redux"
+			if _, err := repository.Update(ctx, update, "stale-etag"); !errors.Is(err, ErrETagMismatch) {
+				t.Errorf("Given a stale etag, When updating on %s, Then expected ErrETagMismatch, got %v", backend, err)
+			}
+
+			updated, err := repository.Update(ctx, update, etag)
+			if err != nil || updated.GetTitle() != "Epsilon Redux" {
+				t.Fatalf("Given the current etag, When updating on %s, Then expected the new title, got %v (err %v)", backend, updated.GetTitle(), err)
+			}
+			if err := repository.Delete(ctx, "tt0000010", etag); !errors.Is(err, ErrETagMismatch) {
+				t.Errorf("Given the pre-update etag, When deleting on %s, Then expected ErrETagMismatch, got %v", backend, err)
+			}
+
+			if err := repository.Delete(ctx, "tt0000010", movieETag(t, updated)); err != nil {
+				t.Fatalf("Given the current etag, When deleting on %s, Then expected no error, got %v", backend, err)
+			}
+			if _, err := repository.Get(ctx, "tt0000010"); !errors.Is(err, ErrMovieNotFound) {
+				t.Errorf("Given a deleted movie, When getting on %s, Then expected ErrMovieNotFound, got %v", backend, err)
+			}
+			if err := repository.Delete(ctx, "tt0000010", etag); !errors.Is(err, ErrMovieNotFound) {
+				t.Errorf("Given a deleted movie, When deleting on %s, Then expected ErrMovieNotFound, got %v", backend, err)
+			}
+		})
+	}
+}
+
+func TestJSONFileRepositoryWritePersists(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	filePath := filepath.Join(writeMovieData(t, testMovieData), DefaultMovieDataFileName)
+	repository, err := NewJSONFileRepository(filePath)
+	if err != nil {
+		t.Fatalf("could not open repository: %v", err)
+	}
+
+	// When
+	created, err := repository.Create(ctx, &moviepb.Movie{MovieId: "tt0000010", Title: "Epsilon", RatingsScore: 7.5})
+	if err != nil {
+		t.Fatalf("could not create movie: %v", err)
+	}
+
+	// Then
+	reopened, err := NewJSONFileRepository(filePath)
+	if err != nil {
+		t.Fatalf("Given a written file, When reopening, Then expected no error, got %v", err)
+	}
+	persisted, err := reopened.Get(ctx, "tt0000010")
+	if err != nil || movieETag(t, persisted) != movieETag(t, created) {
+		t.Errorf("Given a created movie, When reopening, Then expected the same etag, got %v (err %v)", persisted, err)
+	}
+	if diff, _ := repository.Reload(ctx); !diff.IsEmpty() {
+		t.Errorf("Given a repository's own write, When reloading, Then expected no changes, got %+v", diff)
+	}
+}
+
+func TestJSONFileRepositoryRefusesWritesWhileReloadFails(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	filePath := filepath.Join(writeMovieData(t, testMovieData), DefaultMovieDataFileName)
+	repository, err := NewJSONFileRepository(filePath)
+	if err != nil {
+		t.Fatalf("could not open repository: %v", err)
+	}
+	handEdit := []byte(`[{"movie_id": "tt0000001", "title": "Alpha", "ratings_score": 42}]`)
+	if err := os.WriteFile(filePath, handEdit, 0o644); err != nil {
+		t.Fatalf("could not edit movie data: %v", err)
+	}
+
+	// When
+	_, err = repository.Create(ctx, &moviepb.Movie{MovieId: "tt0000010", Title: "Epsilon", RatingsScore: 7.5})
+
+	// Then
+	if !errors.Is(err, ErrDataFileInvalid) {
+		t.Errorf("Given a hand edit that fails validation, When creating a movie, Then expected ErrDataFileInvalid, got %v", err)
+	}
+	if content, _ := os.ReadFile(filePath); string(content) != string(handEdit) {
+		t.Errorf("Given a hand edit that fails validation, When creating a movie, Then expected the file to be left alone, got %s", content)
+	}
+	if repository.ReloadErr() == nil {
+		t.Errorf("Given a hand edit that fails validation, When creating a movie, Then expected the reload to still be reported as failing")
+	}
+}
+
+func TestJSONFileRepositoryWriteKeepsUnmodelledFields(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	content := `[
+  {"movie_id": "tt0000003", "title": "Gamma", "director": {"name": "Jane Doe", "nationality": "American", "birth_year": 1975}, "ratings_score": 8.5, "box_office": "$1m"},
+  {"movie_id": "tt0000001", "title": "Alpha", "director": {"name": "Jane Doe", "nationality": "American"}, "ratings_score": 8.5},
+  {"movie_id": "tt0000002", "title": "Beta", "director": {"name": "John Roe"}, "ratings_score": 6.2}
+]
+`
+	filePath := filepath.Join(writeMovieData(t, content), DefaultMovieDataFileName)
+	repository, err := NewJSONFileRepository(filePath)
+	if err != nil {
+		t.Fatalf("could not open repository: %v", err)
+	}
+	current, _ := repository.Get(ctx, "tt0000003")
+	update := proto.Clone(current).(*moviepb.Movie)
+	update.Title = "Gamma Redux"
+	update.Director.Name = "Jim Poe"
+
+	// When
+	if _, err := repository.Update(ctx, update, movieETag(t, current)); err != nil {
+		t.Fatalf("could not update movie: %v", err)
+	}
+	if err := repository.Delete(ctx, "tt0000002", movieETag(t, testMovieByID(t, repository, "tt0000002"))); err != nil {
+		t.Fatalf("could not delete movie: %v", err)
+	}
+	if _, err := repository.Create(ctx, &moviepb.Movie{MovieId: "tt0000010", Title: "Epsilon", RatingsScore: 7.5}); err != nil {
+		t.Fatalf("could not create movie: %v", err)
+	}
+
+	// Then
+	written, _ := os.ReadFile(filePath)
+	expected := `[
+  {
+    "movie_id": "tt0000003",
+    "title": "Gamma Redux",
+    "director": {
+      "name": "Jim Poe",
+      "nationality": "American",
+      "birth_year": 1975
+    },
+    "ratings_score": 8.5,
+    "box_office": "$1m"
+  },
+  {"movie_id": "tt0000001", "title": "Alpha", "director": {"name": "Jane Doe", "nationality": "American"}, "ratings_score": 8.5},
+  {
+    "movie_id": "tt0000010",
+    "title": "Epsilon",
+    "ratings_score": 7.5
+  }
+]
+`
+	if string(written) != expected {
+		t.Errorf("Given entries with fields Movie does not model, When writing, Then expected only changed entries rewritten and unknown fields kept, got:\n%s", written)
+	}
+	if diff, _ := repository.Reload(ctx); !diff.IsEmpty() {
+		t.Errorf("Given a repository's own writes, When reloading, Then expected no changes, got %+v", diff)
+	}
+}
+
+func testMovieByID(t *testing.T, repository MovieRepository, movieID string) *moviepb.Movie {
+	t.Helper()
+	m, err := repository.Get(context.Background(), movieID)
+	if err != nil {
+		t.Fatalf("could not get movie %s: %v", movieID, err)
+	}
+	return m
+}
@@ -0,0 +1,85 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestContextLogHelpers(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	tracedCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected map[string]string
+	}{
+		{
+			name:     "request ID",
+			ctx:      ContextWithRequestID(context.Background(), "abc-123"),
+			expected: map[string]string{"request_id": "abc-123"},
+		},
+		{
+			name:     "trace and request IDs",
+			ctx:      ContextWithRequestID(tracedCtx, "abc-123"),
+			expected: map[string]string{"request_id": "abc-123", "trace_id": traceID.String(), "span_id": spanID.String()},
+		},
+		{
+			name:     "no IDs",
+			ctx:      context.Background(),
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			logFns := map[string]func(){
+				"LogSuccessContext": func() { LogSuccessContext(tt.ctx, "test operation", "test function", nil) },
+				"LogErrorContext":   func() { LogErrorContext(tt.ctx, "test operation", "test function", errors.New("boom"), nil) },
+				"LogInfrastructureInputContext": func() {
+					LogInfrastructureInputContext(tt.ctx, "test request", nil)
+				},
+			}
+
+			for fnName, logFn := range logFns {
+				// When
+				output := captureOutput(t, func() {
+					SetupLogger("debug")
+					logFn()
+				})
+
+				// Then
+				var entry map[string]interface{}
+				if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &entry); err != nil {
+					t.Fatalf("Given %s, When logging with %s, Then expected one JSON line, got %q", tt.name, fnName, output)
+				}
+				for _, key := range []string{"request_id", "trace_id", "span_id"} {
+					if got, want := entry[key], tt.expected[key]; want == "" && got != nil || want != "" && got != want {
+						t.Errorf("Given %s, When logging with %s, Then expected %s %q, got %v", tt.name, fnName, key, want, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	// When
+	first, second := NewRequestID(), NewRequestID()
+
+	// Then
+	if len(first) != 32 || first == second {
+		t.Errorf("Given two new request IDs, When compared, Then expected distinct 32 character IDs, got %q and %q", first, second)
+	}
+}
@@ -0,0 +1,85 @@
+package observability
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// levelVar backs the logger installed by SetupLogger so the level can change while it runs
+var levelVar = new(slog.LevelVar)
+
+// CurrentLogLevel returns the level the default logger is writing at
+func CurrentLogLevel() string {
+	return currentLevel().String()
+}
+
+func currentLevel() LogLevel {
+	switch l := levelVar.Level(); {
+	case l <= slog.LevelDebug:
+		return LogLevelDebug
+	case l <= slog.LevelInfo:
+		return LogLevelInfo
+	case l <= slog.LevelWarn:
+		return LogLevelWarn
+	default:
+		return LogLevelError
+	}
+}
+
+// SetLogLevel changes the level of the default logger and returns the previous one
+func SetLogLevel(levelStr, source string) (string, error) {
+	if err := ValidateLogLevel(levelStr); err != nil {
+		return "", err
+	}
+	previous := currentLevel()
+	setLevel(previous, ParseLogLevel(levelStr), source)
+	return previous.String(), nil
+}
+
+// StepLogLevel moves the level by steps, negative for more detail, staying between debug and error
+func StepLogLevel(steps int, source string) {
+	previous := currentLevel()
+	level := LogLevel(max(int(LogLevelDebug), min(int(previous)+steps, int(LogLevelError))))
+	setLevel(previous, level, source)
+}
+
+func setLevel(previous, level LogLevel, source string) {
+	levelVar.Set(level.ToSlogLevel())
+	if previous != level {
+		// Warn so the change is visible at every level except error
+		slog.Default().Warn("log level changed", "previous_level", previous.String(), "log_level", level.String(), "source", source)
+	}
+}
+
+// sampler keeps 1 in every calls of an operation
+type sampler struct {
+	every int
+	calls atomic.Uint64
+}
+
+var samplers atomic.Pointer[map[string]*sampler]
+
+// SetLogSampling logs only 1 in N successes of each named operation, replacing earlier rates.
+// Rates of 1 or less log every success; errors are never sampled.
+func SetLogSampling(rates map[string]int) {
+	next := make(map[string]*sampler, len(rates))
+	for operation, every := range rates {
+		if every > 1 {
+			next[operation] = &sampler{every: every}
+		}
+	}
+	samplers.Store(&next)
+}
+
+// sampleSuccess reports whether this success of operation should be logged and at what rate
+func sampleSuccess(operation string) (bool, int) {
+	current := samplers.Load()
+	if current == nil {
+		return true, 1
+	}
+	s, ok := (*current)[operation]
+	if !ok {
+		return true, 1
+	}
+	return (s.calls.Add(1)-1)%uint64(s.every) == 0, s.every
+}
@@ -0,0 +1,117 @@
+package observability
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSetLogLevel(t *testing.T) {
+	tests := []struct {
+		name             string
+		level            string
+		expectedPrevious string
+		expectErr        bool
+		shouldSeeDebug   bool
+	}{
+		{"more detail", "debug", "info", false, true},
+		{"less detail", "warn", "info", false, false},
+		{"invalid level", "verbose", "", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var previous string
+			var err error
+			output := captureOutput(t, func() {
+				// Given
+				SetupLogger("info")
+
+				// When
+				previous, err = SetLogLevel(tt.level, "test")
+				LogInfrastructureInput("debug line", nil)
+			})
+
+			// Then
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Given level %q, When setting it, Then expected error %v, got %v", tt.level, tt.expectErr, err)
+			}
+			if previous != tt.expectedPrevious {
+				t.Errorf("Given level %q, When setting it, Then expected previous level %q, got %q", tt.level, tt.expectedPrevious, previous)
+			}
+			if strings.Contains(output, "debug line") != tt.shouldSeeDebug {
+				t.Errorf("Given level %q, When logging at debug, Then expected output %v, got: %s", tt.level, tt.shouldSeeDebug, output)
+			}
+		})
+	}
+}
+
+func TestStepLogLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		steps    int
+		expected string
+	}{
+		{"one step more detail", "info", -1, "debug"},
+		{"one step less detail", "info", 1, "warn"},
+		{"past debug", "debug", -1, "debug"},
+		{"past error", "error", 2, "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureOutput(t, func() {
+				// Given
+				SetupLogger(tt.start)
+
+				// When
+				StepLogLevel(tt.steps, "test")
+			})
+
+			// Then
+			if got := CurrentLogLevel(); got != tt.expected {
+				t.Errorf("Given level %s, When stepping %d, Then expected %s, got %s", tt.start, tt.steps, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLogSampling(t *testing.T) {
+	t.Cleanup(func() { SetLogSampling(nil) })
+
+	// Given
+	output := captureOutput(t, func() {
+		SetupLogger("info")
+		SetLogSampling(map[string]int{"movie-filter": 3, "movie-request": 1})
+
+		// When
+		for i := 0; i < 7; i++ {
+			LogSuccess("movie-filter", "filterMoviesByRating", nil)
+			LogError("movie-filter", "filterMoviesByRating", errors.New("boom"), nil)
+			LogSuccess("movie-request", "GetMoviesByRatings", nil)
+		}
+	})
+
+	// Then
+	counts := map[string]int{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		switch {
+		case strings.Contains(line, `"operation failed"`):
+			counts["errors"]++
+		case strings.Contains(line, `"operation":"movie-filter"`):
+			counts["movie-filter"]++
+			if !strings.Contains(line, `"sample_rate":3`) {
+				t.Errorf("Given 1 in 3 sampling, When a success is logged, Then expected sample_rate 3, got: %s", line)
+			}
+		case strings.Contains(line, `"operation":"movie-request"`):
+			counts["movie-request"]++
+		}
+	}
+	expected := map[string]int{"movie-filter": 3, "errors": 7, "movie-request": 7}
+	for key, want := range expected {
+		if counts[key] != want {
+			t.Errorf("Given 1 in 3 sampling of movie-filter, When logging 7 calls, Then expected %d %s lines, got %d", want, key, counts[key])
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package observability
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
@@ -74,14 +75,19 @@ func ValidateLogLevel(levelStr string) error {
 	return fmt.Errorf("invalid log level '%s'. Valid values: %s", levelStr, strings.Join(validLevels, ", "))
 }
 
-func SetupLogger(logLevel string) *slog.Logger {
+// SetupLogger installs the default JSON logger at logLevel, which SetLogLevel can change later.
+// Fields named in DefaultRedactedKeys or redactKeys, and credential-like values, are masked
+// before they are written.
+func SetupLogger(logLevel string, redactKeys ...string) *slog.Logger {
 	level := ParseLogLevel(logLevel)
 
+	levelVar.Set(level.ToSlogLevel())
 	opts := &slog.HandlerOptions{
-		Level: level.ToSlogLevel(),
+		Level: levelVar,
 	}
 
-	handler := slog.NewJSONHandler(os.Stdout, opts)
+	keys := append(append([]string(nil), DefaultRedactedKeys...), redactKeys...)
+	handler := contextHandler{NewRedactHandler(slog.NewJSONHandler(os.Stdout, opts), keys)}
 	logger := slog.New(handler)
 	slog.SetDefault(logger)
 
@@ -110,45 +116,97 @@ func LogShutdown(appType, appName string) {
 
 // LogError logs errors with consistent structure
 func LogError(operation, function string, err error, fields map[string]interface{}) {
+	LogErrorContext(context.Background(), operation, function, err, fields)
+}
+
+// LogErrorContext is LogError carrying the request and trace IDs in ctx
+func LogErrorContext(ctx context.Context, operation, function string, err error, fields map[string]interface{}) {
 	args := []interface{}{"operation", operation, "function", function, "error", err}
 	for k, v := range fields {
 		args = append(args, k, v)
 	}
-	slog.Default().Error("operation failed", args...)
+	slog.Default().ErrorContext(ctx, "operation failed", args...)
 }
 
 // LogSuccess logs successful operations with consistent structure
 func LogSuccess(operation, function string, fields map[string]interface{}) {
+	LogSuccessContext(context.Background(), operation, function, fields)
+}
+
+// LogSuccessContext is LogSuccess carrying the request and trace IDs in ctx. Operations with a
+// sampling rate only log 1 in N successes, noting the rate in sample_rate.
+func LogSuccessContext(ctx context.Context, operation, function string, fields map[string]interface{}) {
+	keep, every := sampleSuccess(operation)
+	if !keep {
+		return
+	}
+	args := []interface{}{"operation", operation, "function", function}
+	if every > 1 {
+		args = append(args, "sample_rate", every)
+	}
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+	slog.Default().InfoContext(ctx, "operation completed successfully", args...)
+}
+
+// LogWarning logs a condition that needs attention before it becomes an error, such as a
+// certificate close to expiry
+func LogWarning(operation, function string, fields map[string]interface{}) {
 	args := []interface{}{"operation", operation, "function", function}
 	for k, v := range fields {
 		args = append(args, k, v)
 	}
-	slog.Default().Info("operation completed successfully", args...)
+	slog.Default().Warn("operation needs attention", args...)
 }
 
 // LogInfrastructureInput logs infrastructure request events
 func LogInfrastructureInput(message string, fields map[string]interface{}) {
+	LogInfrastructureInputContext(context.Background(), message, fields)
+}
+
+// LogInfrastructureInputContext is LogInfrastructureInput carrying the request and trace IDs in ctx
+func LogInfrastructureInputContext(ctx context.Context, message string, fields map[string]interface{}) {
 	args := []interface{}{"component", "infrastructure", "event_type", "request"}
 	for k, v := range fields {
 		args = append(args, k, v)
 	}
-	slog.Default().Debug(message, args...)
+	slog.Default().DebugContext(ctx, message, args...)
 }
 
 // LogInfrastructureOutput logs infrastructure response events (Debug level)
 func LogInfrastructureOutput(message string, fields map[string]interface{}) {
+	LogInfrastructureOutputContext(context.Background(), message, fields)
+}
+
+// LogInfrastructureOutputContext is LogInfrastructureOutput carrying the request and trace IDs in ctx
+func LogInfrastructureOutputContext(ctx context.Context, message string, fields map[string]interface{}) {
 	args := []interface{}{"component", "infrastructure", "event_type", "response"}
 	for k, v := range fields {
 		args = append(args, k, v)
 	}
-	slog.Default().Debug(message, args...)
+	slog.Default().DebugContext(ctx, message, args...)
 }
 
 // LogInfrastructureError logs infrastructure errors (Error level)
 func LogInfrastructureError(message string, err error, fields map[string]interface{}) {
+	LogInfrastructureErrorContext(context.Background(), message, err, fields)
+}
+
+// LogInfrastructureErrorContext is LogInfrastructureError carrying the request and trace IDs in ctx
+func LogInfrastructureErrorContext(ctx context.Context, message string, err error, fields map[string]interface{}) {
 	args := []interface{}{"component", "infrastructure", "event_type", "error", "error", err}
 	for k, v := range fields {
 		args = append(args, k, v)
 	}
-	slog.Default().Error(message, args...)
+	slog.Default().ErrorContext(ctx, message, args...)
+}
+
+// LogAudit logs an access control decision (Warn level) so refusals can be reviewed apart from errors
+func LogAudit(ctx context.Context, decision string, fields map[string]interface{}) {
+	args := []interface{}{"component", "audit", "decision", decision}
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+	slog.Default().WarnContext(ctx, "access decision", args...)
 }
@@ -2,6 +2,7 @@ package observability
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -234,3 +235,25 @@ func TestLogSuccess(t *testing.T) {
 		t.Errorf("Given success log, When logged, Then expected output to contain 'operation completed successfully', got: %s", output)
 	}
 }
+
+func TestLogWarning(t *testing.T) {
+	output := captureOutput(t, func() {
+		SetupLogger("warn")
+		LogWarning("tls-certificate-expiry", "report", map[string]interface{}{"days_until_expiry": 3})
+	})
+
+	if !strings.Contains(output, `"level":"WARN"`) || !strings.Contains(output, `"operation":"tls-certificate-expiry"`) {
+		t.Errorf("Given a warning, When logged at warn level, Then expected a WARN line with its operation, got: %s", output)
+	}
+}
+
+func TestLogAudit(t *testing.T) {
+	output := captureOutput(t, func() {
+		SetupLogger("warn")
+		LogAudit(context.Background(), "deny", map[string]interface{}{"method": "/movie.MovieAdmin/CreateMovie"})
+	})
+
+	if !strings.Contains(output, `"component":"audit"`) || !strings.Contains(output, `"decision":"deny"`) {
+		t.Errorf("Given an audit log, When logged at warn level, Then expected the audit component and decision, got: %s", output)
+	}
+}
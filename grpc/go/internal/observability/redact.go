@@ -0,0 +1,124 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the value of a denylisted field or a credential-like string
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedKeys are always masked; SetupLogger adds to them, it never removes
+var DefaultRedactedKeys = []string{"x-api-key", "authorization", "name", "biography"}
+
+// secretPatterns match credentials wherever they appear in a string value
+var secretPatterns = []*regexp.Regexp{
+	// Authorization header values
+	regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]+`),
+	// JWTs
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*`),
+	// Generated API keys are 43 base64url characters. The bound stays above the 32 hex
+	// characters of request and trace IDs so those still correlate.
+	regexp.MustCompile(`[A-Za-z0-9_-]{40,}`),
+}
+
+// redactHandler masks denylisted keys and credential-like values before a record reaches the next handler
+type redactHandler struct {
+	next slog.Handler
+	keys map[string]bool
+}
+
+// NewRedactHandler wraps next so fields named in keys (compared case-insensitively, with _ and - treated
+// alike) are logged as [REDACTED], and bearer tokens, JWTs and API key-like strings are masked in any value
+func NewRedactHandler(next slog.Handler, keys []string) slog.Handler {
+	h := &redactHandler{next: next, keys: make(map[string]bool, len(keys))}
+	for _, k := range keys {
+		if k = normaliseKey(k); k != "" {
+			h.keys[k] = true
+		}
+	}
+	return h
+}
+
+func normaliseKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "_", "-")
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, maskSecrets(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(a)
+	}
+	return &redactHandler{next: h.next.WithAttrs(redacted), keys: h.keys}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{next: h.next.WithGroup(name), keys: h.keys}
+}
+
+func (h *redactHandler) redactAttr(a slog.Attr) slog.Attr {
+	if h.keys[normaliseKey(a.Key)] {
+		return slog.String(a.Key, Redacted)
+	}
+	return slog.Attr{Key: a.Key, Value: h.redactValue(a.Value.Resolve())}
+}
+
+func (h *redactHandler) redactValue(v slog.Value) slog.Value {
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.StringValue(maskSecrets(v.String()))
+	case slog.KindGroup:
+		attrs := v.Group()
+		redacted := make([]slog.Attr, len(attrs))
+		for i, a := range attrs {
+			redacted[i] = h.redactAttr(a)
+		}
+		return slog.GroupValue(redacted...)
+	case slog.KindAny:
+		switch value := v.Any().(type) {
+		case error:
+			return slog.StringValue(maskSecrets(value.Error()))
+		case map[string]interface{}:
+			attrs := make([]slog.Attr, 0, len(value))
+			for k, inner := range value {
+				attrs = append(attrs, h.redactAttr(slog.Any(k, inner)))
+			}
+			return slog.GroupValue(attrs...)
+		case map[string]string:
+			attrs := make([]slog.Attr, 0, len(value))
+			for k, inner := range value {
+				attrs = append(attrs, h.redactAttr(slog.String(k, inner)))
+			}
+			return slog.GroupValue(attrs...)
+		case []string:
+			masked := make([]string, len(value))
+			for i, s := range value {
+				masked[i] = maskSecrets(s)
+			}
+			return slog.AnyValue(masked)
+		}
+	}
+	return v
+}
+
+func maskSecrets(s string) string {
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
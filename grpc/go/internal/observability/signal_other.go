@@ -0,0 +1,13 @@
+//go:build !unix
+
+package observability
+
+// NotifyLogLevelSignals does nothing where SIGUSR1 and SIGUSR2 do not exist
+func NotifyLogLevelSignals() (stop func()) {
+	return func() {}
+}
+
+// NotifyReloadSignal does nothing where SIGHUP does not exist
+func NotifyReloadSignal(reload func()) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,56 @@
+//go:build unix
+
+package observability
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// NotifyLogLevelSignals lowers the log level one step on SIGUSR1 (more detail) and raises it
+// on SIGUSR2, until stop is called
+func NotifyLogLevelSignals() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if sig == syscall.SIGUSR1 {
+					StepLogLevel(-1, "SIGUSR1")
+				} else {
+					StepLogLevel(1, "SIGUSR2")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// NotifyReloadSignal calls reload on every SIGHUP until stop is called
+func NotifyReloadSignal(reload func()) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-signals:
+				reload()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,61 @@
+//go:build unix
+
+package observability
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyLogLevelSignals(t *testing.T) {
+	tests := []struct {
+		name     string
+		signal   syscall.Signal
+		expected string
+	}{
+		{"SIGUSR1 adds detail", syscall.SIGUSR1, "debug"},
+		{"SIGUSR2 removes detail", syscall.SIGUSR2, "warn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureOutput(t, func() {
+				// Given
+				SetupLogger("info")
+				stop := NotifyLogLevelSignals()
+				defer stop()
+
+				// When
+				syscall.Kill(os.Getpid(), tt.signal)
+				deadline := time.Now().Add(2 * time.Second)
+				for CurrentLogLevel() != tt.expected && time.Now().Before(deadline) {
+					time.Sleep(10 * time.Millisecond)
+				}
+			})
+
+			// Then
+			if got := CurrentLogLevel(); got != tt.expected {
+				t.Errorf("Given level info, When receiving %v, Then expected %s, got %s", tt.signal, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNotifyReloadSignal(t *testing.T) {
+	// Given
+	reloaded := make(chan struct{}, 1)
+	stop := NotifyReloadSignal(func() { reloaded <- struct{}{} })
+	defer stop()
+
+	// When
+	syscall.Kill(os.Getpid(), syscall.SIGHUP)
+
+	// Then
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Errorf("Given a reload handler, When receiving SIGHUP, Then expected it to be called")
+	}
+}
@@ -0,0 +1,137 @@
+package observability
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	grpc_health_v1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestSetupTracing(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		wantErr  bool
+	}{
+		{"no exporter", TracesExporterNone, false},
+		{"stdout exporter", TracesExporterStdout, false},
+		{"OTLP exporter", TracesExporterOTLP, false},
+		{"unknown exporter", "jaeger", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			shutdown, err := SetupTracing(context.Background(), "movie-test", tt.exporter, "localhost:4317")
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Given the %s, When setting up tracing, Then expected error = %v, got %v", tt.name, tt.wantErr, err)
+			}
+			if err == nil {
+				if err := shutdown(context.Background()); err != nil {
+					t.Errorf("Given the %s, When shutting tracing down, Then expected no error, got %v", tt.name, err)
+				}
+			}
+		})
+	}
+}
+
+// useSpanRecorder installs a tracer provider recording ended spans in memory for the test
+func useSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+func TestStartSpanIsChildOfContextSpan(t *testing.T) {
+	// Given
+	recorder := useSpanRecorder(t)
+	ctx, parent := StartSpan(context.Background(), "parent")
+
+	// When
+	_, child := StartSpan(ctx, "loadMovies")
+	child.End()
+	parent.End()
+
+	// Then
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Given a parent and a child span, When both end, Then expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Errorf("Given a span started from a context holding a parent, When it ends, Then expected it to be the parent's child")
+	}
+}
+
+func TestTraceContextPropagatesThroughGRPCMetadata(t *testing.T) {
+	// Given
+	recorder := useSpanRecorder(t)
+	listener := bufconn.Listen(1 << 20)
+	var incoming metadata.MD
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			incoming, _ = metadata.FromIncomingContext(ctx)
+			return handler(ctx, req)
+		}),
+	)
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+	defer conn.Close()
+
+	// When
+	ctx, parent := StartSpan(context.Background(), "makeGetterRequest")
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	parent.End()
+	server.GracefulStop()
+
+	// Then
+	if err != nil {
+		t.Fatalf("Given a traced client, When calling the server, Then expected no error, got %v", err)
+	}
+	if len(incoming.Get("traceparent")) == 0 {
+		t.Errorf("Given a traced client, When calling the server, Then expected a traceparent header in the metadata, got %v", incoming)
+	}
+	traceID := parent.SpanContext().TraceID()
+	var serverSpans int
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() != traceID {
+			t.Errorf("Given a traced client, When calling the server, Then expected span %q in trace %s, got %s", span.Name(), traceID, span.SpanContext().TraceID())
+		}
+		if span.SpanKind().String() == "server" {
+			serverSpans++
+		}
+	}
+	if serverSpans != 1 {
+		t.Errorf("Given a traced client, When calling the server, Then expected one server span, got %d", serverSpans)
+	}
+}
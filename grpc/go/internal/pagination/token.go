@@ -0,0 +1,94 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 500
+)
+
+// ErrInvalidPageToken is returned when a page token is malformed or its signature does not match
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// Cursor identifies the last item of a page within a list sorted by (RatingsScore, MovieID)
+type Cursor struct {
+	MinimumRatingsScore float32 `json:"min"`
+	LastRatingsScore    float32 `json:"score"`
+	LastMovieID         string  `json:"id"`
+}
+
+// TokenCodec signs cursors into opaque page tokens and verifies them on the way back
+type TokenCodec struct {
+	key []byte
+}
+
+// NewTokenCodec creates a codec with the given signing key, generating a random one if empty
+func NewTokenCodec(key []byte) (*TokenCodec, error) {
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("could not generate page token key: %w", err)
+		}
+	}
+	return &TokenCodec{key: key}, nil
+}
+
+func (c *TokenCodec) Encode(cursor Cursor) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal cursor: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+func (c *TokenCodec) Decode(token string) (Cursor, error) {
+	var cursor Cursor
+
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return cursor, ErrInvalidPageToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return cursor, ErrInvalidPageToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return cursor, ErrInvalidPageToken
+	}
+	if !hmac.Equal(signature, c.sign(payload)) {
+		return cursor, ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return cursor, ErrInvalidPageToken
+	}
+
+	return cursor, nil
+}
+
+func (c *TokenCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// ResolvePageSize applies the default to an unset page size and caps it at MaxPageSize
+func ResolvePageSize(pageSize int32) int {
+	if pageSize <= 0 {
+		return DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		return MaxPageSize
+	}
+	return int(pageSize)
+}
@@ -0,0 +1,117 @@
+package pagination
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTokenCodecRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"zero cursor", Cursor{}},
+		{"populated cursor", Cursor{MinimumRatingsScore: 0.1, LastRatingsScore: 7.5, LastMovieID: "tt1234567"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			codec, err := NewTokenCodec([]byte("test-key"))
+			if err != nil {
+				t.Fatalf("Given a signing key, When creating a codec, Then expected no error, got %v", err)
+			}
+
+			// When
+			token, err := codec.Encode(tt.cursor)
+			if err != nil {
+				t.Fatalf("Given cursor %+v, When encoded, Then expected no error, got %v", tt.cursor, err)
+			}
+			decoded, err := codec.Decode(token)
+
+			// Then
+			if err != nil {
+				t.Fatalf("Given token %q, When decoded, Then expected no error, got %v", token, err)
+			}
+			if decoded != tt.cursor {
+				t.Errorf("Given cursor %+v, When round-tripped, Then expected the same cursor, got %+v", tt.cursor, decoded)
+			}
+		})
+	}
+}
+
+func TestTokenCodecRejectsTampering(t *testing.T) {
+	codec, _ := NewTokenCodec([]byte("test-key"))
+	otherCodec, _ := NewTokenCodec([]byte("other-key"))
+	token, _ := codec.Encode(Cursor{MinimumRatingsScore: 5, LastRatingsScore: 6, LastMovieID: "tt0000001"})
+	forged, _ := otherCodec.Encode(Cursor{MinimumRatingsScore: 0, LastRatingsScore: 6, LastMovieID: "tt0000001"})
+	payload, signature, _ := strings.Cut(token, ".")
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty token", ""},
+		{"missing signature", payload},
+		{"garbage", "not-a-token"},
+		{"invalid base64", "!!!." + signature},
+		{"swapped payload", forgedPayload + "." + signature},
+		{"signed with another key", forged},
+		{"truncated signature", payload + "." + signature[:len(signature)-2]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			_, err := codec.Decode(tt.token)
+
+			// Then
+			if !errors.Is(err, ErrInvalidPageToken) {
+				t.Errorf("Given token %q, When decoded, Then expected ErrInvalidPageToken, got %v", tt.token, err)
+			}
+		})
+	}
+}
+
+func TestNewTokenCodecGeneratesKey(t *testing.T) {
+	// Given
+	first, _ := NewTokenCodec(nil)
+	second, _ := NewTokenCodec(nil)
+	token, _ := first.Encode(Cursor{LastMovieID: "tt1234567"})
+
+	// When
+	_, err := second.Decode(token)
+
+	// Then
+	if !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("Given two codecs with generated keys, When decoding across them, Then expected ErrInvalidPageToken, got %v", err)
+	}
+}
+
+func TestResolvePageSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		pageSize int32
+		expected int
+	}{
+		{"unset", 0, DefaultPageSize},
+		{"negative", -1, DefaultPageSize},
+		{"within range", 25, 25},
+		{"at maximum", MaxPageSize, MaxPageSize},
+		{"above maximum", MaxPageSize + 1, MaxPageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			result := ResolvePageSize(tt.pageSize)
+
+			// Then
+			if result != tt.expected {
+				t.Errorf("Given page size %d, When resolved, Then expected %d, got %d", tt.pageSize, tt.expected, result)
+			}
+		})
+	}
+}
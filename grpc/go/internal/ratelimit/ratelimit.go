@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrRateLimited   = errors.New("rate limit exceeded")
+	ErrQuotaExceeded = errors.New("daily quota exceeded")
+)
+
+// Limits configures one key; zero values disable the corresponding limit
+type Limits struct {
+	// RequestsPerSecond is the rate the token bucket refills at
+	RequestsPerSecond float64
+	// Burst is the bucket capacity; it defaults to RequestsPerSecond rounded up
+	Burst int
+	// DailyQuota is the number of requests allowed per UTC day
+	DailyQuota int64
+}
+
+func (l Limits) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return math.Max(1, math.Ceil(l.RequestsPerSecond))
+}
+
+// Limiter enforces a token bucket and a daily quota per key. Quotas are held in memory,
+// so they reset when the process restarts.
+type Limiter struct {
+	now func() time.Time
+	// mu serialises SetLimits; Allow reads keys without it
+	mu   sync.Mutex
+	keys atomic.Pointer[map[string]*keyLimiter]
+}
+
+type keyLimiter struct {
+	mu         sync.Mutex
+	limits     Limits
+	tokens     float64
+	lastRefill time.Time
+	quotaDay   time.Time
+	quotaUsed  int64
+}
+
+// NewLimiter creates a limiter for the given keys; keys without an entry are never limited.
+// now is used as the clock and defaults to time.Now when nil.
+func NewLimiter(limits map[string]Limits, now func() time.Time) *Limiter {
+	if now == nil {
+		now = time.Now
+	}
+	l := &Limiter{now: now}
+	l.SetLimits(limits)
+	return l
+}
+
+// SetLimits replaces the keys and their limits. Keys that stay limited keep their bucket and the
+// quota used today under the new limits; new keys start with a full bucket.
+func (l *Limiter) SetLimits(limits map[string]Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var current map[string]*keyLimiter
+	if p := l.keys.Load(); p != nil {
+		current = *p
+	}
+	keys := make(map[string]*keyLimiter, len(limits))
+	start := l.now()
+	for key, limit := range limits {
+		if limit.RequestsPerSecond <= 0 && limit.DailyQuota <= 0 {
+			continue
+		}
+		if k, ok := current[key]; ok {
+			k.setLimits(limit)
+			keys[key] = k
+			continue
+		}
+		keys[key] = &keyLimiter{limits: limit, tokens: limit.burst(), lastRefill: start}
+	}
+	l.keys.Store(&keys)
+}
+
+// Limits returns the limits of every limited key
+func (l *Limiter) Limits() map[string]Limits {
+	keys := *l.keys.Load()
+	limits := make(map[string]Limits, len(keys))
+	for key, k := range keys {
+		k.mu.Lock()
+		limits[key] = k.limits
+		k.mu.Unlock()
+	}
+	return limits
+}
+
+// Allow charges one request to key. When the request is refused it returns
+// ErrRateLimited or ErrQuotaExceeded and how long the caller should wait before retrying.
+func (l *Limiter) Allow(key string) (time.Duration, error) {
+	k, ok := (*l.keys.Load())[key]
+	if !ok {
+		return 0, nil
+	}
+	return k.allow(l.now())
+}
+
+func (k *keyLimiter) setLimits(limits Limits) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.limits = limits
+	k.tokens = math.Min(k.tokens, limits.burst())
+}
+
+func (k *keyLimiter) allow(now time.Time) (time.Duration, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.limits.DailyQuota > 0 {
+		day := now.UTC().Truncate(24 * time.Hour)
+		if !day.Equal(k.quotaDay) {
+			k.quotaDay = day
+			k.quotaUsed = 0
+		}
+		if k.quotaUsed >= k.limits.DailyQuota {
+			return day.Add(24 * time.Hour).Sub(now), ErrQuotaExceeded
+		}
+	}
+
+	if k.limits.RequestsPerSecond > 0 {
+		if elapsed := now.Sub(k.lastRefill); elapsed > 0 {
+			k.tokens = math.Min(k.limits.burst(), k.tokens+elapsed.Seconds()*k.limits.RequestsPerSecond)
+			k.lastRefill = now
+		}
+		if k.tokens < 1 {
+			wait := (1 - k.tokens) / k.limits.RequestsPerSecond
+			return time.Duration(wait * float64(time.Second)), ErrRateLimited
+		}
+		k.tokens--
+	}
+
+	k.quotaUsed++
+	return 0, nil
+}
@@ -0,0 +1,171 @@
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestLimiterAllow(t *testing.T) {
+	start := time.Date(2025, 6, 1, 23, 59, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		limits             Limits
+		requests           int
+		advance            time.Duration
+		expectedErr        error
+		expectedRetryAfter time.Duration
+	}{
+		{
+			name:     "within burst",
+			limits:   Limits{RequestsPerSecond: 1, Burst: 3},
+			requests: 3,
+		},
+		{
+			name:               "burst exhausted",
+			limits:             Limits{RequestsPerSecond: 2, Burst: 3},
+			requests:           4,
+			expectedErr:        ErrRateLimited,
+			expectedRetryAfter: 500 * time.Millisecond,
+		},
+		{
+			name:     "bucket refills over time",
+			limits:   Limits{RequestsPerSecond: 2, Burst: 1},
+			requests: 1,
+			advance:  500 * time.Millisecond,
+		},
+		{
+			name:        "burst defaults to the rate",
+			limits:      Limits{RequestsPerSecond: 1.5},
+			requests:    3,
+			expectedErr: ErrRateLimited,
+			// two tokens spent, none left, one more needs two thirds of a second
+			expectedRetryAfter: 666666666 * time.Nanosecond,
+		},
+		{
+			name:               "daily quota exhausted",
+			limits:             Limits{DailyQuota: 2},
+			requests:           3,
+			expectedErr:        ErrQuotaExceeded,
+			expectedRetryAfter: time.Minute,
+		},
+		{
+			name:     "daily quota resets at UTC midnight",
+			limits:   Limits{DailyQuota: 2},
+			requests: 2,
+			advance:  time.Minute,
+		},
+		{
+			name:     "unlimited key",
+			limits:   Limits{},
+			requests: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			clock := &fakeClock{now: start}
+			limiter := NewLimiter(map[string]Limits{"key": tt.limits}, clock.Now)
+			for i := 0; i < tt.requests-1; i++ {
+				if _, err := limiter.Allow("key"); err != nil {
+					t.Fatalf("Given %s, When sending request %d, Then expected it to be allowed, got %v", tt.name, i+1, err)
+				}
+			}
+			clock.now = clock.now.Add(tt.advance)
+
+			// When
+			retryAfter, err := limiter.Allow("key")
+
+			// Then
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Given %s, When sending the last request, Then expected error %v, got %v", tt.name, tt.expectedErr, err)
+			}
+			if retryAfter != tt.expectedRetryAfter {
+				t.Errorf("Given %s, When sending the last request, Then expected retry after %v, got %v", tt.name, tt.expectedRetryAfter, retryAfter)
+			}
+		})
+	}
+}
+
+func TestLimiterRefusedRequestsAreNotCharged(t *testing.T) {
+	// Given
+	clock := &fakeClock{now: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	limiter := NewLimiter(map[string]Limits{"key": {RequestsPerSecond: 1, Burst: 1, DailyQuota: 2}}, clock.Now)
+	if _, err := limiter.Allow("key"); err != nil {
+		t.Fatalf("could not send first request: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := limiter.Allow("key"); !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("Given an empty bucket, When sending, Then expected ErrRateLimited, got %v", err)
+		}
+	}
+	clock.now = clock.now.Add(time.Second)
+
+	// When
+	_, err := limiter.Allow("key")
+
+	// Then
+	if err != nil {
+		t.Errorf("Given rate-limited requests, When the bucket refills, Then expected the quota to still have room, got %v", err)
+	}
+}
+
+func TestLimiterSetLimits(t *testing.T) {
+	// Given a limited key that has spent its burst, and an unlimited one
+	clock := &fakeClock{now: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	limiter := NewLimiter(map[string]Limits{"kept": {RequestsPerSecond: 1, Burst: 2, DailyQuota: 10}}, clock.Now)
+	for i := 0; i < 2; i++ {
+		if _, err := limiter.Allow("kept"); err != nil {
+			t.Fatalf("could not send request %d: %v", i+1, err)
+		}
+	}
+
+	// When the limits are replaced, adding a key
+	limiter.SetLimits(map[string]Limits{
+		"kept":  {RequestsPerSecond: 1, Burst: 5, DailyQuota: 3},
+		"added": {DailyQuota: 1},
+	})
+
+	// Then the kept key has no tokens back
+	if _, err := limiter.Allow("kept"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Given a spent bucket, When the limits are replaced, Then expected the bucket to stay spent, got %v", err)
+	}
+	// and counts its earlier requests against the new quota
+	clock.now = clock.now.Add(time.Minute)
+	if _, err := limiter.Allow("kept"); err != nil {
+		t.Fatalf("Given a refilled bucket, When sending, Then expected it to be allowed, got %v", err)
+	}
+	if _, err := limiter.Allow("kept"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Given 3 requests today, When the quota is lowered to 3, Then expected ErrQuotaExceeded, got %v", err)
+	}
+	// and the added key is limited
+	if _, err := limiter.Allow("added"); err != nil {
+		t.Fatalf("Given an added key, When sending its first request, Then expected it to be allowed, got %v", err)
+	}
+	if _, err := limiter.Allow("added"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Given an added key with a quota of 1, When sending a second request, Then expected ErrQuotaExceeded, got %v", err)
+	}
+}
+
+func TestLimiterUnknownKey(t *testing.T) {
+	// Given
+	limiter := NewLimiter(map[string]Limits{"key": {DailyQuota: 1}}, nil)
+
+	// When
+	retryAfter, err := limiter.Allow("other")
+
+	// Then
+	if err != nil || retryAfter != 0 {
+		t.Errorf("Given a key without limits, When sending, Then expected it to be allowed, got %v (retry after %v)", err, retryAfter)
+	}
+}
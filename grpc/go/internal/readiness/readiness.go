@@ -0,0 +1,175 @@
+// Package readiness derives gRPC health statuses from checks of the server's state
+package readiness
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	grpc_health_v1 "google.golang.org/grpc/health/grpc_health_v1"
+
+	"case-studies/grpc/internal/observability"
+)
+
+// Health service names probes can ask for. Liveness, like the empty name, stays SERVING while the
+// process can answer; readiness and the API services follow the checks.
+const (
+	LivenessService  = "liveness"
+	ReadinessService = "readiness"
+)
+
+// Check reports why the server should not take traffic, or nil when it can
+type Check struct {
+	Name string
+	Run  func() error
+}
+
+// Monitor runs checks and publishes the outcome on a health server
+type Monitor struct {
+	health   *health.Server
+	services []string
+	checks   []Check
+
+	mu      sync.Mutex
+	failing map[string]error
+}
+
+// NewMonitor reports liveness at once and readiness from a first run of the checks. services are
+// the API services that share the readiness status.
+func NewMonitor(healthServer *health.Server, services []string, checks ...Check) *Monitor {
+	m := &Monitor{health: healthServer, services: services, checks: checks, failing: map[string]error{}}
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(LivenessService, grpc_health_v1.HealthCheckResponse_SERVING)
+	m.Update()
+	return m
+}
+
+// Update runs every check and sets the readiness status, logging only checks that start or stop failing
+func (m *Monitor) Update() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []error
+	for _, check := range m.checks {
+		err := check.Run()
+		_, wasFailing := m.failing[check.Name]
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", check.Name, err))
+			m.failing[check.Name] = err
+			if !wasFailing {
+				observability.LogError("readiness-check", "Update", err, map[string]interface{}{
+					"check": check.Name,
+				})
+			}
+		case wasFailing:
+			delete(m.failing, check.Name)
+			observability.LogSuccess("readiness-check", "Update", map[string]interface{}{
+				"check": check.Name,
+			})
+		}
+	}
+
+	servingStatus := grpc_health_v1.HealthCheckResponse_SERVING
+	if len(errs) > 0 {
+		servingStatus = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+	m.health.SetServingStatus(ReadinessService, servingStatus)
+	for _, service := range m.services {
+		m.health.SetServingStatus(service, servingStatus)
+	}
+	return errors.Join(errs...)
+}
+
+// Run updates the statuses every interval until ctx is done
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Update()
+		}
+	}
+}
+
+// Handler serves /healthz and /readyz, for probes that cannot speak gRPC. /healthz answers 200
+// while the process can answer at all, so a liveness probe never restarts a server that is
+// draining; /readyz follows the readiness status and answers 503 once the health server is shut down.
+func (m *Monitor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, grpc_health_v1.HealthCheckResponse_SERVING)
+	}))
+	mux.Handle("/readyz", m.statusHandler(ReadinessService))
+	return mux
+}
+
+// ListenAndServe serves Handler on port until ctx is cancelled, for servers whose metrics
+// listener is disabled or should not be reachable by probes
+func (m *Monitor) ListenAndServe(ctx context.Context, port int) error {
+	server := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: m.Handler(), ReadHeaderTimeout: 5 * time.Second}
+	stop := context.AfterFunc(ctx, func() { server.Shutdown(context.Background()) })
+	defer stop()
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (m *Monitor) statusHandler(service string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		servingStatus := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		if resp, err := m.health.Check(r.Context(), &grpc_health_v1.HealthCheckRequest{Service: service}); err == nil {
+			servingStatus = resp.GetStatus()
+		}
+		writeStatus(w, servingStatus)
+	})
+}
+
+// writeStatus answers 200 for SERVING and 503 otherwise, with the status as the body
+func writeStatus(w http.ResponseWriter, servingStatus grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	code := http.StatusOK
+	if servingStatus != grpc_health_v1.HealthCheckResponse_SERVING {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	fmt.Fprintln(w, servingStatus)
+}
+
+// NotEmpty fails while count reports nothing loaded
+func NotEmpty(name string, count func() int) Check {
+	return Check{Name: name, Run: func() error {
+		if count() == 0 {
+			return errors.New("nothing loaded")
+		}
+		return nil
+	}}
+}
+
+// CertificateValid fails when the certificate is not yet valid, has expired or expires within minValidity
+func CertificateValid(name string, certificate func() *x509.Certificate, minValidity time.Duration, now func() time.Time) Check {
+	return Check{Name: name, Run: func() error {
+		cert := certificate()
+		if cert == nil {
+			return errors.New("no certificate loaded")
+		}
+		t := now()
+		switch {
+		case t.Before(cert.NotBefore):
+			return fmt.Errorf("certificate is not valid until %s", cert.NotBefore.UTC().Format(time.RFC3339))
+		case !t.Add(minValidity).Before(cert.NotAfter):
+			return fmt.Errorf("certificate expires at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,222 @@
+package readiness
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/health"
+	grpc_health_v1 "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func servingStatus(t *testing.T, healthServer *health.Server, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := healthServer.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("could not check %q: %v", service, err)
+	}
+	return resp.GetStatus()
+}
+
+func TestMonitorUpdate(t *testing.T) {
+	tests := []struct {
+		name              string
+		checkErr          error
+		expectedReadiness grpc_health_v1.HealthCheckResponse_ServingStatus
+	}{
+		{
+			name:              "passing checks",
+			expectedReadiness: grpc_health_v1.HealthCheckResponse_SERVING,
+		},
+		{
+			name:              "a failing check",
+			checkErr:          errors.New("nothing loaded"),
+			expectedReadiness: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			healthServer := health.NewServer()
+
+			// When
+			NewMonitor(healthServer, []string{"movie.Getter"},
+				Check{Name: "ok", Run: func() error { return nil }},
+				Check{Name: "data", Run: func() error { return tt.checkErr }},
+			)
+
+			// Then
+			for _, service := range []string{ReadinessService, "movie.Getter"} {
+				if got := servingStatus(t, healthServer, service); got != tt.expectedReadiness {
+					t.Errorf("Given %s, When monitoring, Then expected %s to be %v, got %v", tt.name, service, tt.expectedReadiness, got)
+				}
+			}
+			for _, service := range []string{"", LivenessService} {
+				if got := servingStatus(t, healthServer, service); got != grpc_health_v1.HealthCheckResponse_SERVING {
+					t.Errorf("Given %s, When monitoring, Then expected %q to stay SERVING, got %v", tt.name, service, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMonitorRecovers(t *testing.T) {
+	// Given
+	healthServer := health.NewServer()
+	checkErr := errors.New("reload failed")
+	monitor := NewMonitor(healthServer, nil, Check{Name: "reload", Run: func() error { return checkErr }})
+
+	// When
+	checkErr = nil
+	err := monitor.Update()
+
+	// Then
+	if err != nil {
+		t.Errorf("Given a check that stopped failing, When updating, Then expected no error, got %v", err)
+	}
+	if got := servingStatus(t, healthServer, ReadinessService); got != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("Given a check that stopped failing, When updating, Then expected readiness SERVING, got %v", got)
+	}
+}
+
+func TestMonitorHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		ready        bool
+		shutdown     bool
+		path         string
+		expectedCode int
+	}{
+		{name: "liveness of a live server", path: "/healthz", expectedCode: http.StatusOK},
+		{name: "readiness of a ready server", ready: true, path: "/readyz", expectedCode: http.StatusOK},
+		{name: "readiness of a server that is not ready", path: "/readyz", expectedCode: http.StatusServiceUnavailable},
+		{name: "liveness of a server that is not ready", path: "/healthz", expectedCode: http.StatusOK},
+		{name: "liveness of a server draining", ready: true, shutdown: true, path: "/healthz", expectedCode: http.StatusOK},
+		{name: "readiness of a server shutting down", ready: true, shutdown: true, path: "/readyz", expectedCode: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			healthServer := health.NewServer()
+			monitor := NewMonitor(healthServer, nil, Check{Name: "data", Run: func() error {
+				if tt.ready {
+					return nil
+				}
+				return errors.New("nothing loaded")
+			}})
+			if tt.shutdown {
+				healthServer.Shutdown()
+			}
+			recorder := httptest.NewRecorder()
+
+			// When
+			monitor.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			// Then
+			if recorder.Code != tt.expectedCode {
+				t.Errorf("Given the %s, When requesting %s, Then expected status %d, got %d", tt.name, tt.path, tt.expectedCode, recorder.Code)
+			}
+		})
+	}
+}
+
+func TestMonitorListenAndServe(t *testing.T) {
+	// Given
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not find a free port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	monitor := NewMonitor(health.NewServer(), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- monitor.ListenAndServe(ctx, port) }()
+
+	// When
+	var resp *http.Response
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if resp, err = http.Get(fmt.Sprintf("http://127.0.0.1:%d/healthz", port)); err == nil {
+			break
+		}
+	}
+	cancel()
+
+	// Then
+	if err != nil {
+		t.Fatalf("Given a probe listener, When requesting /healthz, Then expected a response, got %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Given a probe listener, When requesting /healthz, Then expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Given a cancelled context, When serving probes, Then expected no error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Given a cancelled context, When serving probes, Then expected the listener to stop")
+	}
+}
+
+func TestCertificateValid(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		cert    *x509.Certificate
+		wantErr bool
+	}{
+		{name: "certificate valid for a year", cert: &x509.Certificate{NotBefore: now.Add(-time.Hour), NotAfter: now.AddDate(1, 0, 0)}},
+		{name: "certificate expiring within the minimum validity", cert: &x509.Certificate{NotBefore: now.Add(-time.Hour), NotAfter: now.Add(time.Hour)}, wantErr: true},
+		{name: "expired certificate", cert: &x509.Certificate{NotBefore: now.AddDate(-1, 0, 0), NotAfter: now.Add(-time.Hour)}, wantErr: true},
+		{name: "certificate not yet valid", cert: &x509.Certificate{NotBefore: now.Add(time.Hour), NotAfter: now.AddDate(1, 0, 0)}, wantErr: true},
+		{name: "missing certificate", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			check := CertificateValid("server-certificate", func() *x509.Certificate { return tt.cert }, 24*time.Hour, func() time.Time { return now })
+
+			// When
+			err := check.Run()
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Given a %s, When checking it, Then expected error %v, got %v", tt.name, tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNotEmpty(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		wantErr bool
+	}{
+		{name: "loaded catalogue", count: 3},
+		{name: "empty catalogue", count: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			err := NotEmpty("movie-catalogue", func() int { return tt.count }).Run()
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Given a %s, When checking it, Then expected error %v, got %v", tt.name, tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Package shutdown stops servers without cutting off the calls they are handling
+package shutdown
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+)
+
+// Options bounds how long a server takes to stop
+type Options struct {
+	// DrainPeriod is how long the server keeps serving after it reports NOT_SERVING, so load
+	// balancers and Kubernetes endpoints stop routing new calls to it
+	DrainPeriod time.Duration
+	// Timeout is how long calls still running after the drain may take before they are cut off
+	Timeout time.Duration
+	// BeforeStop, if set, runs after the drain and before the server stops accepting calls, to end
+	// streams that would otherwise stay open until the timeout
+	BeforeStop func()
+}
+
+// NotifyContext returns a context that is cancelled on SIGINT or SIGTERM
+func NotifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
+
+// GRPC reports every service NOT_SERVING, waits for the drain period, runs BeforeStop, then stops
+// accepting calls and waits up to the timeout for running ones before closing them. It reports
+// whether every call finished in time.
+func GRPC(server *grpc.Server, healthServer *health.Server, options Options) bool {
+	if healthServer != nil {
+		healthServer.Shutdown()
+	}
+	time.Sleep(options.DrainPeriod)
+	if options.BeforeStop != nil {
+		options.BeforeStop()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(options.Timeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+		return true
+	case <-timer.C:
+		server.Stop()
+		<-stopped
+		return false
+	}
+}
+
+// HTTP stops reusing connections, waits for the drain period, then closes the listeners and waits
+// up to the timeout for running requests before closing their connections
+func HTTP(server *http.Server, options Options) error {
+	server.SetKeepAlivesEnabled(false)
+	time.Sleep(options.DrainPeriod)
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.Timeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		server.Close()
+		return err
+	}
+	return nil
+}
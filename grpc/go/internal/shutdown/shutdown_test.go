@@ -0,0 +1,185 @@
+package shutdown
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	grpc_health_v1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// slowService is the health check name the test server holds until released
+const slowService = "slow"
+
+func newTestGRPCServer(t *testing.T, started chan<- struct{}, release <-chan struct{}) (*grpc.Server, *health.Server, grpc_health_v1.HealthClient) {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if r, ok := req.(*grpc_health_v1.HealthCheckRequest); ok && r.Service == slowService {
+			close(started)
+			<-release
+		}
+		return handler(ctx, req)
+	}))
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(slowService, grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return server, healthServer, grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestGRPCDrainsRunningCalls(t *testing.T) {
+	// Given
+	started, release := make(chan struct{}), make(chan struct{})
+	server, healthServer, client := newTestGRPCServer(t, started, release)
+	callErr := make(chan error, 1)
+	go func() {
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: slowService})
+		callErr <- err
+	}()
+	<-started
+
+	// When
+	graceful := make(chan bool, 1)
+	go func() {
+		graceful <- GRPC(server, healthServer, Options{DrainPeriod: 200 * time.Millisecond, Timeout: 5 * time.Second})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	resp, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	close(release)
+
+	// Then
+	if err != nil || resp.Status != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Given a draining server, When checking its health, Then expected NOT_SERVING, got %v, %v", resp, err)
+	}
+	if err := <-callErr; status.Code(err) == codes.Unavailable || status.Code(err) == codes.Canceled {
+		t.Errorf("Given a call running at shutdown, When the server drains, Then expected it to complete, got %v", err)
+	}
+	if !<-graceful {
+		t.Errorf("Given calls that finish within the timeout, When stopping, Then expected a graceful stop")
+	}
+}
+
+func TestGRPCStopsCallsAfterTimeout(t *testing.T) {
+	// Given
+	server, healthServer, client := newTestGRPCServer(t, make(chan struct{}), nil)
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("could not watch health: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("could not receive the first health status: %v", err)
+	}
+
+	// When
+	start := time.Now()
+	graceful := GRPC(server, healthServer, Options{Timeout: 100 * time.Millisecond})
+
+	// Then
+	if graceful {
+		t.Errorf("Given a stream that never ends, When stopping, Then expected the stop not to be graceful")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Given a 100ms timeout, When stopping, Then expected the server to stop promptly, took %v", elapsed)
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+}
+
+func TestGRPCRunsBeforeStop(t *testing.T) {
+	// Given a call that only ends once the server is stopping
+	started, release := make(chan struct{}), make(chan struct{})
+	server, healthServer, client := newTestGRPCServer(t, started, release)
+	callErr := make(chan error, 1)
+	go func() {
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: slowService})
+		callErr <- err
+	}()
+	<-started
+
+	// When
+	start := time.Now()
+	graceful := GRPC(server, healthServer, Options{Timeout: 5 * time.Second, BeforeStop: func() { close(release) }})
+
+	// Then
+	if !graceful {
+		t.Errorf("Given a call BeforeStop ends, When stopping, Then expected a graceful stop")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Given a call BeforeStop ends, When stopping, Then expected the server not to wait for the timeout, took %v", elapsed)
+	}
+	if err := <-callErr; err != nil {
+		t.Errorf("Given a call BeforeStop ends, When stopping, Then expected it to complete, got %v", err)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	tests := []struct {
+		name        string
+		handlerTime time.Duration
+		timeout     time.Duration
+		wantErr     bool
+	}{
+		{
+			name:        "request finishing within the timeout",
+			handlerTime: 100 * time.Millisecond,
+			timeout:     5 * time.Second,
+		},
+		{
+			name:        "request outlasting the timeout",
+			handlerTime: 5 * time.Second,
+			timeout:     100 * time.Millisecond,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			started := make(chan struct{})
+			server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				select {
+				case <-time.After(tt.handlerTime):
+				case <-r.Context().Done():
+				}
+			})}
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("could not listen: %v", err)
+			}
+			go server.Serve(listener)
+			go http.Get("http://" + listener.Addr().String())
+			<-started
+
+			// When
+			err = HTTP(server, Options{Timeout: tt.timeout})
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Given a %s, When stopping, Then expected error %v, got %v", tt.name, tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+// Package tlsconfig builds TLS configurations that pick up rotated certificates without a restart
+package tlsconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+
+	"case-studies/grpc/internal/metrics"
+	"case-studies/grpc/internal/observability"
+)
+
+// Files names the PEM files of a key pair and of the CA certificates that verify the peer. A
+// client without CertFile and KeyFile presents no certificate; a server without CAFile does not
+// ask for one.
+type Files struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// Options configures how often a Reloader looks at its files and when it warns about expiry
+type Options struct {
+	// CheckInterval is the least time between two reads of the files
+	CheckInterval time.Duration
+	// ExpiryWarning logs a warning, once a day, for each certificate expiring within it
+	ExpiryWarning time.Duration
+	// Metrics receives the days until expiry of each certificate; nil records nothing
+	Metrics *metrics.Metrics
+	// MinVersion defaults to TLS 1.2
+	MinVersion uint16
+	// CipherSuites restricts the TLS 1.2 cipher suites; nil keeps Go's defaults
+	CipherSuites []uint16
+	// Now defaults to time.Now
+	Now func() time.Time
+}
+
+// Reloader holds a key pair and CA pool loaded from files and re-reads them once they change.
+// A changed file that does not parse leaves the last good material in use.
+type Reloader struct {
+	files    Files
+	options  Options
+	material atomic.Pointer[material]
+
+	// Serialises reloads and guards checkedAt and warnedDays
+	mu         sync.Mutex
+	checkedAt  time.Time
+	warnedDays map[[sha256.Size]byte]int
+}
+
+// material is an immutable set of loaded certificates; cert and caPool are nil when their files
+// are not configured
+type material struct {
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+	caCerts  []*x509.Certificate
+	checksum [sha256.Size]byte
+}
+
+// NewReloader loads files, failing if any of them is missing or invalid
+func NewReloader(files Files, options Options) (*Reloader, error) {
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	r := &Reloader{files: files, options: options, warnedDays: map[[sha256.Size]byte]int{}}
+
+	content, checksum, err := r.readFiles()
+	if err != nil {
+		return nil, err
+	}
+	m, err := r.parseMaterial(content, checksum)
+	if err != nil {
+		return nil, err
+	}
+	r.material.Store(m)
+	r.checkedAt = options.Now()
+	r.logLoaded(m)
+	r.reportExpiry(m)
+	return r, nil
+}
+
+// Reload re-reads the files and swaps in their certificates if the content changed. It also
+// refreshes the expiry metrics and warnings, which change with time alone.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reloadLocked()
+}
+
+func (r *Reloader) reloadLocked() error {
+	r.checkedAt = r.options.Now()
+	current := r.material.Load()
+	defer func() { r.reportExpiry(r.material.Load()) }()
+
+	content, checksum, err := r.readFiles()
+	if err == nil && checksum == current.checksum {
+		return nil
+	}
+	var next *material
+	if err == nil {
+		next, err = r.parseMaterial(content, checksum)
+	}
+	if err != nil {
+		observability.LogError("tls-reload", "reloadLocked", err, map[string]interface{}{
+			"cert_file": r.files.CertFile,
+			"ca_file":   r.files.CAFile,
+		})
+		return err
+	}
+
+	r.material.Store(next)
+	r.logLoaded(next)
+	return nil
+}
+
+// reloadIfDue reloads when CheckInterval has passed since the files were last read, so the
+// handshake callbacks notice rotated files without a goroutine per connection
+func (r *Reloader) reloadIfDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.options.Now().Sub(r.checkedAt) >= r.options.CheckInterval {
+		r.reloadLocked()
+	}
+}
+
+// Watch reloads every CheckInterval until ctx is done, keeping the expiry metrics current on a
+// server that may go a long time between handshakes
+func (r *Reloader) Watch(ctx context.Context) {
+	if r.options.CheckInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.options.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Reload()
+		}
+	}
+}
+
+// Certificate returns the leaf of the key pair in use, or nil without one
+func (r *Reloader) Certificate() *x509.Certificate {
+	if cert := r.material.Load().cert; cert != nil {
+		return cert.Leaf
+	}
+	return nil
+}
+
+// ServerConfig chooses the key pair and CA pool for each handshake from the latest files, and
+// requires and verifies client certificates when a CA file is configured
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:   r.options.MinVersion,
+		CipherSuites: r.options.CipherSuites,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.reloadIfDue()
+			m := r.material.Load()
+			config := &tls.Config{
+				Certificates: []tls.Certificate{*m.cert},
+				MinVersion:   r.options.MinVersion,
+				CipherSuites: r.options.CipherSuites,
+			}
+			if m.caPool != nil {
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+				config.ClientCAs = m.caPool
+			}
+			return config, nil
+		},
+	}
+}
+
+// ClientConfig presents the latest client key pair, if any, and verifies the server against the
+// latest CA pool, or the system roots without a CA file. An empty serverName verifies the host
+// being dialled. The CA pool is read when the config is built, so a long-lived config misses a
+// rotated CA; ClientCredentials builds a fresh one for every handshake.
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	r.reloadIfDue()
+	return &tls.Config{
+		ServerName:   serverName,
+		RootCAs:      r.material.Load().caPool,
+		MinVersion:   r.options.MinVersion,
+		CipherSuites: r.options.CipherSuites,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert := r.material.Load().cert; cert != nil {
+				return cert, nil
+			}
+			return &tls.Certificate{}, nil
+		},
+	}
+}
+
+// ClientCredentials secures gRPC connections with a ClientConfig built for each handshake, so
+// reconnections trust a rotated CA while keeping Go's verification of the server name, including
+// IP addresses, which SNI does not carry
+func (r *Reloader) ClientCredentials(serverName string) credentials.TransportCredentials {
+	return &clientCredentials{reloader: r, serverName: serverName}
+}
+
+type clientCredentials struct {
+	reloader   *Reloader
+	serverName string
+}
+
+func (c *clientCredentials) current() credentials.TransportCredentials {
+	return credentials.NewTLS(c.reloader.ClientConfig(c.serverName))
+}
+
+// ClientHandshake verifies the server against the dialled host when no server name is configured
+func (c *clientCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return c.current().ClientHandshake(ctx, authority, conn)
+}
+
+func (c *clientCredentials) ServerHandshake(net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("tls: client credentials cannot accept connections")
+}
+
+func (c *clientCredentials) Info() credentials.ProtocolInfo {
+	return c.current().Info()
+}
+
+func (c *clientCredentials) Clone() credentials.TransportCredentials {
+	clone := *c
+	return &clone
+}
+
+// OverrideServerName is deprecated in gRPC but still part of TransportCredentials
+func (c *clientCredentials) OverrideServerName(serverName string) error {
+	c.serverName = serverName
+	return nil
+}
+
+func (r *Reloader) readFiles() ([3][]byte, [sha256.Size]byte, error) {
+	var content [3][]byte
+	hash := sha256.New()
+	for i, path := range []string{r.files.CertFile, r.files.KeyFile, r.files.CAFile} {
+		if path == "" {
+			continue
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return content, [sha256.Size]byte{}, err
+		}
+		content[i] = b
+		hash.Write(b)
+	}
+	var checksum [sha256.Size]byte
+	copy(checksum[:], hash.Sum(nil))
+	return content, checksum, nil
+}
+
+func (r *Reloader) parseMaterial(content [3][]byte, checksum [sha256.Size]byte) (*material, error) {
+	m := &material{checksum: checksum}
+	if r.files.CertFile != "" || r.files.KeyFile != "" {
+		cert, err := tls.X509KeyPair(content[0], content[1])
+		if err != nil {
+			return nil, fmt.Errorf("could not load key pair: %w", err)
+		}
+		if cert.Leaf == nil {
+			if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+				return nil, fmt.Errorf("could not parse certificate: %w", err)
+			}
+		}
+		m.cert = &cert
+	}
+	if r.files.CAFile == "" {
+		return m, nil
+	}
+
+	var caCerts []*x509.Certificate
+	rest := content[2]
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		caCert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse CA certificate: %w", err)
+		}
+		caCerts = append(caCerts, caCert)
+	}
+	if len(caCerts) == 0 {
+		return nil, errors.New("CA file holds no certificates")
+	}
+	m.caPool = x509.NewCertPool()
+	for _, caCert := range caCerts {
+		m.caPool.AddCert(caCert)
+	}
+	m.caCerts = caCerts
+
+	return m, nil
+}
+
+func (r *Reloader) logLoaded(m *material) {
+	fields := map[string]interface{}{
+		"ca_file":         r.files.CAFile,
+		"ca_certificates": len(m.caCerts),
+	}
+	if m.cert != nil {
+		fields["cert_file"] = r.files.CertFile
+		fields["subject"] = m.cert.Leaf.Subject.String()
+		fields["not_after"] = m.cert.Leaf.NotAfter.UTC().Format(time.RFC3339)
+		fields["days_until_expiry"] = daysUntil(m.cert.Leaf.NotAfter, r.options.Now())
+	}
+	observability.LogSuccess("tls-load", "Reloader", fields)
+}
+
+// reportExpiry exports days until expiry and warns about certificates within ExpiryWarning,
+// at most once per certificate per day
+func (r *Reloader) reportExpiry(m *material) {
+	now := r.options.Now()
+	certFileDays := map[string]float64{}
+	caFileDays := map[string]float64{}
+
+	report := func(file string, cert *x509.Certificate, days map[string]float64) {
+		remaining := daysUntil(cert.NotAfter, now)
+		days[cert.Subject.String()] = remaining
+		if cert.NotAfter.Sub(now) >= r.options.ExpiryWarning {
+			return
+		}
+		fingerprint := sha256.Sum256(cert.Raw)
+		wholeDays := int(math.Floor(remaining))
+		if warned, ok := r.warnedDays[fingerprint]; ok && warned == wholeDays {
+			return
+		}
+		r.warnedDays[fingerprint] = wholeDays
+		observability.LogWarning("tls-certificate-expiry", "reportExpiry", map[string]interface{}{
+			"file":              file,
+			"subject":           cert.Subject.String(),
+			"not_after":         cert.NotAfter.UTC().Format(time.RFC3339),
+			"days_until_expiry": remaining,
+		})
+	}
+
+	if m.cert != nil {
+		report(r.files.CertFile, m.cert.Leaf, certFileDays)
+		r.options.Metrics.SetCertificateExpiry(r.files.CertFile, certFileDays)
+	}
+	if r.files.CAFile != "" {
+		for _, caCert := range m.caCerts {
+			report(r.files.CAFile, caCert, caFileDays)
+		}
+		r.options.Metrics.SetCertificateExpiry(r.files.CAFile, caFileDays)
+	}
+}
+
+// daysUntil is rounded to hundredths to keep log lines readable
+func daysUntil(t, now time.Time) float64 {
+	return math.Round(t.Sub(now).Hours()/24*100) / 100
+}
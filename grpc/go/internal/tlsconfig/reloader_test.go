@@ -0,0 +1,397 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate CA key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create CA certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM key pair for commonName, valid for both server and client authentication
+func (ca testCA) issue(t *testing.T, commonName string, notAfter time.Time) (certPEM, keyPEM []byte) {
+	t.Helper()
+	return ca.issueFor(t, commonName, notAfter, []string{"localhost"}, nil)
+}
+
+// issueFor is issue with the DNS and IP subject alternative names given
+func (ca testCA) issueFor(t *testing.T, commonName string, notAfter time.Time, dnsNames []string, ipAddresses []net.IP) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		IPAddresses:  ipAddresses,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not encode key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFiles writes a key pair issued by ca, and ca itself, into dir
+func writeFiles(t *testing.T, dir string, ca testCA, commonName string) Files {
+	t.Helper()
+	files := Files{
+		CertFile: filepath.Join(dir, commonName+"-public.key"),
+		KeyFile:  filepath.Join(dir, commonName+"-private.key"),
+		CAFile:   filepath.Join(dir, "ca-public.key"),
+	}
+	certPEM, keyPEM := ca.issue(t, commonName, time.Now().AddDate(0, 3, 0))
+	for path, content := range map[string][]byte{files.CertFile: certPEM, files.KeyFile: keyPEM, files.CAFile: ca.pem} {
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			t.Fatalf("could not write %s: %v", path, err)
+		}
+	}
+	return files
+}
+
+func TestReloaderReload(t *testing.T) {
+	tests := []struct {
+		name            string
+		rotate          func(t *testing.T, files Files)
+		wantErr         bool
+		expectedSubject string
+	}{
+		{
+			name:            "unchanged files",
+			rotate:          func(t *testing.T, files Files) {},
+			expectedSubject: "CN=server",
+		},
+		{
+			name: "rotated key pair",
+			rotate: func(t *testing.T, files Files) {
+				certPEM, keyPEM := newTestCA(t).issue(t, "server-rotated", time.Now().AddDate(1, 0, 0))
+				os.WriteFile(files.CertFile, certPEM, 0o600)
+				os.WriteFile(files.KeyFile, keyPEM, 0o600)
+			},
+			expectedSubject: "CN=server-rotated",
+		},
+		{
+			name: "half-written certificate",
+			rotate: func(t *testing.T, files Files) {
+				os.WriteFile(files.CertFile, []byte("-----BEGIN CERTIFICATE-----\n"), 0o600)
+			},
+			wantErr:         true,
+			expectedSubject: "CN=server",
+		},
+		{
+			name: "CA file without certificates",
+			rotate: func(t *testing.T, files Files) {
+				os.WriteFile(files.CAFile, []byte("not a certificate"), 0o600)
+			},
+			wantErr:         true,
+			expectedSubject: "CN=server",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			files := writeFiles(t, t.TempDir(), newTestCA(t), "server")
+			reloader, err := NewReloader(files, Options{CheckInterval: time.Minute, ExpiryWarning: 30 * 24 * time.Hour})
+			if err != nil {
+				t.Fatalf("could not create reloader: %v", err)
+			}
+			tt.rotate(t, files)
+
+			// When
+			err = reloader.Reload()
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Given %s, When reloading, Then expected error %v, got %v", tt.name, tt.wantErr, err)
+			}
+			if got := reloader.Certificate().Subject.String(); got != tt.expectedSubject {
+				t.Errorf("Given %s, When reloading, Then expected certificate %s in use, got %s", tt.name, tt.expectedSubject, got)
+			}
+		})
+	}
+}
+
+func TestNewReloaderErrors(t *testing.T) {
+	// Given
+	files := writeFiles(t, t.TempDir(), newTestCA(t), "server")
+	os.Remove(files.KeyFile)
+
+	// When
+	_, err := NewReloader(files, Options{CheckInterval: time.Minute})
+
+	// Then
+	if err == nil {
+		t.Errorf("Given a missing key file, When creating a reloader, Then expected an error, got nil")
+	}
+}
+
+func TestReloaderChecksFilesAtMostOncePerInterval(t *testing.T) {
+	// Given
+	now := time.Now()
+	files := writeFiles(t, t.TempDir(), newTestCA(t), "server")
+	reloader, err := NewReloader(files, Options{CheckInterval: time.Minute, Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatalf("could not create reloader: %v", err)
+	}
+	certPEM, keyPEM := newTestCA(t).issue(t, "server-rotated", time.Now().AddDate(1, 0, 0))
+	os.WriteFile(files.CertFile, certPEM, 0o600)
+	os.WriteFile(files.KeyFile, keyPEM, 0o600)
+
+	// When
+	reloader.reloadIfDue()
+	beforeInterval := reloader.Certificate().Subject.String()
+	now = now.Add(time.Minute)
+	reloader.reloadIfDue()
+	afterInterval := reloader.Certificate().Subject.String()
+
+	// Then
+	if beforeInterval != "CN=server" {
+		t.Errorf("Given files checked moments ago, When a handshake starts, Then expected the loaded certificate, got %s", beforeInterval)
+	}
+	if afterInterval != "CN=server-rotated" {
+		t.Errorf("Given the check interval has passed, When a handshake starts, Then expected the rotated certificate, got %s", afterInterval)
+	}
+}
+
+// handshake runs a mutual TLS handshake between serverConfig and the client credentials over
+// loopback, dialling authority as a gRPC client would
+func handshake(t *testing.T, serverConfig *tls.Config, clientCreds credentials.TransportCredentials, authority string) error {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	defer listener.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		server, _, err := credentials.NewTLS(serverConfig).ServerHandshake(conn)
+		if err == nil {
+			// TLS 1.3 clients finish before the server has checked their certificate
+			_, err = server.Write([]byte{0})
+		}
+		serverErr <- err
+	}()
+
+	conn, err := net.DialTimeout("tcp", listener.Addr().String(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("could not dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	client, _, err := clientCreds.ClientHandshake(context.Background(), authority, conn)
+	if err == nil {
+		_, err = client.Read(make([]byte, 1))
+		client.Close()
+	}
+	if sErr := <-serverErr; err == nil {
+		err = sErr
+	}
+	return err
+}
+
+func TestReloaderHandshake(t *testing.T) {
+	tests := []struct {
+		name    string
+		rotate  func(t *testing.T, serverFiles, clientFiles Files)
+		wantErr bool
+	}{
+		{
+			name:   "certificates from a shared CA",
+			rotate: func(t *testing.T, serverFiles, clientFiles Files) {},
+		},
+		{
+			name: "server rotated to a certificate the client does not trust",
+			rotate: func(t *testing.T, serverFiles, clientFiles Files) {
+				writeFiles(t, filepath.Dir(serverFiles.CertFile), newTestCA(t), "server")
+			},
+			wantErr: true,
+		},
+		{
+			name: "both sides rotated to a new CA",
+			rotate: func(t *testing.T, serverFiles, clientFiles Files) {
+				ca := newTestCA(t)
+				writeFiles(t, filepath.Dir(serverFiles.CertFile), ca, "server")
+				writeFiles(t, filepath.Dir(clientFiles.CertFile), ca, "client")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ca := newTestCA(t)
+			serverFiles := writeFiles(t, t.TempDir(), ca, "server")
+			clientFiles := writeFiles(t, t.TempDir(), ca, "client")
+			options := Options{CheckInterval: 0}
+			serverReloader, err := NewReloader(serverFiles, options)
+			if err != nil {
+				t.Fatalf("could not create server reloader: %v", err)
+			}
+			clientReloader, err := NewReloader(clientFiles, options)
+			if err != nil {
+				t.Fatalf("could not create client reloader: %v", err)
+			}
+			tt.rotate(t, serverFiles, clientFiles)
+
+			// When
+			err = handshake(t, serverReloader.ServerConfig(), clientReloader.ClientCredentials("localhost"), "localhost:443")
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Given %s, When handshaking, Then expected error %v, got %v", tt.name, tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestReloaderOptionalFiles(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverCAFile  bool
+		clientKeyPair bool
+		clientVersion uint16
+		wantErr       bool
+	}{
+		{name: "server without a CA file accepts a client without a certificate"},
+		{name: "server with a CA file refuses a client without a certificate", serverCAFile: true, wantErr: true},
+		{name: "server with a CA file accepts a client certificate", serverCAFile: true, clientKeyPair: true},
+		{name: "TLS 1.3 server refuses a TLS 1.2 client", clientVersion: tls.VersionTLS12, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ca := newTestCA(t)
+			serverFiles := writeFiles(t, t.TempDir(), ca, "server")
+			clientFiles := writeFiles(t, t.TempDir(), ca, "client")
+			if !tt.serverCAFile {
+				serverFiles.CAFile = ""
+			}
+			if !tt.clientKeyPair {
+				clientFiles.CertFile, clientFiles.KeyFile = "", ""
+			}
+			serverReloader, err := NewReloader(serverFiles, Options{MinVersion: tls.VersionTLS13})
+			if err != nil {
+				t.Fatalf("could not create server reloader: %v", err)
+			}
+			clientReloader, err := NewReloader(clientFiles, Options{})
+			if err != nil {
+				t.Fatalf("could not create client reloader: %v", err)
+			}
+			clientConfig := clientReloader.ClientConfig("localhost")
+			if tt.clientVersion != 0 {
+				clientConfig.MaxVersion = tt.clientVersion
+			}
+
+			// When
+			err = handshake(t, serverReloader.ServerConfig(), credentials.NewTLS(clientConfig), "localhost:443")
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Given a %s, When handshaking, Then expected error %v, got %v", tt.name, tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestReloaderClientCredentialsVerifiesIPAddresses(t *testing.T) {
+	tests := []struct {
+		name        string
+		dnsNames    []string
+		ipAddresses []net.IP
+		serverName  string
+		wantErr     bool
+	}{
+		{name: "certificate for the dialled IP", ipAddresses: []net.IP{net.ParseIP("127.0.0.1")}},
+		{name: "certificate for another host", dnsNames: []string{"some.other.host"}, wantErr: true},
+		{name: "certificate for another IP", ipAddresses: []net.IP{net.ParseIP("10.0.0.1")}, wantErr: true},
+		{name: "configured IP server name", ipAddresses: []net.IP{net.ParseIP("10.0.0.1")}, serverName: "10.0.0.1"},
+		{name: "configured IP server name the certificate lacks", dnsNames: []string{"some.other.host"}, serverName: "127.0.0.1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ca := newTestCA(t)
+			dir := t.TempDir()
+			serverFiles := writeFiles(t, dir, ca, "server")
+			certPEM, keyPEM := ca.issueFor(t, "server", time.Now().AddDate(0, 3, 0), tt.dnsNames, tt.ipAddresses)
+			os.WriteFile(serverFiles.CertFile, certPEM, 0o600)
+			os.WriteFile(serverFiles.KeyFile, keyPEM, 0o600)
+			serverFiles.CAFile = ""
+			serverReloader, err := NewReloader(serverFiles, Options{})
+			if err != nil {
+				t.Fatalf("could not create server reloader: %v", err)
+			}
+			clientReloader, err := NewReloader(Files{CAFile: filepath.Join(dir, "ca-public.key")}, Options{})
+			if err != nil {
+				t.Fatalf("could not create client reloader: %v", err)
+			}
+
+			// When
+			err = handshake(t, serverReloader.ServerConfig(), clientReloader.ClientCredentials(tt.serverName), "127.0.0.1:443")
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Given a %s, When dialling 127.0.0.1, Then expected error %v, got %v", tt.name, tt.wantErr, err)
+			}
+		})
+	}
+}
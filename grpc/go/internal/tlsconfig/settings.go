@@ -0,0 +1,42 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+var versions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseVersion converts "1.2" or "1.3" to its crypto/tls constant; older versions are not offered
+func ParseVersion(name string) (uint16, error) {
+	version, ok := versions[name]
+	if !ok {
+		return 0, fmt.Errorf("TLS version %q is not supported, use 1.2 or 1.3", name)
+	}
+	return version, nil
+}
+
+// ParseCipherSuites converts IANA cipher suite names, such as TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+// to their IDs. Suites Go considers insecure are refused. An empty list returns nil, keeping Go's defaults.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	secure := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		secure[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := secure[name]
+		if !ok {
+			return nil, fmt.Errorf("cipher suite %q is unknown or insecure", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,62 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"slices"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected uint16
+		wantErr  bool
+	}{
+		{name: "1.2", expected: tls.VersionTLS12},
+		{name: "1.3", expected: tls.VersionTLS13},
+		{name: "1.0", wantErr: true},
+		{name: "TLS1.3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			version, err := ParseVersion(tt.name)
+
+			// Then
+			if (err != nil) != tt.wantErr || version != tt.expected {
+				t.Errorf("Given version %q, When parsing it, Then expected %x and error %v, got %x and %v", tt.name, tt.expected, tt.wantErr, version, err)
+			}
+		})
+	}
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    []string
+		expected []uint16
+		wantErr  bool
+	}{
+		{name: "no suites"},
+		{
+			name:     "secure suites",
+			names:    []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384", "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"},
+			expected: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256},
+		},
+		{name: "insecure suite", names: []string{"TLS_RSA_WITH_3DES_EDE_CBC_SHA"}, wantErr: true},
+		{name: "unknown suite", names: []string{"TLS_MADE_UP"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			ids, err := ParseCipherSuites(tt.names)
+
+			// Then
+			if (err != nil) != tt.wantErr || !slices.Equal(ids, tt.expected) {
+				t.Errorf("Given %s, When parsing them, Then expected %v and error %v, got %v and %v", tt.name, tt.expected, tt.wantErr, ids, err)
+			}
+		})
+	}
+}
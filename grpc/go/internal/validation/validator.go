@@ -1,11 +1,16 @@
 package validation
 
 import (
+	"crypto/tls"
+	"math"
 	"strings"
+	"time"
 	"unicode"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	"case-studies/grpc/internal/tlsconfig"
 )
 
 type ValidationError struct {
@@ -66,6 +71,41 @@ func ValidatePort(port int) error {
 	return nil
 }
 
+// ValidateMetricsPort allows 0 to disable the metrics listener; otherwise it must be a
+// valid port other than the one serving the API
+func ValidateMetricsPort(port, serverPort int) error {
+	if port == 0 {
+		return nil
+	}
+	if port < 1 || port > 65535 {
+		return status.Errorf(codes.InvalidArgument, "metrics port must be 0 or between 1 and 65535")
+	}
+	if port == serverPort {
+		return status.Errorf(codes.InvalidArgument, "metrics port must differ from the server port %d", serverPort)
+	}
+
+	return nil
+}
+
+// ValidateProbePort accepts 0, which serves the probes on the metrics port, or a port used by
+// neither the server nor the metrics listener
+func ValidateProbePort(port, serverPort, metricsPort int) error {
+	if port == 0 {
+		return nil
+	}
+	if port < 1 || port > 65535 {
+		return status.Errorf(codes.InvalidArgument, "probe port must be 0 or between 1 and 65535")
+	}
+	if port == serverPort {
+		return status.Errorf(codes.InvalidArgument, "probe port must differ from the server port %d", serverPort)
+	}
+	if port == metricsPort {
+		return status.Errorf(codes.InvalidArgument, "probe port must differ from the metrics port %d; leave it 0 to share that listener", metricsPort)
+	}
+
+	return nil
+}
+
 func ValidateHost(host string) error {
 	if host == "" {
 		return status.Errorf(codes.InvalidArgument, "host cannot be empty")
@@ -114,6 +154,81 @@ func ValidateMovieRatings(rating float32) error {
 	return nil
 }
 
+func ValidateMovieRatingsRange(minRating, maxRating float32) error {
+	if err := ValidateMovieRatings(minRating); err != nil {
+		return err
+	}
+	if err := ValidateMovieRatings(maxRating); err != nil {
+		return err
+	}
+	if minRating > maxRating {
+		return status.Errorf(codes.InvalidArgument, "minimum ratings cannot be greater than maximum ratings")
+	}
+	return nil
+}
+
+func ValidateReleaseDate(date string) error {
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		return status.Errorf(codes.InvalidArgument, "release date must be in YYYY-MM-DD format")
+	}
+	return nil
+}
+
+// ValidateReleaseDateRange checks optional inclusive bounds, where an empty string means unbounded
+func ValidateReleaseDateRange(after, before string) error {
+	if after != "" {
+		if err := ValidateReleaseDate(after); err != nil {
+			return err
+		}
+	}
+	if before != "" {
+		if err := ValidateReleaseDate(before); err != nil {
+			return err
+		}
+	}
+	if after != "" && before != "" && after > before {
+		return status.Errorf(codes.InvalidArgument, "released_after cannot be later than released_before")
+	}
+	return nil
+}
+
+func ValidatePageSize(pageSize int32) error {
+	if pageSize < 0 {
+		return status.Errorf(codes.InvalidArgument, "page size cannot be negative")
+	}
+	return nil
+}
+
+func ValidateMovieID(movieID string) error {
+	if movieID == "" {
+		return status.Errorf(codes.InvalidArgument, "movie ID cannot be empty")
+	}
+
+	if len(movieID) > 64 {
+		return status.Errorf(codes.InvalidArgument, "movie ID too long (max 64 characters)")
+	}
+
+	for _, r := range movieID {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return status.Errorf(codes.InvalidArgument, "movie ID contains invalid characters")
+		}
+	}
+
+	return nil
+}
+
+func ValidateBatchSize(size, maxSize int) error {
+	if size < 1 {
+		return status.Errorf(codes.InvalidArgument, "batch cannot be empty")
+	}
+
+	if size > maxSize {
+		return status.Errorf(codes.InvalidArgument, "batch too large (max %d items)", maxSize)
+	}
+
+	return nil
+}
+
 func ValidateAssetsFilePath(path string) error {
 	if path == "" {
 		return status.Errorf(codes.InvalidArgument, "file path cannot be empty")
@@ -148,3 +263,136 @@ func ValidateEnvironment(environment string) error {
 		return status.Errorf(codes.InvalidArgument, "environment must be one of: development, staging, production")
 	}
 }
+
+func ValidateMovieRepository(backend string) error {
+	switch backend {
+	case "json", "sqlite":
+		return nil
+	default:
+		return status.Errorf(codes.InvalidArgument, "movie repository must be one of: json, sqlite")
+	}
+}
+
+func ValidateMovieReloadInterval(interval time.Duration) error {
+	if interval < 0 {
+		return status.Errorf(codes.InvalidArgument, "movie reload interval cannot be negative")
+	}
+	if interval > 0 && interval < time.Second {
+		return status.Errorf(codes.InvalidArgument, "movie reload interval must be at least 1s")
+	}
+	return nil
+}
+
+func ValidateShutdownPeriods(drainPeriod, timeout time.Duration) error {
+	if drainPeriod < 0 {
+		return status.Errorf(codes.InvalidArgument, "shutdown drain period cannot be negative")
+	}
+	if timeout <= 0 {
+		return status.Errorf(codes.InvalidArgument, "shutdown timeout must be positive")
+	}
+	return nil
+}
+
+func ValidateTLSReload(reloadInterval, expiryWarning time.Duration) error {
+	if reloadInterval < time.Second {
+		return status.Errorf(codes.InvalidArgument, "TLS reload interval must be at least 1s")
+	}
+	if expiryWarning < 0 {
+		return status.Errorf(codes.InvalidArgument, "TLS expiry warning cannot be negative")
+	}
+	return nil
+}
+
+func ValidateTLSMode(mode string) error {
+	switch mode {
+	case "mtls", "tls", "insecure":
+		return nil
+	default:
+		return status.Errorf(codes.InvalidArgument, "TLS mode must be one of: mtls, tls, insecure")
+	}
+}
+
+func ValidateTLSProtocol(minVersion string, cipherSuites []string) error {
+	version, err := tlsconfig.ParseVersion(minVersion)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if _, err := tlsconfig.ParseCipherSuites(cipherSuites); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	// Go does not let TLS 1.3 suites be configured, so a list would silently do nothing
+	if len(cipherSuites) > 0 && version == tls.VersionTLS13 {
+		return status.Errorf(codes.InvalidArgument, "cipher suites only apply to TLS 1.2, set the minimum version to 1.2 or remove them")
+	}
+	return nil
+}
+
+func ValidateLogSampling(rates map[string]int) error {
+	for operation, every := range rates {
+		if operation == "" {
+			return status.Errorf(codes.InvalidArgument, "log sampling operation cannot be empty")
+		}
+		if every < 1 {
+			return status.Errorf(codes.InvalidArgument, "log sampling rate for %s must be at least 1", operation)
+		}
+	}
+	return nil
+}
+
+func ValidateETag(etag string) error {
+	if etag == "" {
+		return status.Errorf(codes.InvalidArgument, "etag cannot be empty")
+	}
+	if len(etag) > 64 {
+		return status.Errorf(codes.InvalidArgument, "etag too long (max 64 characters)")
+	}
+	return nil
+}
+
+// ValidateAPIKeyLimits checks an API key's rate limit, burst and daily quota, where zero leaves
+// that limit off. A burst needs a rate limit to refill it.
+func ValidateAPIKeyLimits(rateLimit float64, rateBurst int, dailyQuota int64) error {
+	if rateLimit < 0 || math.IsNaN(rateLimit) || math.IsInf(rateLimit, 0) {
+		return status.Errorf(codes.InvalidArgument, "rate limit must be a non-negative number")
+	}
+	if rateBurst < 0 {
+		return status.Errorf(codes.InvalidArgument, "rate burst cannot be negative")
+	}
+	if rateBurst > 0 && rateLimit == 0 {
+		return status.Errorf(codes.InvalidArgument, "rate burst requires a rate limit")
+	}
+	if dailyQuota < 0 {
+		return status.Errorf(codes.InvalidArgument, "daily quota cannot be negative")
+	}
+	return nil
+}
+
+// ValidateAPIKeyScopes accepts the scopes the server checks; an empty list grants the default
+func ValidateAPIKeyScopes(scopes []string) error {
+	for _, scope := range scopes {
+		switch scope {
+		case "movies:read", "movies:write", "server:admin":
+		default:
+			return status.Errorf(codes.InvalidArgument, "unknown scope %q, scopes must be one of: movies:read, movies:write, server:admin", scope)
+		}
+	}
+	return nil
+}
+
+func ValidateAuthMode(mode string) error {
+	switch mode {
+	case "api-key", "jwt", "both":
+		return nil
+	default:
+		return status.Errorf(codes.InvalidArgument, "auth mode must be one of: api-key, jwt, both")
+	}
+}
+
+func ValidateTracesExporter(exporter string) error {
+	switch exporter {
+	case "none", "otlp", "stdout":
+		return nil
+	default:
+		return status.Errorf(codes.InvalidArgument, "traces exporter must be one of: none, otlp, stdout")
+	}
+}
@@ -1,7 +1,10 @@
 package validation
 
 import (
+	"math"
+	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -109,6 +112,65 @@ func TestValidatePort(t *testing.T) {
 	}
 }
 
+func TestValidateMetricsPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		port    int
+		wantErr bool
+	}{
+		{"default port", 9090, false},
+		{"disabled", 0, false},
+		{"same as the server port", 50051, true},
+		{"port negative", -1, true},
+		{"port too large", 65536, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			port := tt.port
+
+			// When
+			err := ValidateMetricsPort(port, 50051)
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Given metrics port %d, When validated, Then expected error = %v, got %v", port, tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateProbePort(t *testing.T) {
+	tests := []struct {
+		name    string
+		port    int
+		wantErr bool
+	}{
+		{"own port", 8086, false},
+		{"shares the metrics listener", 0, false},
+		{"same as the server port", 50051, true},
+		{"same as the metrics port", 9090, true},
+		{"port negative", -1, true},
+		{"port too large", 65536, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			port := tt.port
+
+			// When
+			err := ValidateProbePort(port, 50051, 9090)
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Given probe port %d, When validated, Then expected error = %v, got %v", port, tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestValidateHost(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -192,6 +254,144 @@ func TestValidateMovieRatings(t *testing.T) {
 	}
 }
 
+func TestValidateMovieRatingsRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		minRating float32
+		maxRating float32
+		wantErr   bool
+	}{
+		{"full range", 0, 10, false},
+		{"equal bounds", 7.5, 7.5, false},
+		{"inverted range", 8, 6, true},
+		{"minimum out of bounds", -1, 5, true},
+		{"maximum out of bounds", 5, 11, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			minRating, maxRating := tt.minRating, tt.maxRating
+
+			// When
+			err := ValidateMovieRatingsRange(minRating, maxRating)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "ratings range "+tt.name)
+		})
+	}
+}
+
+func TestValidateReleaseDateRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		after   string
+		before  string
+		wantErr bool
+	}{
+		{"unbounded", "", "", false},
+		{"lower bound only", "2020-01-01", "", false},
+		{"upper bound only", "", "2020-12-31", false},
+		{"valid range", "2020-01-01", "2020-12-31", false},
+		{"same day", "2020-06-01", "2020-06-01", false},
+		{"inverted range", "2021-01-01", "2020-01-01", true},
+		{"invalid format", "01/01/2020", "", true},
+		{"invalid date", "", "2020-13-01", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			after, before := tt.after, tt.before
+
+			// When
+			err := ValidateReleaseDateRange(after, before)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "release date range "+tt.name)
+		})
+	}
+}
+
+func TestValidatePageSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		pageSize int32
+		wantErr  bool
+	}{
+		{"negative", -1, true},
+		{"unset", 0, false},
+		{"positive", 50, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			pageSize := tt.pageSize
+
+			// When
+			err := ValidatePageSize(pageSize)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "page size "+tt.name)
+		})
+	}
+}
+
+func TestValidateMovieID(t *testing.T) {
+	tests := []struct {
+		name    string
+		movieID string
+		wantErr bool
+	}{
+		{"valid ID", "tt1234567", false},
+		{"empty", "", true},
+		{"too long", strings.Repeat("a", 65), true},
+		{"contains space", "tt 123", true},
+		{"contains control character", "tt\x00123", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			movieID := tt.movieID
+
+			// When
+			err := ValidateMovieID(movieID)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "movie ID "+tt.name)
+		})
+	}
+}
+
+func TestValidateBatchSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		maxSize int
+		wantErr bool
+	}{
+		{"empty", 0, 100, true},
+		{"single", 1, 100, false},
+		{"at maximum", 100, 100, false},
+		{"above maximum", 101, 100, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			size := tt.size
+
+			// When
+			err := ValidateBatchSize(size, tt.maxSize)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "batch size "+tt.name)
+		})
+	}
+}
+
 func TestValidateAssetsFilePath(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -278,3 +478,321 @@ func TestValidateEnvironment(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMovieRepository(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		wantErr bool
+	}{
+		{"json", "json", false},
+		{"sqlite", "sqlite", false},
+		{"empty", "", true},
+		{"unknown", "mongodb", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			backend := tt.backend
+
+			// When
+			err := ValidateMovieRepository(backend)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "movie repository "+tt.name)
+		})
+	}
+}
+
+func TestValidateMovieReloadInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		wantErr  bool
+	}{
+		{"disabled", 0, false},
+		{"thirty seconds", 30 * time.Second, false},
+		{"too frequent", 100 * time.Millisecond, true},
+		{"negative", -time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			interval := tt.interval
+
+			// When
+			err := ValidateMovieReloadInterval(interval)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "movie reload interval "+tt.name)
+		})
+	}
+}
+
+func TestValidateShutdownPeriods(t *testing.T) {
+	tests := []struct {
+		name        string
+		drainPeriod time.Duration
+		timeout     time.Duration
+		wantErr     bool
+	}{
+		{"defaults", 5 * time.Second, 20 * time.Second, false},
+		{"no drain", 0, time.Second, false},
+		{"negative drain", -time.Second, time.Second, true},
+		{"no timeout", time.Second, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			err := ValidateShutdownPeriods(tt.drainPeriod, tt.timeout)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "shutdown periods "+tt.name)
+		})
+	}
+}
+
+func TestValidateTLSReload(t *testing.T) {
+	tests := []struct {
+		name           string
+		reloadInterval time.Duration
+		expiryWarning  time.Duration
+		wantErr        bool
+	}{
+		{"defaults", 30 * time.Second, 30 * 24 * time.Hour, false},
+		{"no expiry warning", time.Minute, 0, false},
+		{"no reload interval", 0, time.Hour, true},
+		{"sub-second reload interval", 100 * time.Millisecond, time.Hour, true},
+		{"negative expiry warning", time.Minute, -time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			err := ValidateTLSReload(tt.reloadInterval, tt.expiryWarning)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "TLS reload "+tt.name)
+		})
+	}
+}
+
+func TestValidateTLSMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{"mtls", false},
+		{"tls", false},
+		{"insecure", false},
+		{"", true},
+		{"MTLS", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			// When
+			err := ValidateTLSMode(tt.mode)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "TLS mode "+tt.mode)
+		})
+	}
+}
+
+func TestValidateTLSProtocol(t *testing.T) {
+	tests := []struct {
+		name         string
+		minVersion   string
+		cipherSuites []string
+		wantErr      bool
+	}{
+		{"TLS 1.2 with default suites", "1.2", nil, false},
+		{"TLS 1.3", "1.3", nil, false},
+		{"TLS 1.2 with chosen suites", "1.2", []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}, false},
+		{"TLS 1.1", "1.1", nil, true},
+		{"empty version", "", nil, true},
+		{"unknown suite", "1.2", []string{"TLS_MADE_UP"}, true},
+		{"insecure suite", "1.2", []string{"TLS_RSA_WITH_RC4_128_SHA"}, true},
+		{"suites with TLS 1.3", "1.3", []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			err := ValidateTLSProtocol(tt.minVersion, tt.cipherSuites)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "TLS protocol "+tt.name)
+		})
+	}
+}
+
+func TestValidateLogSampling(t *testing.T) {
+	tests := []struct {
+		name    string
+		rates   map[string]int
+		wantErr bool
+	}{
+		{"no sampling", nil, false},
+		{"one in ten", map[string]int{"movie-filter": 10}, false},
+		{"every success", map[string]int{"movie-filter": 1}, false},
+		{"zero rate", map[string]int{"movie-filter": 0}, true},
+		{"empty operation", map[string]int{"": 5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			rates := tt.rates
+
+			// When
+			err := ValidateLogSampling(rates)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "log sampling "+tt.name)
+		})
+	}
+}
+
+func TestValidateETag(t *testing.T) {
+	tests := []struct {
+		name    string
+		etag    string
+		wantErr bool
+	}{
+		{"valid etag", "3f2a9c0d1b4e5f60718293a4b5c6d7e8", false},
+		{"empty etag", "", true},
+		{"too long etag", strings.Repeat("a", 65), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			etag := tt.etag
+
+			// When
+			err := ValidateETag(etag)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "etag "+tt.name)
+		})
+	}
+}
+
+func TestValidateAPIKeyLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		rateLimit  float64
+		rateBurst  int
+		dailyQuota int64
+		wantErr    bool
+	}{
+		{"unlimited", 0, 0, 0, false},
+		{"rate with burst", 10, 20, 0, false},
+		{"quota only", 0, 0, 1000, false},
+		{"negative rate", -1, 0, 0, true},
+		{"infinite rate", math.Inf(1), 0, 0, true},
+		{"negative burst", 10, -1, 0, true},
+		{"burst without rate", 0, 5, 0, true},
+		{"negative quota", 0, 0, -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			rateLimit, rateBurst, dailyQuota := tt.rateLimit, tt.rateBurst, tt.dailyQuota
+
+			// When
+			err := ValidateAPIKeyLimits(rateLimit, rateBurst, dailyQuota)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "API key limits "+tt.name)
+		})
+	}
+}
+
+func TestValidateAPIKeyScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		wantErr bool
+	}{
+		{"no scopes", nil, false},
+		{"read", []string{"movies:read"}, false},
+		{"read and write", []string{"movies:read", "movies:write"}, false},
+		{"server admin", []string{"server:admin"}, false},
+		{"typo", []string{"movie:write"}, true},
+		{"known and unknown", []string{"movies:read", "admin"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			scopes := tt.scopes
+
+			// When
+			err := ValidateAPIKeyScopes(scopes)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "scopes "+tt.name)
+		})
+	}
+}
+
+func TestValidateAuthMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		wantErr bool
+	}{
+		{"api key", "api-key", false},
+		{"jwt", "jwt", false},
+		{"both", "both", false},
+		{"empty", "", true},
+		{"unknown", "oauth", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mode := tt.mode
+
+			// When
+			err := ValidateAuthMode(mode)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "auth mode "+tt.name)
+		})
+	}
+}
+
+func TestValidateTracesExporter(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		wantErr  bool
+	}{
+		{"none", "none", false},
+		{"otlp", "otlp", false},
+		{"stdout", "stdout", false},
+		{"empty", "", true},
+		{"unknown", "jaeger", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			exporter := tt.exporter
+
+			// When
+			err := ValidateTracesExporter(exporter)
+
+			// Then
+			assertValidationError(t, err, tt.wantErr, "traces exporter "+tt.name)
+		})
+	}
+}
@@ -1,18 +1,20 @@
 #!/bin/bash
 
 # Variables
-HEALTHCHECK_HOST=localhost
-HEALTHCHECK_PORT=50051
+HEALTHCHECK_HOST=${HEALTHCHECK_HOST:-localhost}
+HEALTHCHECK_PORT=${HEALTHCHECK_PORT:-50051}
 
-if ! command -v grpcurl >/dev/null 2>&1; then
-  echo "ERROR: grpcurl not installed - cannot check gRPC health"
+# Extra arguments go to the healthcheck command, e.g. -plaintext or -services liveness,readiness
+if ! go build -o bin/healthcheck ./cmd/healthcheck/; then
+  echo "ERROR: could not build the healthcheck command"
   exit 1
 fi
 
-if grpcurl -plaintext $HEALTHCHECK_HOST:$HEALTHCHECK_PORT grpc.health.v1.Health/Check >/dev/null 2>&1; then
+if ./bin/healthcheck -host "$HEALTHCHECK_HOST" -port "$HEALTHCHECK_PORT" "$@"; then
   echo "OK: gRPC health check passed"
   exit 0
 else
+  code=$?
   echo "ERROR: gRPC health check failed"
-  exit 1
+  exit $code
 fi